@@ -0,0 +1,159 @@
+package accesslog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/9seconds/mtg/v2/events"
+)
+
+// Opts defines a set of options for [NewFactory]. Only Path is
+// mandatory.
+type Opts struct {
+	// Path is an absolute path to the access log. Rotated files are
+	// placed nearby.
+	//
+	// This is a mandatory setting.
+	Path string
+
+	// Format is a format of records: either [FormatJSON] or
+	// [FormatLogfmt].
+	//
+	// This is an optional setting, [DefaultFormat] is used by default.
+	Format string
+
+	// MaxSize is a size of the file in bytes when it is rotated.
+	//
+	// This is an optional setting, [DefaultMaxSize] is used by default.
+	MaxSize uint
+
+	// RotateEach is an age of the file when it is rotated.
+	//
+	// This is an optional setting, [DefaultRotateEach] is used by
+	// default.
+	RotateEach time.Duration
+
+	// MaxBackups is a number of rotated files to keep.
+	//
+	// This is an optional setting, [DefaultMaxBackups] is used by
+	// default.
+	MaxBackups uint
+
+	// Logger is used to report errors on writing records.
+	//
+	// This is an optional setting, errors are ignored by default.
+	Logger Logger
+}
+
+func (o Opts) getFormat() string {
+	if o.Format == "" {
+		return DefaultFormat
+	}
+
+	return o.Format
+}
+
+func (o Opts) getMaxSize() int64 {
+	if o.MaxSize == 0 {
+		return DefaultMaxSize
+	}
+
+	return int64(o.MaxSize)
+}
+
+func (o Opts) getRotateEach() time.Duration {
+	if o.RotateEach == 0 {
+		return DefaultRotateEach
+	}
+
+	return o.RotateEach
+}
+
+func (o Opts) getMaxBackups() int {
+	if o.MaxBackups == 0 {
+		return DefaultMaxBackups
+	}
+
+	return int(o.MaxBackups)
+}
+
+func (o Opts) getLogger() Logger {
+	if o.Logger == nil {
+		return noopLogger{}
+	}
+
+	return o.Logger
+}
+
+type noopLogger struct{}
+
+func (n noopLogger) WarningError(_ string, _ error) {}
+
+type writer struct {
+	file   *rotator
+	encode func(Record) []byte
+	logger Logger
+}
+
+func (w writer) write(record *Record) {
+	if _, err := w.file.Write(w.encode(*record)); err != nil {
+		w.logger.WarningError("cannot write access log record", err)
+	}
+}
+
+// Factory is a factory of [events.Observer] which write access log.
+type Factory struct {
+	writer *writer
+}
+
+// Make builds a new observer.
+func (f Factory) Make() events.Observer {
+	return observer{
+		streams: map[string]*Record{},
+		writer:  f.writer,
+	}
+}
+
+// Close closes the access log. Observers write records of unfinished
+// streams on shutdown, so please close it after an event stream is
+// stopped. Records written after that are dropped.
+func (f Factory) Close() error {
+	return f.writer.file.Close()
+}
+
+// NewFactory builds an [events.ObserverFactory] that writes access log.
+func NewFactory(opts Opts) (Factory, error) {
+	if opts.Path == "" {
+		return Factory{}, ErrPathIsNotSet
+	}
+
+	var encode func(Record) []byte
+
+	switch opts.getFormat() {
+	case FormatJSON:
+		encode = Record.encodeJSON
+	case FormatLogfmt:
+		encode = Record.encodeLogfmt
+	default:
+		return Factory{}, fmt.Errorf("%w: %s", ErrUnknownFormat, opts.Format)
+	}
+
+	logger := opts.getLogger()
+
+	file, err := newRotator(logger,
+		opts.Path,
+		opts.getMaxSize(),
+		opts.getRotateEach(),
+		opts.getMaxBackups())
+	if err != nil {
+		return Factory{}, err
+	}
+
+	return Factory{
+		writer: &writer{
+			file:   file,
+			encode: encode,
+			logger: logger,
+		},
+	}, nil
+}
@@ -0,0 +1,125 @@
+package accesslog_test
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/accesslog"
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/stretchr/testify/suite"
+)
+
+type FactoryTestSuite struct {
+	suite.Suite
+
+	path string
+}
+
+func (suite *FactoryTestSuite) SetupTest() {
+	suite.path = filepath.Join(suite.T().TempDir(), "access.log")
+}
+
+func (suite *FactoryTestSuite) Make(format string) accesslog.Factory {
+	factory, err := accesslog.NewFactory(accesslog.Opts{
+		Path:   suite.path,
+		Format: format,
+	})
+	suite.Require().NoError(err)
+
+	return factory
+}
+
+func (suite *FactoryTestSuite) ReadLines() []string {
+	data, err := os.ReadFile(suite.path)
+	suite.Require().NoError(err)
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func (suite *FactoryTestSuite) TestNoPath() {
+	_, err := accesslog.NewFactory(accesslog.Opts{})
+	suite.ErrorIs(err, accesslog.ErrPathIsNotSet)
+}
+
+func (suite *FactoryTestSuite) TestUnknownFormat() {
+	_, err := accesslog.NewFactory(accesslog.Opts{
+		Path:   suite.path,
+		Format: "xml",
+	})
+	suite.ErrorIs(err, accesslog.ErrUnknownFormat)
+}
+
+func (suite *FactoryTestSuite) TestJSON() {
+	factory := suite.Make(accesslog.FormatJSON)
+	observer := factory.Make()
+
+	observer.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	observer.EventConnectedToDC(mtglib.NewEventConnectedToDC("connID", net.ParseIP("10.1.0.1"), 4))
+	observer.EventTraffic(mtglib.NewEventTraffic("connID", 200, true))
+	observer.EventTraffic(mtglib.NewEventTraffic("connID", 100, false))
+	observer.EventFinish(mtglib.NewEventFinish("connID"))
+	observer.Shutdown()
+	suite.NoError(factory.Close())
+
+	lines := suite.ReadLines()
+	suite.Require().Len(lines, 1)
+
+	record := map[string]any{}
+	suite.NoError(json.Unmarshal([]byte(lines[0]), &record))
+
+	suite.Equal("connID", record["streamId"])
+	suite.Equal("10.0.0.10", record["clientIp"])
+	suite.Equal("10.1.0.1", record["telegramIp"])
+	suite.InDelta(4, record["dc"], 0.1)
+	suite.InDelta(200, record["trafficToClient"], 0.1)
+	suite.InDelta(100, record["trafficFromClient"], 0.1)
+	suite.Equal(false, record["domainFronting"])
+	suite.Equal(false, record["replayAttack"])
+	suite.Contains(record, "startedAt")
+	suite.Contains(record, "duration")
+}
+
+func (suite *FactoryTestSuite) TestLogfmt() {
+	factory := suite.Make(accesslog.FormatLogfmt)
+	observer := factory.Make()
+
+	observer.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	observer.EventReplayAttack(mtglib.NewEventReplayAttack("connID"))
+	observer.EventDomainFronting(mtglib.NewEventDomainFronting("connID"))
+	observer.EventFinish(mtglib.NewEventFinish("connID"))
+	observer.Shutdown()
+	suite.NoError(factory.Close())
+
+	lines := suite.ReadLines()
+	suite.Require().Len(lines, 1)
+
+	suite.True(strings.HasPrefix(lines[0], "streamId=connID startedAt="))
+	suite.Contains(lines[0], " clientIp=10.0.0.10 ")
+	suite.Contains(lines[0], " domainFronting=true replayAttack=true")
+	suite.NotContains(lines[0], "telegramIp")
+}
+
+func (suite *FactoryTestSuite) TestShutdownWritesUnfinished() {
+	factory := suite.Make("")
+	observer := factory.Make()
+
+	observer.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	observer.EventStart(mtglib.NewEventStart("connID2", net.ParseIP("10.0.0.11")))
+	observer.EventFinish(mtglib.NewEventFinish("connID"))
+	observer.EventFinish(mtglib.NewEventFinish("unknown"))
+	observer.Shutdown()
+	suite.NoError(factory.Close())
+
+	lines := suite.ReadLines()
+	suite.Len(lines, 2)
+	suite.Contains(lines[1], "connID2")
+}
+
+func TestFactory(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &FactoryTestSuite{})
+}
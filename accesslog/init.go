@@ -0,0 +1,58 @@
+// Accesslog package has an implementation of [events.Observer] which
+// writes a single record per each finished stream.
+//
+// A record has a start time and duration of the stream, client and
+// Telegram addresses, amount of traffic in each direction and marks if
+// stream was domain fronted or detected as a replay attack. Records are
+// written in JSON lines or logfmt format into a file which is rotated
+// by size and age.
+//
+// Observers rely on a guarantee of [events.EventStream]: all events of
+// the same stream are delivered to the same observer, so each observer
+// keeps its own set of streams and no locking is required.
+package accesslog
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// FormatJSON defines JSON lines format of records.
+	FormatJSON = "json"
+
+	// FormatLogfmt defines logfmt format of records.
+	FormatLogfmt = "logfmt"
+
+	// DefaultFormat defines a default format of records.
+	DefaultFormat = FormatJSON
+
+	// DefaultMaxSize defines a default size of the file when it is
+	// rotated.
+	DefaultMaxSize = 100 * 1024 * 1024
+
+	// DefaultRotateEach defines a default age of the file when it is
+	// rotated.
+	DefaultRotateEach = 24 * time.Hour
+
+	// DefaultMaxBackups defines a default number of rotated files to
+	// keep.
+	DefaultMaxBackups = 7
+
+	// backupTimeFormat defines a suffix of rotated files. Lexicographical
+	// order of these suffixes is chronological.
+	backupTimeFormat = "20060102T150405.000000000"
+)
+
+var (
+	// ErrUnknownFormat is returned if format of records is unknown.
+	ErrUnknownFormat = errors.New("unknown format")
+
+	// ErrPathIsNotSet is returned if path to the access log is empty.
+	ErrPathIsNotSet = errors.New("path is not set")
+)
+
+// Logger defines a logger which is used to report write errors.
+type Logger interface {
+	WarningError(msg string, err error)
+}
@@ -0,0 +1,95 @@
+package accesslog
+
+import (
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+type observer struct {
+	streams map[string]*Record
+	writer  *writer
+}
+
+func (o observer) EventStart(evt mtglib.EventStart) {
+	o.streams[evt.StreamID()] = &Record{
+		StreamID:  evt.StreamID(),
+		StartedAt: evt.Timestamp(),
+		ClientIP:  evt.RemoteIP,
+	}
+}
+
+func (o observer) EventFinish(evt mtglib.EventFinish) {
+	record, ok := o.streams[evt.StreamID()]
+	if !ok {
+		return
+	}
+
+	delete(o.streams, evt.StreamID())
+
+	record.Duration = evt.Timestamp().Sub(record.StartedAt)
+	o.writer.write(record)
+}
+
+func (o observer) EventConnectedToDC(evt mtglib.EventConnectedToDC) {
+	if record, ok := o.streams[evt.StreamID()]; ok {
+		record.SecretName = evt.SecretName()
+		record.DC = evt.DC
+		record.TelegramIP = evt.RemoteIP
+	}
+}
+
+func (o observer) EventDomainFronting(evt mtglib.EventDomainFronting) {
+	if record, ok := o.streams[evt.StreamID()]; ok {
+		record.DomainFronting = true
+	}
+}
+
+func (o observer) EventTraffic(evt mtglib.EventTraffic) {
+	record, ok := o.streams[evt.StreamID()]
+	if !ok {
+		return
+	}
+
+	if evt.SecretName() != "" {
+		record.SecretName = evt.SecretName()
+	}
+
+	if evt.IsRead {
+		record.TrafficToClient += evt.Traffic
+	} else {
+		record.TrafficFromClient += evt.Traffic
+	}
+}
+
+func (o observer) EventReplayAttack(evt mtglib.EventReplayAttack) {
+	if record, ok := o.streams[evt.StreamID()]; ok {
+		record.ReplayAttack = true
+	}
+}
+
+func (o observer) EventConcurrencyLimited(_ mtglib.EventConcurrencyLimited) {}
+func (o observer) EventIPBlocklisted(_ mtglib.EventIPBlocklisted)           {}
+func (o observer) EventPreviousSecretUsed(_ mtglib.EventPreviousSecretUsed) {}
+func (o observer) EventIPListSize(_ mtglib.EventIPListSize)                 {}
+func (o observer) EventTelegramProbe(_ mtglib.EventTelegramProbe)           {}
+func (o observer) EventWarmPool(_ mtglib.EventWarmPool)                     {}
+func (o observer) EventHandshakeFailed(_ mtglib.EventHandshakeFailed)       {}
+func (o observer) EventHandshakeSucceeded(_ mtglib.EventHandshakeSucceeded) {}
+func (o observer) EventOverflow(_ mtglib.EventOverflow)                     {}
+func (o observer) EventRateLimited(_ mtglib.EventRateLimited)               {}
+func (o observer) EventIPBanned(_ mtglib.EventIPBanned)                     {}
+func (o observer) EventClockSkew(_ mtglib.EventClockSkew)                   {}
+
+// Shutdown writes records of streams which are not finished yet. Their
+// duration is counted up to now.
+func (o observer) Shutdown() {
+	now := time.Now()
+
+	for streamID, record := range o.streams {
+		record.Duration = now.Sub(record.StartedAt)
+		o.writer.write(record)
+
+		delete(o.streams, streamID)
+	}
+}
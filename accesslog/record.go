@@ -0,0 +1,113 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is a description of a finished stream.
+type Record struct {
+	StreamID          string
+	StartedAt         time.Time
+	Duration          time.Duration
+	ClientIP          net.IP
+	SecretName        string
+	DC                int
+	TelegramIP        net.IP
+	TrafficToClient   uint
+	TrafficFromClient uint
+	DomainFronting    bool
+	ReplayAttack      bool
+}
+
+type recordJSON struct {
+	StreamID          string  `json:"streamId"`
+	StartedAt         string  `json:"startedAt"`
+	Duration          float64 `json:"duration"`
+	ClientIP          string  `json:"clientIp"`
+	SecretName        string  `json:"secret,omitempty"`
+	DC                int     `json:"dc,omitempty"`
+	TelegramIP        string  `json:"telegramIp,omitempty"`
+	TrafficToClient   uint    `json:"trafficToClient"`
+	TrafficFromClient uint    `json:"trafficFromClient"`
+	DomainFronting    bool    `json:"domainFronting"`
+	ReplayAttack      bool    `json:"replayAttack"`
+}
+
+func (r Record) encodeJSON() []byte {
+	value := recordJSON{
+		StreamID:          r.StreamID,
+		StartedAt:         r.StartedAt.UTC().Format(time.RFC3339Nano),
+		Duration:          r.Duration.Seconds(),
+		ClientIP:          ipToString(r.ClientIP),
+		SecretName:        r.SecretName,
+		DC:                r.DC,
+		TelegramIP:        ipToString(r.TelegramIP),
+		TrafficToClient:   r.TrafficToClient,
+		TrafficFromClient: r.TrafficFromClient,
+		DomainFronting:    r.DomainFronting,
+		ReplayAttack:      r.ReplayAttack,
+	}
+
+	// this struct has only primitive types so it is always encoded
+	encoded, _ := json.Marshal(value) //nolint: errchkjson
+
+	return append(encoded, '\n')
+}
+
+// encodeLogfmt uses the same keys as JSON format. Empty values are
+// skipped.
+func (r Record) encodeLogfmt() []byte {
+	builder := strings.Builder{}
+
+	writePair := func(key, value string) {
+		if value == "" {
+			return
+		}
+
+		if builder.Len() > 0 {
+			builder.WriteByte(' ')
+		}
+
+		builder.WriteString(key)
+		builder.WriteByte('=')
+
+		if strings.ContainsAny(value, " =\"\\") || !strconv.CanBackquote(value) {
+			value = strconv.Quote(value)
+		}
+
+		builder.WriteString(value)
+	}
+
+	dc := ""
+	if r.DC != 0 {
+		dc = strconv.Itoa(r.DC)
+	}
+
+	writePair("streamId", r.StreamID)
+	writePair("startedAt", r.StartedAt.UTC().Format(time.RFC3339Nano))
+	writePair("duration", strconv.FormatFloat(r.Duration.Seconds(), 'f', -1, 64))
+	writePair("clientIp", ipToString(r.ClientIP))
+	writePair("secret", r.SecretName)
+	writePair("dc", dc)
+	writePair("telegramIp", ipToString(r.TelegramIP))
+	writePair("trafficToClient", strconv.FormatUint(uint64(r.TrafficToClient), 10))
+	writePair("trafficFromClient", strconv.FormatUint(uint64(r.TrafficFromClient), 10))
+	writePair("domainFronting", strconv.FormatBool(r.DomainFronting))
+	writePair("replayAttack", strconv.FormatBool(r.ReplayAttack))
+
+	builder.WriteByte('\n')
+
+	return []byte(builder.String())
+}
+
+func ipToString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
@@ -0,0 +1,171 @@
+package accesslog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotator is a file which is rotated when it grows larger than maxSize
+// or older than rotateEach. Rotated files get a timestamp suffix, only
+// maxBackups of them are kept.
+type rotator struct {
+	mutex      sync.Mutex
+	file       *os.File
+	closed     bool
+	size       int64
+	openedAt   time.Time
+	path       string
+	maxSize    int64
+	rotateEach time.Duration
+	maxBackups int
+	logger     Logger
+}
+
+func (r *rotator) Write(data []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+
+	// a file could be lost if previous rotation has failed.
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.shouldRotate(len(data)) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(data)
+	r.size += int64(n)
+
+	return n, err //nolint: wrapcheck
+}
+
+func (r *rotator) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed || r.file == nil {
+		r.closed = true
+
+		return nil
+	}
+
+	r.closed = true
+	err := r.file.Close()
+	r.file = nil
+
+	return err //nolint: wrapcheck
+}
+
+func (r *rotator) shouldRotate(size int) bool {
+	if r.size == 0 {
+		return false
+	}
+
+	return r.size+int64(size) > r.maxSize || time.Since(r.openedAt) >= r.rotateEach
+}
+
+func (r *rotator) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("cannot close access log: %w", err)
+	}
+
+	r.file = nil
+
+	backupPath := r.path + "." + time.Now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(r.path, backupPath); err != nil {
+		return fmt.Errorf("cannot rename access log: %w", err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	// old backups are not a reason to stop writing
+	if err := r.removeBackups(); err != nil {
+		r.logger.WarningError("cannot remove old access logs", err)
+	}
+
+	return nil
+}
+
+func (r *rotator) open() error {
+	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640) //nolint: mnd
+	if err != nil {
+		return fmt.Errorf("cannot open access log: %w", err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close() //nolint: errcheck
+
+		return fmt.Errorf("cannot stat access log: %w", err)
+	}
+
+	r.file = file
+	r.size = stat.Size()
+	r.openedAt = time.Now()
+
+	return nil
+}
+
+func (r *rotator) removeBackups() error {
+	backups, err := filepath.Glob(r.path + ".*")
+	if err != nil {
+		return fmt.Errorf("cannot list rotated access logs: %w", err)
+	}
+
+	backups = slices.DeleteFunc(backups, func(path string) bool {
+		_, err := time.Parse(backupTimeFormat, strings.TrimPrefix(path, r.path+"."))
+
+		return err != nil
+	})
+
+	if len(backups) <= r.maxBackups {
+		return nil
+	}
+
+	slices.Sort(backups)
+
+	for _, path := range backups[:len(backups)-r.maxBackups] {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("cannot remove rotated access log: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func newRotator(logger Logger,
+	path string,
+	maxSize int64,
+	rotateEach time.Duration,
+	maxBackups int,
+) (*rotator, error) {
+	rv := &rotator{
+		path:       path,
+		maxSize:    maxSize,
+		rotateEach: rotateEach,
+		maxBackups: maxBackups,
+		logger:     logger,
+	}
+
+	if err := rv.open(); err != nil {
+		return nil, err
+	}
+
+	return rv, nil
+}
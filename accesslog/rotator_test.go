@@ -0,0 +1,104 @@
+package accesslog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RotatorTestSuite struct {
+	suite.Suite
+
+	dir  string
+	path string
+}
+
+func (suite *RotatorTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+	suite.path = filepath.Join(suite.dir, "access.log")
+}
+
+func (suite *RotatorTestSuite) Backups() []string {
+	backups, err := filepath.Glob(suite.path + ".*")
+	suite.Require().NoError(err)
+
+	return backups
+}
+
+func (suite *RotatorTestSuite) TestRotateBySize() {
+	file, err := newRotator(noopLogger{}, suite.path, 10, time.Hour, 10)
+	suite.Require().NoError(err)
+
+	defer file.Close() //nolint: errcheck
+
+	for range 3 {
+		_, err := file.Write([]byte("12345678\n"))
+		suite.NoError(err)
+	}
+
+	suite.Len(suite.Backups(), 2)
+
+	data, err := os.ReadFile(suite.path)
+	suite.NoError(err)
+	suite.Equal("12345678\n", string(data))
+}
+
+func (suite *RotatorTestSuite) TestRotateByAge() {
+	file, err := newRotator(noopLogger{}, suite.path, 1024, 10*time.Millisecond, 10)
+	suite.Require().NoError(err)
+
+	defer file.Close() //nolint: errcheck
+
+	_, err = file.Write([]byte("1\n"))
+	suite.NoError(err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = file.Write([]byte("2\n"))
+	suite.NoError(err)
+
+	suite.Len(suite.Backups(), 1)
+}
+
+func (suite *RotatorTestSuite) TestKeepBackups() {
+	suite.NoError(os.WriteFile(suite.path+".unrelated", nil, 0o600))
+
+	file, err := newRotator(noopLogger{}, suite.path, 1, time.Hour, 2)
+	suite.Require().NoError(err)
+
+	defer file.Close() //nolint: errcheck
+
+	for range 5 {
+		_, err := file.Write([]byte("1\n"))
+		suite.NoError(err)
+	}
+
+	suite.Len(suite.Backups(), 3)
+	suite.FileExists(suite.path + ".unrelated")
+}
+
+func (suite *RotatorTestSuite) TestAppendToExisting() {
+	suite.NoError(os.WriteFile(suite.path, []byte("1\n"), 0o600))
+
+	file, err := newRotator(noopLogger{}, suite.path, 1024, time.Hour, 2)
+	suite.Require().NoError(err)
+
+	_, err = file.Write([]byte("2\n"))
+	suite.NoError(err)
+	suite.NoError(file.Close())
+
+	_, err = file.Write([]byte("3\n"))
+	suite.ErrorIs(err, os.ErrClosed)
+
+	data, err := os.ReadFile(suite.path)
+	suite.NoError(err)
+	suite.Equal("1\n2\n", string(data))
+}
+
+func TestRotator(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &RotatorTestSuite{})
+}
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+)
+
+// Client is a client of admin API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// Streams returns a list of active streams, the oldest first.
+func (c *Client) Streams(ctx context.Context) ([]Stream, error) {
+	streams := []Stream{}
+
+	if err := c.get(ctx, "/streams", &streams); err != nil {
+		return nil, err
+	}
+
+	return streams, nil
+}
+
+// Counters returns cumulative counters of events.
+func (c *Client) Counters(ctx context.Context) (Counters, error) {
+	counters := Counters{}
+
+	if err := c.get(ctx, "/counters", &counters); err != nil {
+		return Counters{}, err
+	}
+
+	return counters, nil
+}
+
+// IPLists returns sizes of ip lists.
+func (c *Client) IPLists(ctx context.Context) (IPLists, error) {
+	lists := IPLists{}
+
+	if err := c.get(ctx, "/iplists", &lists); err != nil {
+		return IPLists{}, err
+	}
+
+	return lists, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, value any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("cannot build a request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot send a request: %w", err)
+	}
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(value); err != nil {
+		return fmt.Errorf("cannot decode a response: %w", err)
+	}
+
+	return nil
+}
+
+// NewClient builds a new client of admin API. An address is either
+// host:port or an absolute path to unix socket.
+func NewClient(address string) *Client {
+	if !filepath.IsAbs(address) {
+		return &Client{
+			httpClient: &http.Client{
+				Timeout: ClientTimeout,
+			},
+			baseURL: "http://" + address,
+		}
+	}
+
+	dialer := net.Dialer{}
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: ClientTimeout,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, "unix", address)
+				},
+			},
+		},
+		baseURL: "http://unix",
+	}
+}
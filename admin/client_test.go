@@ -0,0 +1,85 @@
+package admin_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/admin"
+	"github.com/9seconds/mtg/v2/events"
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+
+	tcpListener  net.Listener
+	unixListener net.Listener
+	server       *admin.Server
+	observer     events.Observer
+}
+
+func (suite *ClientTestSuite) SetupTest() {
+	var err error
+
+	suite.tcpListener, err = net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	suite.unixListener, err = net.Listen("unix", filepath.Join(suite.T().TempDir(), "admin.sock"))
+	suite.Require().NoError(err)
+
+	suite.server = admin.NewServer("")
+	suite.observer = suite.server.Make()
+
+	go suite.server.Serve(suite.tcpListener)  //nolint: errcheck
+	go suite.server.Serve(suite.unixListener) //nolint: errcheck
+
+	suite.observer.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	suite.observer.EventIPListSize(mtglib.NewEventIPListSize(10, true))
+}
+
+func (suite *ClientTestSuite) TearDownTest() {
+	suite.observer.Shutdown()
+	suite.NoError(suite.server.Close())
+}
+
+func (suite *ClientTestSuite) Check(client *admin.Client) {
+	streams, err := client.Streams(context.Background())
+	suite.NoError(err)
+	suite.Len(streams, 1)
+
+	counters, err := client.Counters(context.Background())
+	suite.NoError(err)
+	suite.EqualValues(1, counters.Streams)
+
+	lists, err := client.IPLists(context.Background())
+	suite.NoError(err)
+	suite.Equal(10, lists.Blocklist)
+}
+
+func (suite *ClientTestSuite) TestTCP() {
+	suite.Check(admin.NewClient(suite.tcpListener.Addr().String()))
+}
+
+func (suite *ClientTestSuite) TestUnixSocket() {
+	suite.Check(admin.NewClient(suite.unixListener.Addr().String()))
+}
+
+func (suite *ClientTestSuite) TestUnexpectedStatus() {
+	httpServer := httptest.NewServer(http.NotFoundHandler())
+	defer httpServer.Close()
+
+	client := admin.NewClient(httpServer.Listener.Addr().String())
+
+	_, err := client.Streams(context.Background())
+	suite.ErrorIs(err, admin.ErrUnexpectedStatus)
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &ClientTestSuite{})
+}
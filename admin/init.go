@@ -0,0 +1,57 @@
+// Admin package has an HTTP API to inspect and control a running proxy.
+//
+// Active streams are tracked by [events.Observer], so an instance of
+// [Server] has to be attached to an event stream as an observer factory.
+// Endpoints are:
+//
+//	GET    /streams       - a list of active streams
+//	GET    /streams/{id}  - a stream with a given id
+//	DELETE /streams/{id}  - close a stream with a given id
+//	GET    /iplists       - sizes of ip blocklist and allowlist
+//	GET    /counters      - cumulative counters of events
+//	GET    /doppelganger  - a state of doppelganger
+//
+// The same server can serve several listeners, for example, TCP and
+// unix socket ones. [Client] can talk to both.
+//
+// Read-only endpoints have no authentication, so please bind it to a
+// loopback interface or protect it with a firewall. Endpoints which
+// change a state of a proxy require a bearer token unless a request
+// comes via unix socket.
+package admin
+
+import (
+	"errors"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+const (
+	// ClientTimeout is a timeout of a single request done by [Client].
+	ClientTimeout = 10 * time.Second
+
+	// ReadHeaderTimeout is a time given to a client of admin API to send
+	// request headers.
+	ReadHeaderTimeout = 10 * time.Second
+)
+
+var (
+	// ErrProxyIsNotSet is returned when an endpoint requires a proxy but
+	// it is not set yet.
+	ErrProxyIsNotSet = errors.New("proxy is not set")
+
+	// ErrUnexpectedStatus is returned by [Client] if admin API responds
+	// with unexpected status code.
+	ErrUnexpectedStatus = errors.New("unexpected status code")
+
+	// ErrTokenIsNotSet is returned by endpoints which change a state of
+	// a proxy if they are requested via TCP but a token is not set.
+	ErrTokenIsNotSet = errors.New("token is not set, this endpoint is available only via unix socket")
+)
+
+// Proxy is a part of [mtglib.Proxy] which is used by admin API.
+type Proxy interface {
+	CloseStream(streamID string) bool
+	DoppelGangerState() mtglib.DoppelGangerState
+}
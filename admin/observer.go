@@ -0,0 +1,105 @@
+package admin
+
+import "github.com/9seconds/mtg/v2/mtglib"
+
+type observer struct {
+	store *store
+}
+
+func (o observer) EventStart(evt mtglib.EventStart) {
+	o.store.add(&Stream{
+		ID:        evt.StreamID(),
+		ClientIP:  evt.RemoteIP,
+		StartedAt: evt.Timestamp(),
+	})
+}
+
+func (o observer) EventFinish(evt mtglib.EventFinish) {
+	o.store.remove(evt.StreamID())
+}
+
+func (o observer) EventConnectedToDC(evt mtglib.EventConnectedToDC) {
+	o.store.update(evt.StreamID(), func(stream *Stream) {
+		stream.SecretName = evt.SecretName()
+		stream.DC = evt.DC
+		stream.TelegramIP = evt.RemoteIP
+	})
+	o.store.count(func(counters *Counters) {
+		counters.ConnectedToDC++
+	})
+}
+
+func (o observer) EventDomainFronting(evt mtglib.EventDomainFronting) {
+	o.store.update(evt.StreamID(), func(stream *Stream) {
+		stream.DomainFronting = true
+	})
+	o.store.count(func(counters *Counters) {
+		counters.DomainFronting++
+	})
+}
+
+func (o observer) EventTraffic(evt mtglib.EventTraffic) {
+	o.store.update(evt.StreamID(), func(stream *Stream) {
+		if evt.SecretName() != "" {
+			stream.SecretName = evt.SecretName()
+		}
+
+		if evt.IsRead {
+			stream.TrafficToClient += evt.Traffic
+		} else {
+			stream.TrafficFromClient += evt.Traffic
+		}
+	})
+}
+
+func (o observer) EventIPListSize(evt mtglib.EventIPListSize) {
+	o.store.setIPListSize(evt)
+}
+
+func (o observer) EventConcurrencyLimited(_ mtglib.EventConcurrencyLimited) {
+	o.store.count(func(counters *Counters) {
+		counters.ConcurrencyLimited++
+	})
+}
+
+func (o observer) EventIPBlocklisted(_ mtglib.EventIPBlocklisted) {
+	o.store.count(func(counters *Counters) {
+		counters.Rejected++
+	})
+}
+
+func (o observer) EventReplayAttack(_ mtglib.EventReplayAttack) {
+	o.store.count(func(counters *Counters) {
+		counters.ReplayAttacks++
+	})
+}
+
+func (o observer) EventRateLimited(_ mtglib.EventRateLimited) {
+	o.store.count(func(counters *Counters) {
+		counters.RateLimited++
+	})
+}
+
+func (o observer) EventIPBanned(_ mtglib.EventIPBanned) {
+	o.store.count(func(counters *Counters) {
+		counters.Banned++
+	})
+}
+
+func (o observer) EventPreviousSecretUsed(_ mtglib.EventPreviousSecretUsed) {}
+func (o observer) EventTelegramProbe(_ mtglib.EventTelegramProbe)           {}
+func (o observer) EventWarmPool(_ mtglib.EventWarmPool)                     {}
+func (o observer) EventHandshakeSucceeded(_ mtglib.EventHandshakeSucceeded) {}
+func (o observer) EventOverflow(_ mtglib.EventOverflow)                     {}
+func (o observer) EventClockSkew(_ mtglib.EventClockSkew)                   {}
+
+func (o observer) EventHandshakeFailed(evt mtglib.EventHandshakeFailed) {
+	o.store.count(func(counters *Counters) {
+		counters.HandshakeFailures++
+		counters.HandshakeFailureReasons[evt.Reason]++
+	})
+}
+
+// Shutdown does nothing: streams are kept by the server, so they
+// survive a replacement of the event stream.
+func (o observer) Shutdown() {}
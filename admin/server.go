@@ -0,0 +1,194 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/9seconds/mtg/v2/events"
+)
+
+// Server is an admin HTTP API. It is also a factory of [events.Observer]
+// which track active streams.
+type Server struct {
+	httpServer *http.Server
+	store      *store
+	token      string
+
+	proxyMutex sync.RWMutex
+	proxy      Proxy
+}
+
+// Make builds a new observer.
+func (s *Server) Make() events.Observer {
+	return observer{
+		store: s.store,
+	}
+}
+
+// SetProxy sets a proxy to control. Until it is set, endpoints which
+// need a proxy respond with 503.
+func (s *Server) SetProxy(proxy Proxy) {
+	s.proxyMutex.Lock()
+	defer s.proxyMutex.Unlock()
+
+	s.proxy = proxy
+}
+
+// Serve starts an HTTP server on a given listener.
+func (s *Server) Serve(listener net.Listener) error {
+	return s.httpServer.Serve(listener) //nolint: wrapcheck
+}
+
+// Close stops a server. Please pay attention that underlying listener
+// is not closed.
+func (s *Server) Close() error {
+	return s.httpServer.Shutdown(context.Background()) //nolint: wrapcheck
+}
+
+func (s *Server) getProxy() Proxy {
+	s.proxyMutex.RLock()
+	defer s.proxyMutex.RUnlock()
+
+	return s.proxy
+}
+
+// authorized guards endpoints which change a state of a proxy. Requests
+// which come via unix socket are trusted: access to it is controlled
+// by file permissions. Others have to present a bearer token. If token
+// is not set, these endpoints are available only via unix socket.
+func (s *Server) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok && addr.Network() == "unix" {
+			handler(w, r)
+			return
+		}
+
+		if s.token == "" {
+			writeError(w, http.StatusForbidden, ErrTokenIsNotSet.Error())
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "incorrect bearer token")
+
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleListStreams(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.store.list())
+}
+
+func (s *Server) handleGetStream(w http.ResponseWriter, r *http.Request) {
+	stream, ok := s.store.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "stream is not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stream)
+}
+
+func (s *Server) handleCloseStream(w http.ResponseWriter, r *http.Request) {
+	proxy := s.getProxy()
+	if proxy == nil {
+		writeError(w, http.StatusServiceUnavailable, ErrProxyIsNotSet.Error())
+		return
+	}
+
+	if !proxy.CloseStream(r.PathValue("id")) {
+		writeError(w, http.StatusNotFound, "stream is not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleIPLists(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.store.getIPLists())
+}
+
+func (s *Server) handleCounters(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.store.getCounters())
+}
+
+func (s *Server) handleDoppelGanger(w http.ResponseWriter, _ *http.Request) {
+	proxy := s.getProxy()
+	if proxy == nil {
+		writeError(w, http.StatusServiceUnavailable, ErrProxyIsNotSet.Error())
+		return
+	}
+
+	state := proxy.DoppelGangerState()
+
+	writeJSON(w, http.StatusOK, struct {
+		URLs               []string `json:"urls"`
+		DRS                bool     `json:"drs"`
+		CollectedDurations int      `json:"collectedDurations"`
+		CollectedCertSizes int      `json:"collectedCertSizes"`
+		Calibrated         bool     `json:"calibrated"`
+		WeibullK           float64  `json:"weibullK"`
+		WeibullLambda      float64  `json:"weibullLambda"`
+		NoiseMean          int      `json:"noiseMean"`
+		NoiseJitter        int      `json:"noiseJitter"`
+	}{
+		URLs:               state.URLs,
+		DRS:                state.DRS,
+		CollectedDurations: state.CollectedDurations,
+		CollectedCertSizes: state.CollectedCertSizes,
+		Calibrated:         state.Calibrated,
+		WeibullK:           state.WeibullK,
+		WeibullLambda:      state.WeibullLambda,
+		NoiseMean:          state.NoiseMean,
+		NoiseJitter:        state.NoiseJitter,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(value) //nolint: errcheck, errchkjson
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{
+		Error: message,
+	})
+}
+
+// NewServer builds a new admin API server. token is a bearer token
+// which is required to change a state of a proxy via TCP listeners. If
+// it is empty, this is possible only via unix socket.
+func NewServer(token string) *Server {
+	mux := http.NewServeMux()
+	server := &Server{
+		httpServer: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: ReadHeaderTimeout,
+		},
+		store: newStore(),
+		token: token,
+	}
+
+	mux.HandleFunc("GET /streams", server.handleListStreams)
+	mux.HandleFunc("GET /streams/{id}", server.handleGetStream)
+	mux.HandleFunc("DELETE /streams/{id}", server.authorized(server.handleCloseStream))
+	mux.HandleFunc("GET /iplists", server.handleIPLists)
+	mux.HandleFunc("GET /counters", server.handleCounters)
+	mux.HandleFunc("GET /doppelganger", server.handleDoppelGanger)
+
+	return server
+}
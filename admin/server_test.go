@@ -0,0 +1,257 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/admin"
+	"github.com/9seconds/mtg/v2/events"
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProxyMock struct {
+	mock.Mock
+}
+
+func (m *ProxyMock) CloseStream(streamID string) bool {
+	return m.Called(streamID).Bool(0)
+}
+
+func (m *ProxyMock) DoppelGangerState() mtglib.DoppelGangerState {
+	return m.Called().Get(0).(mtglib.DoppelGangerState) //nolint: forcetypeassert
+}
+
+type ServerTestSuite struct {
+	suite.Suite
+
+	httpListener net.Listener
+	server       *admin.Server
+	observer     events.Observer
+	proxyMock    *ProxyMock
+}
+
+func (suite *ServerTestSuite) Do(method, path string, value any) int {
+	return suite.DoWithToken(method, path, "", value)
+}
+
+func (suite *ServerTestSuite) DoWithToken(method, path, token string, value any) int {
+	addr := fmt.Sprintf("http://%s%s", suite.httpListener.Addr().String(), path)
+
+	req, err := http.NewRequest(method, addr, nil) //nolint: noctx
+	suite.Require().NoError(err)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	if value != nil {
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(value))
+	}
+
+	return resp.StatusCode
+}
+
+func (suite *ServerTestSuite) SetupTest() {
+	suite.httpListener, _ = net.Listen("tcp", "127.0.0.1:0")
+	suite.server = admin.NewServer("s3cr3t")
+	suite.observer = suite.server.Make()
+	suite.proxyMock = &ProxyMock{}
+
+	go suite.server.Serve(suite.httpListener) //nolint: errcheck
+}
+
+func (suite *ServerTestSuite) TearDownTest() {
+	suite.observer.Shutdown()
+	suite.NoError(suite.server.Close())
+	suite.httpListener.Close() //nolint: errcheck
+
+	suite.proxyMock.AssertExpectations(suite.T())
+}
+
+func (suite *ServerTestSuite) TestStreams() {
+	suite.observer.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	suite.observer.EventConnectedToDC(mtglib.NewEventConnectedToDC("connID", net.ParseIP("10.1.0.1"), 4))
+	suite.observer.EventTraffic(mtglib.NewEventTraffic("connID", 200, true))
+	suite.observer.EventTraffic(mtglib.NewEventTraffic("connID", 100, false))
+	suite.observer.EventStart(mtglib.NewEventStart("connID2", net.ParseIP("10.0.0.11")))
+	suite.observer.EventDomainFronting(mtglib.NewEventDomainFronting("connID2"))
+
+	streams := []admin.Stream{}
+
+	suite.Equal(http.StatusOK, suite.Do(http.MethodGet, "/streams", &streams))
+	suite.Require().Len(streams, 2)
+
+	suite.Equal("connID", streams[0].ID)
+	suite.Equal("10.0.0.10", streams[0].ClientIP.String())
+	suite.Equal(4, streams[0].DC)
+	suite.Equal("10.1.0.1", streams[0].TelegramIP.String())
+	suite.EqualValues(200, streams[0].TrafficToClient)
+	suite.EqualValues(100, streams[0].TrafficFromClient)
+	suite.False(streams[0].DomainFronting)
+	suite.NotEmpty(streams[0].Age)
+
+	suite.Equal("connID2", streams[1].ID)
+	suite.True(streams[1].DomainFronting)
+
+	stream := admin.Stream{}
+
+	suite.Equal(http.StatusOK, suite.Do(http.MethodGet, "/streams/connID2", &stream))
+	suite.Equal("10.0.0.11", stream.ClientIP.String())
+
+	suite.observer.EventFinish(mtglib.NewEventFinish("connID2"))
+
+	suite.Equal(http.StatusNotFound, suite.Do(http.MethodGet, "/streams/connID2", nil))
+	suite.Equal(http.StatusOK, suite.Do(http.MethodGet, "/streams", &streams))
+	suite.Len(streams, 1)
+}
+
+func (suite *ServerTestSuite) TestCloseStreamNoProxy() {
+	suite.Equal(http.StatusServiceUnavailable,
+		suite.DoWithToken(http.MethodDelete, "/streams/connID", "s3cr3t", nil))
+}
+
+func (suite *ServerTestSuite) TestCloseStream() {
+	suite.server.SetProxy(suite.proxyMock)
+	suite.proxyMock.On("CloseStream", "connID").Once().Return(true)
+	suite.proxyMock.On("CloseStream", "unknown").Once().Return(false)
+
+	suite.Equal(http.StatusNoContent, suite.DoWithToken(http.MethodDelete, "/streams/connID", "s3cr3t", nil))
+	suite.Equal(http.StatusNotFound, suite.DoWithToken(http.MethodDelete, "/streams/unknown", "s3cr3t", nil))
+}
+
+func (suite *ServerTestSuite) TestCloseStreamIncorrectToken() {
+	suite.server.SetProxy(suite.proxyMock)
+
+	suite.Equal(http.StatusUnauthorized, suite.Do(http.MethodDelete, "/streams/connID", nil))
+	suite.Equal(http.StatusUnauthorized, suite.DoWithToken(http.MethodDelete, "/streams/connID", "secret", nil))
+}
+
+func (suite *ServerTestSuite) TestCloseStreamNoToken() {
+	server := admin.NewServer("")
+	server.SetProxy(suite.proxyMock)
+
+	defer server.Close() //nolint: errcheck
+
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	unixListener, err := net.Listen("unix", filepath.Join(suite.T().TempDir(), "admin.sock"))
+	suite.Require().NoError(err)
+
+	go server.Serve(tcpListener)  //nolint: errcheck
+	go server.Serve(unixListener) //nolint: errcheck
+
+	suite.proxyMock.On("CloseStream", "connID").Once().Return(true)
+
+	req, err := http.NewRequest( //nolint: noctx
+		http.MethodDelete,
+		fmt.Sprintf("http://%s/streams/connID", tcpListener.Addr().String()),
+		nil)
+	suite.Require().NoError(err)
+
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	resp.Body.Close() //nolint: errcheck
+
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+
+	unixClient := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", unixListener.Addr().String())
+			},
+		},
+	}
+
+	req, err = http.NewRequest(http.MethodDelete, "http://unix/streams/connID", nil) //nolint: noctx
+	suite.Require().NoError(err)
+
+	resp, err = unixClient.Do(req)
+	suite.Require().NoError(err)
+	resp.Body.Close() //nolint: errcheck
+
+	suite.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (suite *ServerTestSuite) TestIPLists() {
+	suite.observer.EventIPListSize(mtglib.NewEventIPListSize(10, true))
+	suite.observer.EventIPListSize(mtglib.NewEventIPListSize(3, false))
+	suite.observer.EventIPListSize(mtglib.NewEventIPBanlistSize(2))
+
+	lists := admin.IPLists{}
+
+	suite.Equal(http.StatusOK, suite.Do(http.MethodGet, "/iplists", &lists))
+	suite.Equal(10, lists.Blocklist)
+	suite.Equal(3, lists.Allowlist)
+	suite.Equal(2, lists.Banlist)
+}
+
+func (suite *ServerTestSuite) TestCounters() {
+	suite.observer.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	suite.observer.EventConnectedToDC(mtglib.NewEventConnectedToDC("connID", net.ParseIP("10.1.0.1"), 4))
+	suite.observer.EventFinish(mtglib.NewEventFinish("connID"))
+	suite.observer.EventStart(mtglib.NewEventStart("connID2", net.ParseIP("10.0.0.11")))
+	suite.observer.EventReplayAttack(mtglib.NewEventReplayAttack("connID2"))
+	suite.observer.EventHandshakeFailed(
+		mtglib.NewEventHandshakeFailed("connID2", net.ParseIP("10.0.0.11"), mtglib.HandshakeFailureReplay))
+	suite.observer.EventDomainFronting(mtglib.NewEventDomainFronting("connID2"))
+	suite.observer.EventFinish(mtglib.NewEventFinish("connID2"))
+	suite.observer.EventIPBlocklisted(mtglib.NewEventIPBlocklisted(net.ParseIP("10.0.0.12")))
+	suite.observer.EventConcurrencyLimited(mtglib.NewEventConcurrencyLimited())
+	suite.observer.EventRateLimited(mtglib.NewEventRateLimited(net.ParseIP("10.0.0.13"), mtglib.RateLimitIPRate))
+	suite.observer.EventIPBanned(mtglib.NewEventIPBanned(net.ParseIP("10.0.0.14"), mtglib.HandshakeFailureNotTLS, time.Hour))
+
+	counters := admin.Counters{}
+
+	suite.Equal(http.StatusOK, suite.Do(http.MethodGet, "/counters", &counters))
+	suite.Equal(admin.Counters{
+		Streams:           2,
+		ConnectedToDC:     1,
+		HandshakeFailures: 1,
+		HandshakeFailureReasons: map[mtglib.HandshakeFailureReason]uint64{
+			mtglib.HandshakeFailureReplay: 1,
+		},
+		DomainFronting:     1,
+		ReplayAttacks:      1,
+		Rejected:           1,
+		ConcurrencyLimited: 1,
+		RateLimited:        1,
+		Banned:             1,
+	}, counters)
+}
+
+func (suite *ServerTestSuite) TestDoppelGanger() {
+	suite.server.SetProxy(suite.proxyMock)
+	suite.proxyMock.On("DoppelGangerState").Once().Return(mtglib.DoppelGangerState{
+		URLs:       []string{"https://example.com"},
+		DRS:        true,
+		Calibrated: true,
+		NoiseMean:  2500,
+	})
+
+	state := map[string]any{}
+
+	suite.Equal(http.StatusOK, suite.Do(http.MethodGet, "/doppelganger", &state))
+	suite.Equal([]any{"https://example.com"}, state["urls"])
+	suite.Equal(true, state["drs"])
+	suite.Equal(true, state["calibrated"])
+	suite.InDelta(2500, state["noiseMean"], 0.1)
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &ServerTestSuite{})
+}
@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+// Stream is a description of active stream.
+type Stream struct {
+	ID                string    `json:"id"`
+	ClientIP          net.IP    `json:"clientIp"`
+	SecretName        string    `json:"secret,omitempty"`
+	DC                int       `json:"dc,omitempty"`
+	TelegramIP        net.IP    `json:"telegramIp,omitempty"`
+	DomainFronting    bool      `json:"domainFronting"`
+	TrafficToClient   uint      `json:"trafficToClient"`
+	TrafficFromClient uint      `json:"trafficFromClient"`
+	StartedAt         time.Time `json:"startedAt"`
+	Age               string    `json:"age"`
+}
+
+// IPLists has sizes of ip lists. They are zero until lists are loaded.
+// Banlist is a number of active bans.
+type IPLists struct {
+	Blocklist int `json:"blocklist"`
+	Allowlist int `json:"allowlist"`
+	Banlist   int `json:"banlist"`
+}
+
+// Counters are cumulative numbers of events since a start of the proxy.
+type Counters struct {
+	Streams                 uint64                                   `json:"streams"`
+	ConnectedToDC           uint64                                   `json:"connectedToDc"`
+	HandshakeFailures       uint64                                   `json:"handshakeFailures"`
+	HandshakeFailureReasons map[mtglib.HandshakeFailureReason]uint64 `json:"handshakeFailureReasons"`
+	DomainFronting          uint64                                   `json:"domainFronting"`
+	ReplayAttacks           uint64                                   `json:"replayAttacks"`
+	Rejected                uint64                                   `json:"rejected"`
+	ConcurrencyLimited      uint64                                   `json:"concurrencyLimited"`
+	RateLimited             uint64                                   `json:"rateLimited"`
+	Banned                  uint64                                   `json:"banned"`
+}
+
+type store struct {
+	mutex    sync.Mutex
+	streams  map[string]*Stream
+	ipLists  IPLists
+	counters Counters
+}
+
+func (s *store) update(streamID string, callback func(*Stream)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if stream, ok := s.streams[streamID]; ok {
+		callback(stream)
+	}
+}
+
+func (s *store) add(stream *Stream) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.streams[stream.ID] = stream
+	s.counters.Streams++
+}
+
+func (s *store) remove(streamID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.streams, streamID)
+}
+
+func (s *store) count(callback func(*Counters)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	callback(&s.counters)
+}
+
+func (s *store) getCounters() Counters {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rv := s.counters
+	rv.HandshakeFailureReasons = maps.Clone(s.counters.HandshakeFailureReasons)
+
+	return rv
+}
+
+func (s *store) get(streamID string) (Stream, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stream, ok := s.streams[streamID]
+	if !ok {
+		return Stream{}, false
+	}
+
+	return s.snapshot(stream), true
+}
+
+// list returns streams, the oldest first.
+func (s *store) list() []Stream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	streams := make([]Stream, 0, len(s.streams))
+
+	for _, v := range s.streams {
+		streams = append(streams, s.snapshot(v))
+	}
+
+	slices.SortFunc(streams, func(a, b Stream) int {
+		if rv := a.StartedAt.Compare(b.StartedAt); rv != 0 {
+			return rv
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return streams
+}
+
+func (s *store) setIPListSize(evt mtglib.EventIPListSize) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case evt.IsBanList:
+		s.ipLists.Banlist = evt.Size
+	case evt.IsBlockList:
+		s.ipLists.Blocklist = evt.Size
+	default:
+		s.ipLists.Allowlist = evt.Size
+	}
+}
+
+func (s *store) getIPLists() IPLists {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.ipLists
+}
+
+func (s *store) snapshot(stream *Stream) Stream {
+	rv := *stream
+	rv.Age = time.Since(stream.StartedAt).Truncate(time.Second).String()
+
+	return rv
+}
+
+func newStore() *store {
+	return &store{
+		streams: map[string]*Stream{},
+		counters: Counters{
+			HandshakeFailureReasons: map[mtglib.HandshakeFailureReason]uint64{},
+		},
+	}
+}
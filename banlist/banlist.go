@@ -0,0 +1,305 @@
+package banlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+// UpdateCallback defines a signature of the callback which gets a number
+// of active bans.
+type UpdateCallback func(context.Context, int)
+
+type offender struct {
+	offenses    uint
+	windowStart time.Time
+	bannedUntil time.Time
+}
+
+func (o *offender) banned(now time.Time) bool {
+	return now.Before(o.bannedUntil)
+}
+
+// Banlist is [mtglib.IPBanlist] which keeps offenders in memory and
+// periodically dumps active bans into a state file.
+type Banlist struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	logger    mtglib.Logger
+	stateFile string
+	saveMutex sync.Mutex
+
+	updateCallback UpdateCallback
+
+	maxOffenses uint
+	window      time.Duration
+	banFor      time.Duration
+
+	mutex     sync.Mutex
+	offenders map[netip.Addr]*offender
+}
+
+// Banned checks if an IP is banned now.
+func (b *Banlist) Banned(ip net.IP) bool {
+	addr, ok := ipToAddr(ip)
+	if !ok {
+		return false
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	value, ok := b.offenders[addr]
+
+	return ok && value.banned(time.Now())
+}
+
+// Offend registers a failed handshake of an IP. Only [Offenses] are
+// counted. It returns a duration of a ban if an IP has been banned
+// right now.
+func (b *Banlist) Offend(ip net.IP, reason mtglib.HandshakeFailureReason) time.Duration {
+	addr, ok := ipToAddr(ip)
+	if !ok || !slices.Contains(Offenses, reason) {
+		return 0
+	}
+
+	now := time.Now()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	value, ok := b.offenders[addr]
+	if !ok {
+		value = &offender{}
+		b.offenders[addr] = value
+	}
+
+	// streams which were started before a ban can still fail, we do not
+	// want to prolong a ban because of them.
+	if value.banned(now) {
+		return 0
+	}
+
+	if now.Sub(value.windowStart) >= b.window {
+		value.windowStart = now
+		value.offenses = 0
+	}
+
+	value.offenses++
+
+	if value.offenses < b.maxOffenses {
+		return 0
+	}
+
+	value.offenses = 0
+	value.bannedUntil = now.Add(b.banFor)
+
+	return b.banFor
+}
+
+// Run starts a background process which forgets expired bans, dumps
+// active ones into a state file and reports their number to the update
+// callback.
+//
+// This is a blocking method so you probably want to run it in a goroutine.
+func (b *Banlist) Run(saveEach time.Duration) {
+	if saveEach == 0 {
+		saveEach = DefaultSaveEach
+	}
+
+	ticker := time.NewTicker(saveEach)
+
+	b.report(b.cleanup())
+
+	defer func() {
+		ticker.Stop()
+
+		select {
+		case <-ticker.C:
+		default:
+		}
+	}()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+			b.report(b.cleanup())
+
+			if err := b.save(); err != nil {
+				b.logger.WarningError("cannot save banlist state", err)
+			}
+		}
+	}
+}
+
+// Shutdown stops a background process and dumps active bans into a
+// state file.
+func (b *Banlist) Shutdown() {
+	b.ctxCancel()
+
+	if err := b.save(); err != nil {
+		b.logger.WarningError("cannot save banlist state", err)
+	}
+}
+
+// cleanup forgets expired bans and returns a number of active ones.
+func (b *Banlist) cleanup() int {
+	now := time.Now()
+	active := 0
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for k, v := range b.offenders {
+		switch {
+		case v.banned(now):
+			active++
+		case now.Sub(v.windowStart) >= b.window:
+			delete(b.offenders, k)
+		}
+	}
+
+	return active
+}
+
+func (b *Banlist) report(active int) {
+	if b.updateCallback != nil {
+		b.updateCallback(b.ctx, active)
+	}
+}
+
+func (b *Banlist) load() error {
+	if b.stateFile == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(b.stateFile)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return fmt.Errorf("cannot read a state file: %w", err)
+	}
+
+	state := map[netip.Addr]time.Time{}
+
+	if err := json.Unmarshal(content, &state); err != nil {
+		return fmt.Errorf("cannot parse a state file: %w", err)
+	}
+
+	now := time.Now()
+
+	for addr, bannedUntil := range state {
+		if now.Before(bannedUntil) {
+			b.offenders[addr.Unmap()] = &offender{
+				bannedUntil: bannedUntil,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Banlist) save() error {
+	if b.stateFile == "" {
+		return nil
+	}
+
+	b.saveMutex.Lock()
+	defer b.saveMutex.Unlock()
+
+	now := time.Now()
+	state := map[netip.Addr]time.Time{}
+
+	b.mutex.Lock()
+
+	for addr, value := range b.offenders {
+		if value.banned(now) {
+			state[addr] = value.bannedUntil.UTC()
+		}
+	}
+
+	b.mutex.Unlock()
+
+	content, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("cannot serialize a state: %w", err)
+	}
+
+	// write + rename is atomic, so we won't end up with a half-written file
+	// on crash.
+	tmpFile, err := os.CreateTemp(filepath.Dir(b.stateFile), filepath.Base(b.stateFile)+".*")
+	if err != nil {
+		return fmt.Errorf("cannot create a temporary file: %w", err)
+	}
+
+	defer os.Remove(tmpFile.Name()) //nolint: errcheck
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close() //nolint: errcheck
+
+		return fmt.Errorf("cannot write a temporary file: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("cannot close a temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), b.stateFile); err != nil {
+		return fmt.Errorf("cannot replace a state file: %w", err)
+	}
+
+	return nil
+}
+
+func ipToAddr(ip net.IP) (netip.Addr, bool) {
+	addr, ok := netip.AddrFromSlice(ip)
+
+	return addr.Unmap(), ok
+}
+
+// New creates a new banlist with given rules.
+//
+// If stateFile is empty, bans are kept only in memory. updateCallback
+// is optional, it gets a number of active bans each time they are
+// saved.
+func New(logger mtglib.Logger,
+	stateFile string,
+	rules Rules,
+	updateCallback UpdateCallback,
+) (*Banlist, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	banlist := &Banlist{
+		ctx:            ctx,
+		ctxCancel:      cancel,
+		logger:         logger,
+		stateFile:      stateFile,
+		updateCallback: updateCallback,
+		maxOffenses:    rules.getMaxOffenses(),
+		window:         rules.getWindow(),
+		banFor:         rules.getBanFor(),
+		offenders:      map[netip.Addr]*offender{},
+	}
+
+	if err := banlist.load(); err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	return banlist, nil
+}
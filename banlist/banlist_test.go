@@ -0,0 +1,161 @@
+package banlist_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/banlist"
+	"github.com/9seconds/mtg/v2/logger"
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/stretchr/testify/suite"
+)
+
+type BanlistTestSuite struct {
+	suite.Suite
+
+	stateFile string
+}
+
+func (suite *BanlistTestSuite) SetupTest() {
+	suite.stateFile = filepath.Join(suite.T().TempDir(), "bans.json")
+}
+
+func (suite *BanlistTestSuite) Make(rules banlist.Rules) *banlist.Banlist {
+	b, err := banlist.New(logger.NewNoopLogger(), suite.stateFile, rules, nil)
+	suite.Require().NoError(err)
+
+	return b
+}
+
+func (suite *BanlistTestSuite) TestBan() {
+	b := suite.Make(banlist.Rules{
+		MaxOffenses: 3,
+		BanFor:      time.Hour,
+	})
+	defer b.Shutdown()
+
+	ip := net.ParseIP("10.0.0.10")
+
+	suite.Zero(b.Offend(ip, mtglib.HandshakeFailureNotTLS))
+	suite.Zero(b.Offend(ip, mtglib.HandshakeFailureReplay))
+	suite.False(b.Banned(ip))
+
+	suite.Equal(time.Hour, b.Offend(ip, mtglib.HandshakeFailureSNIMismatch))
+	suite.True(b.Banned(ip))
+	suite.True(b.Banned(net.ParseIP("::ffff:10.0.0.10")))
+	suite.False(b.Banned(net.ParseIP("10.0.0.11")))
+
+	suite.Zero(b.Offend(ip, mtglib.HandshakeFailureNotTLS))
+}
+
+func (suite *BanlistTestSuite) TestNotOffense() {
+	b := suite.Make(banlist.Rules{
+		MaxOffenses: 1,
+	})
+	defer b.Shutdown()
+
+	ip := net.ParseIP("10.0.0.10")
+
+	suite.Zero(b.Offend(ip, mtglib.HandshakeFailureTimeout))
+	suite.Zero(b.Offend(ip, mtglib.HandshakeFailureTimeSkew))
+	suite.False(b.Banned(ip))
+}
+
+func (suite *BanlistTestSuite) TestWindow() {
+	b := suite.Make(banlist.Rules{
+		MaxOffenses: 2,
+		Window:      50 * time.Millisecond,
+	})
+	defer b.Shutdown()
+
+	ip := net.ParseIP("10.0.0.10")
+
+	suite.Zero(b.Offend(ip, mtglib.HandshakeFailureBadHMAC))
+	time.Sleep(100 * time.Millisecond)
+	suite.Zero(b.Offend(ip, mtglib.HandshakeFailureBadHMAC))
+	suite.False(b.Banned(ip))
+}
+
+func (suite *BanlistTestSuite) TestExpire() {
+	b := suite.Make(banlist.Rules{
+		MaxOffenses: 1,
+		BanFor:      50 * time.Millisecond,
+	})
+	defer b.Shutdown()
+
+	ip := net.ParseIP("2001:db8::1")
+
+	suite.Equal(50*time.Millisecond, b.Offend(ip, mtglib.HandshakeFailureReplay))
+	suite.True(b.Banned(ip))
+
+	time.Sleep(100 * time.Millisecond)
+	suite.False(b.Banned(ip))
+}
+
+func (suite *BanlistTestSuite) TestPersist() {
+	b := suite.Make(banlist.Rules{
+		MaxOffenses: 1,
+	})
+
+	b.Offend(net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureReplay)
+	b.Offend(net.ParseIP("2001:db8::1"), mtglib.HandshakeFailureNotTLS)
+	b.Shutdown()
+
+	b = suite.Make(banlist.Rules{})
+	defer b.Shutdown()
+
+	suite.True(b.Banned(net.ParseIP("10.0.0.10")))
+	suite.True(b.Banned(net.ParseIP("2001:db8::1")))
+	suite.False(b.Banned(net.ParseIP("10.0.0.11")))
+}
+
+func (suite *BanlistTestSuite) TestExpiredAreNotLoaded() {
+	content := `{"10.0.0.10":"2020-01-01T00:00:00Z","10.0.0.11":"2100-01-01T00:00:00Z"}`
+	suite.NoError(os.WriteFile(suite.stateFile, []byte(content), 0o600))
+
+	b := suite.Make(banlist.Rules{})
+	defer b.Shutdown()
+
+	suite.False(b.Banned(net.ParseIP("10.0.0.10")))
+	suite.True(b.Banned(net.ParseIP("10.0.0.11")))
+}
+
+func (suite *BanlistTestSuite) TestReportActive() {
+	reported := make(chan int, 1)
+
+	b, err := banlist.New(logger.NewNoopLogger(), "", banlist.Rules{
+		MaxOffenses: 1,
+	}, func(_ context.Context, size int) {
+		select {
+		case reported <- size:
+		default:
+		}
+	})
+	suite.Require().NoError(err)
+
+	defer b.Shutdown()
+
+	b.Offend(net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureReplay)
+	b.Offend(net.ParseIP("10.0.0.11"), mtglib.HandshakeFailureNotTLS)
+	b.Offend(net.ParseIP("10.0.0.12"), mtglib.HandshakeFailureTimeout)
+
+	go b.Run(time.Hour)
+
+	suite.Equal(2, <-reported)
+}
+
+func (suite *BanlistTestSuite) TestBrokenStateFile() {
+	suite.NoError(os.WriteFile(suite.stateFile, []byte("{"), 0o600))
+
+	_, err := banlist.New(logger.NewNoopLogger(), suite.stateFile, banlist.Rules{}, nil)
+	suite.Error(err)
+}
+
+func TestBanlist(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &BanlistTestSuite{})
+}
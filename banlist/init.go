@@ -0,0 +1,83 @@
+// Banlist package has implementations of [mtglib.IPBanlist].
+//
+// A banlist bans client IPs which fail handshakes too often, the same
+// way fail2ban does. Active probers usually hammer a proxy with broken
+// or replayed client hellos; once an IP has made a given number of
+// offenses within a window, its connections are closed for some time.
+// Active bans are stored in a small state file so they survive
+// restarts.
+package banlist
+
+import (
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+const (
+	// DefaultMaxOffenses defines a default number of offenses within a
+	// window which results in a ban.
+	DefaultMaxOffenses = 10
+
+	// DefaultWindow defines a default size of window where offenses are
+	// counted.
+	DefaultWindow = time.Minute
+
+	// DefaultBanFor defines a default duration of a ban.
+	DefaultBanFor = time.Hour
+
+	// DefaultSaveEach defines a default period of time for dumping bans
+	// into a state file.
+	DefaultSaveEach = time.Minute
+)
+
+// Offenses is a list of handshake failures which are counted as
+// offenses. Timeouts, closed and reset connections, clock skew and
+// unsupported connection types are not here: honest clients get them on
+// bad networks or with drifted clocks. Clients with inactive secrets or
+// exhausted quotas are honest too.
+var Offenses = []mtglib.HandshakeFailureReason{
+	mtglib.HandshakeFailureNotTLS,
+	mtglib.HandshakeFailureMalformedRecord,
+	mtglib.HandshakeFailureSNIMismatch,
+	mtglib.HandshakeFailureBadHMAC,
+	mtglib.HandshakeFailureReplay,
+}
+
+// Rules defines when and for how long an IP is banned. Zero values are
+// replaced with defaults.
+type Rules struct {
+	// MaxOffenses is a number of offenses within a window which results
+	// in a ban.
+	MaxOffenses uint
+
+	// Window is a size of window where offenses are counted.
+	Window time.Duration
+
+	// BanFor is a duration of a ban.
+	BanFor time.Duration
+}
+
+func (r Rules) getMaxOffenses() uint {
+	if r.MaxOffenses == 0 {
+		return DefaultMaxOffenses
+	}
+
+	return r.MaxOffenses
+}
+
+func (r Rules) getWindow() time.Duration {
+	if r.Window == 0 {
+		return DefaultWindow
+	}
+
+	return r.Window
+}
+
+func (r Rules) getBanFor() time.Duration {
+	if r.BanFor == 0 {
+		return DefaultBanFor
+	}
+
+	return r.BanFor
+}
@@ -0,0 +1,22 @@
+package banlist
+
+import (
+	"net"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+type noop struct{}
+
+func (n noop) Banned(_ net.IP) bool { return false }
+func (n noop) Shutdown()            {}
+
+func (n noop) Offend(_ net.IP, _ mtglib.HandshakeFailureReason) time.Duration {
+	return 0
+}
+
+// NewNoop returns a dummy banlist which never bans anyone.
+func NewNoop() mtglib.IPBanlist {
+	return noop{}
+}
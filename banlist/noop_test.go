@@ -0,0 +1,32 @@
+package banlist_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/banlist"
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/stretchr/testify/suite"
+)
+
+type NoopTestSuite struct {
+	suite.Suite
+}
+
+func (suite *NoopTestSuite) TestOp() {
+	b := banlist.NewNoop()
+	ip := net.ParseIP("10.0.0.10")
+
+	for range 100 {
+		suite.Zero(b.Offend(ip, mtglib.HandshakeFailureReplay))
+	}
+
+	suite.False(b.Banned(ip))
+
+	b.Shutdown()
+}
+
+func TestNoop(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &NoopTestSuite{})
+}
@@ -0,0 +1,100 @@
+package eventlog
+
+import (
+	"github.com/9seconds/mtg/v2/events"
+)
+
+// Opts defines a set of options for [NewFactory]. Exactly one of Path
+// and UnixSocket has to be set.
+type Opts struct {
+	// Path is an absolute path to a file or a named pipe where events are
+	// appended to.
+	Path string
+
+	// UnixSocket is an absolute path to a unix socket to connect to.
+	UnixSocket string
+
+	// BufferSize is a number of events which could wait to be written.
+	// If buffer is full, events are dropped.
+	//
+	// This is an optional setting, [DefaultBufferSize] is used by
+	// default.
+	BufferSize uint
+
+	// Logger is used to report errors on writing events and a number of
+	// dropped events.
+	//
+	// This is an optional setting, errors are ignored by default.
+	Logger Logger
+}
+
+func (o Opts) getBufferSize() int {
+	if o.BufferSize == 0 {
+		return DefaultBufferSize
+	}
+
+	return int(o.BufferSize)
+}
+
+func (o Opts) getLogger() Logger {
+	if o.Logger == nil {
+		return noopLogger{}
+	}
+
+	return o.Logger
+}
+
+type noopLogger struct{}
+
+func (n noopLogger) Warning(_ string)               {}
+func (n noopLogger) WarningError(_ string, _ error) {}
+
+// Factory is a factory of [events.Observer] which export events as JSON
+// lines.
+type Factory struct {
+	writer *writer
+}
+
+// Make builds a new observer.
+func (f Factory) Make() events.Observer {
+	return observer{
+		writer: f.writer,
+	}
+}
+
+// Dropped returns a number of events which were dropped because buffer
+// was full or target was not available.
+func (f Factory) Dropped() uint64 {
+	return f.writer.dropped.Load()
+}
+
+// Close writes buffered events and closes a target. Events sent after
+// that are dropped.
+func (f Factory) Close() error {
+	f.writer.Close()
+
+	return nil
+}
+
+// NewFactory builds an [events.ObserverFactory] that exports events as
+// JSON lines.
+//
+// Target is opened lazily, so it is fine if it is not available yet.
+func NewFactory(opts Opts) (Factory, error) {
+	var open target
+
+	switch {
+	case opts.Path != "" && opts.UnixSocket != "":
+		return Factory{}, ErrTooManyTargets
+	case opts.Path != "":
+		open = fileTarget(opts.Path)
+	case opts.UnixSocket != "":
+		open = unixSocketTarget(opts.UnixSocket)
+	default:
+		return Factory{}, ErrTargetIsNotSet
+	}
+
+	return Factory{
+		writer: newWriter(open, opts.getLogger(), opts.getBufferSize()),
+	}, nil
+}
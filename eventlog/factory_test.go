@@ -0,0 +1,181 @@
+package eventlog_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/eventlog"
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/stretchr/testify/suite"
+)
+
+type FactoryTestSuite struct {
+	suite.Suite
+
+	dir string
+}
+
+func (suite *FactoryTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *FactoryTestSuite) Decode(line string) map[string]any {
+	record := map[string]any{}
+	suite.Require().NoError(json.Unmarshal([]byte(line), &record))
+
+	return record
+}
+
+func (suite *FactoryTestSuite) TestNoTarget() {
+	_, err := eventlog.NewFactory(eventlog.Opts{})
+	suite.ErrorIs(err, eventlog.ErrTargetIsNotSet)
+}
+
+func (suite *FactoryTestSuite) TestTooManyTargets() {
+	_, err := eventlog.NewFactory(eventlog.Opts{
+		Path:       filepath.Join(suite.dir, "events.log"),
+		UnixSocket: filepath.Join(suite.dir, "events.sock"),
+	})
+	suite.ErrorIs(err, eventlog.ErrTooManyTargets)
+}
+
+func (suite *FactoryTestSuite) TestFile() {
+	path := filepath.Join(suite.dir, "events.log")
+	factory, err := eventlog.NewFactory(eventlog.Opts{Path: path})
+	suite.Require().NoError(err)
+
+	observer := factory.Make()
+	evt := mtglib.NewEventConnectedToDC("connID", net.ParseIP("10.1.0.1"), 4)
+	evt.DialDuration = 250 * time.Millisecond
+
+	observer.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	observer.EventConnectedToDC(evt)
+	observer.EventReplayAttack(mtglib.NewEventReplayAttack("connID"))
+	observer.EventIPListSize(mtglib.NewEventIPListSize(10, true))
+	observer.Shutdown()
+	suite.NoError(factory.Close())
+
+	data, err := os.ReadFile(path)
+	suite.Require().NoError(err)
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	suite.Require().Len(lines, 4)
+
+	record := suite.Decode(lines[0])
+	suite.Equal(eventlog.TypeStart, record["type"])
+	suite.Equal("connID", record["streamId"])
+	suite.NotEmpty(record["timestamp"])
+	suite.Equal(map[string]any{"remoteIp": "10.0.0.10"}, record["fields"])
+
+	record = suite.Decode(lines[1])
+	suite.Equal(eventlog.TypeConnectedToDC, record["type"])
+	suite.Equal(map[string]any{
+		"remoteIp":     "10.1.0.1",
+		"dc":           float64(4),
+		"dialDuration": 0.25,
+	}, record["fields"])
+
+	record = suite.Decode(lines[2])
+	suite.Equal(eventlog.TypeReplayAttack, record["type"])
+	suite.NotContains(record, "fields")
+
+	record = suite.Decode(lines[3])
+	suite.Equal(eventlog.TypeIPListSize, record["type"])
+	suite.NotContains(record, "streamId")
+
+	suite.Zero(factory.Dropped())
+}
+
+func (suite *FactoryTestSuite) TestNamedPipe() {
+	path := filepath.Join(suite.dir, "events.pipe")
+	suite.Require().NoError(syscall.Mkfifo(path, 0o600))
+
+	// read-write mode does not block on opening and does not return EOF
+	// until mtg opens a pipe.
+	reader, err := os.OpenFile(path, os.O_RDWR, 0)
+	suite.Require().NoError(err)
+
+	defer reader.Close() //nolint: errcheck
+
+	factory, err := eventlog.NewFactory(eventlog.Opts{Path: path})
+	suite.Require().NoError(err)
+
+	factory.Make().EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+
+	reader.SetReadDeadline(time.Now().Add(time.Second)) //nolint: errcheck
+
+	line, err := bufio.NewReader(reader).ReadString('\n')
+	suite.NoError(err)
+	suite.Equal(eventlog.TypeStart, suite.Decode(line)["type"])
+	suite.NoError(factory.Close())
+}
+
+func (suite *FactoryTestSuite) TestUnixSocketReconnect() {
+	path := filepath.Join(suite.dir, "events.sock")
+	factory, err := eventlog.NewFactory(eventlog.Opts{UnixSocket: path})
+	suite.Require().NoError(err)
+
+	defer factory.Close() //nolint: errcheck
+
+	observer := factory.Make()
+
+	// nobody listens yet, so event is dropped
+	observer.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	suite.Eventually(func() bool {
+		return factory.Dropped() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	listener, err := net.Listen("unix", path)
+	suite.Require().NoError(err)
+
+	defer listener.Close() //nolint: errcheck
+
+	time.Sleep(eventlog.ReconnectEach)
+	observer.EventFinish(mtglib.NewEventFinish("connID"))
+
+	conn, err := listener.Accept()
+	suite.Require().NoError(err)
+
+	defer conn.Close() //nolint: errcheck
+
+	conn.SetReadDeadline(time.Now().Add(time.Second)) //nolint: errcheck
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	suite.NoError(err)
+	suite.Equal(eventlog.TypeFinish, suite.Decode(line)["type"])
+	suite.EqualValues(1, factory.Dropped())
+}
+
+func (suite *FactoryTestSuite) TestBufferIsFull() {
+	path := filepath.Join(suite.dir, "events.pipe")
+	suite.Require().NoError(syscall.Mkfifo(path, 0o600))
+
+	// there is no reader, so pipe cannot be opened and events are
+	// dropped either by writer or because buffer is full.
+	factory, err := eventlog.NewFactory(eventlog.Opts{
+		Path:       path,
+		BufferSize: 1,
+	})
+	suite.Require().NoError(err)
+
+	observer := factory.Make()
+
+	for range 100 {
+		observer.EventConcurrencyLimited(mtglib.NewEventConcurrencyLimited())
+	}
+
+	suite.NoError(factory.Close())
+	suite.EqualValues(100, factory.Dropped())
+}
+
+func TestFactory(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &FactoryTestSuite{})
+}
@@ -0,0 +1,55 @@
+// Eventlog package has an implementation of [events.Observer] which
+// exports each event as a JSON line. It is intended for external
+// analytics which wants to consume raw events of mtg.
+//
+// Each line is an object with a type of event, stream id, timestamp,
+// name of the secret and fields of the event:
+//
+//	{"type":"start","streamId":"...","timestamp":"...","fields":{"remoteIp":"10.0.0.10"}}
+//
+// Events are written into a file (named pipe is fine) or a unix socket.
+// If target is not available, mtg reconnects to it.
+//
+// Writing never blocks [events.EventStream]: events are put into a
+// bounded buffer and a single goroutine writes them. If this buffer is
+// full or target is not available, events are dropped and counted.
+package eventlog
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultBufferSize defines a default number of events which could
+	// wait to be written.
+	DefaultBufferSize = 4096
+
+	// ReconnectEach defines a minimal time between attempts to open a
+	// target. Events are dropped in between.
+	ReconnectEach = time.Second
+
+	// WriteTimeout defines a timeout of writing to named pipe or unix
+	// socket. Regular files ignore it.
+	WriteTimeout = 5 * time.Second
+
+	// ReportDropsEach defines how often a number of dropped events is
+	// logged.
+	ReportDropsEach = time.Minute
+)
+
+var (
+	// ErrTargetIsNotSet is returned if neither path nor unix socket is
+	// set.
+	ErrTargetIsNotSet = errors.New("either path or unix socket has to be set")
+
+	// ErrTooManyTargets is returned if both path and unix socket are set.
+	ErrTooManyTargets = errors.New("path and unix socket are mutually exclusive")
+)
+
+// Logger defines a logger which is used to report write errors and
+// dropped events.
+type Logger interface {
+	Warning(msg string)
+	WarningError(msg string, err error)
+}
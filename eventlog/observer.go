@@ -0,0 +1,133 @@
+package eventlog
+
+import (
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+type observer struct {
+	writer *writer
+}
+
+func (o observer) EventStart(evt mtglib.EventStart) {
+	o.writer.send(encode(TypeStart, evt, fields{
+		"remoteIp": ipToString(evt.RemoteIP),
+	}))
+}
+
+func (o observer) EventConnectedToDC(evt mtglib.EventConnectedToDC) {
+	o.writer.send(encode(TypeConnectedToDC, evt, fields{
+		"remoteIp":     ipToString(evt.RemoteIP),
+		"dc":           evt.DC,
+		"dialDuration": evt.DialDuration.Seconds(),
+	}))
+}
+
+func (o observer) EventTraffic(evt mtglib.EventTraffic) {
+	o.writer.send(encode(TypeTraffic, evt, fields{
+		"traffic": evt.Traffic,
+		"isRead":  evt.IsRead,
+	}))
+}
+
+func (o observer) EventFinish(evt mtglib.EventFinish) {
+	o.writer.send(encode(TypeFinish, evt, fields{
+		"duration": evt.Duration.Seconds(),
+	}))
+}
+
+func (o observer) EventDomainFronting(evt mtglib.EventDomainFronting) {
+	o.writer.send(encode(TypeDomainFronting, evt, nil))
+}
+
+func (o observer) EventConcurrencyLimited(evt mtglib.EventConcurrencyLimited) {
+	o.writer.send(encode(TypeConcurrencyLimited, evt, nil))
+}
+
+func (o observer) EventIPBlocklisted(evt mtglib.EventIPBlocklisted) {
+	o.writer.send(encode(TypeIPBlocklisted, evt, fields{
+		"remoteIp":    ipToString(evt.RemoteIP),
+		"isBlockList": evt.IsBlockList,
+		"isBanned":    evt.IsBanned,
+		"asn":         evt.ASN,
+	}))
+}
+
+func (o observer) EventReplayAttack(evt mtglib.EventReplayAttack) {
+	o.writer.send(encode(TypeReplayAttack, evt, nil))
+}
+
+func (o observer) EventPreviousSecretUsed(evt mtglib.EventPreviousSecretUsed) {
+	o.writer.send(encode(TypePreviousSecretUsed, evt, nil))
+}
+
+func (o observer) EventIPListSize(evt mtglib.EventIPListSize) {
+	o.writer.send(encode(TypeIPListSize, evt, fields{
+		"size":        evt.Size,
+		"isBlockList": evt.IsBlockList,
+		"isBanList":   evt.IsBanList,
+	}))
+}
+
+func (o observer) EventTelegramProbe(evt mtglib.EventTelegramProbe) {
+	o.writer.send(encode(TypeTelegramProbe, evt, fields{
+		"remoteIp":    ipToString(evt.RemoteIP),
+		"dc":          evt.DC,
+		"rtt":         evt.RTT.Seconds(),
+		"failureRate": evt.FailureRate,
+		"forgotten":   evt.Forgotten,
+	}))
+}
+
+func (o observer) EventWarmPool(evt mtglib.EventWarmPool) {
+	o.writer.send(encode(TypeWarmPool, evt, fields{
+		"dc":  evt.DC,
+		"hit": evt.Hit,
+	}))
+}
+
+func (o observer) EventHandshakeFailed(evt mtglib.EventHandshakeFailed) {
+	o.writer.send(encode(TypeHandshakeFailed, evt, fields{
+		"remoteIp": ipToString(evt.RemoteIP),
+		"reason":   evt.Reason,
+	}))
+}
+
+func (o observer) EventClockSkew(evt mtglib.EventClockSkew) {
+	o.writer.send(encode(TypeClockSkew, evt, fields{
+		"skew":     evt.Skew.Seconds(),
+		"accepted": evt.Accepted,
+	}))
+}
+
+func (o observer) EventHandshakeSucceeded(evt mtglib.EventHandshakeSucceeded) {
+	o.writer.send(encode(TypeHandshakeSucceeded, evt, fields{
+		"fakeTlsDuration":    evt.FakeTLSDuration.Seconds(),
+		"obfuscatedDuration": evt.ObfuscatedDuration.Seconds(),
+	}))
+}
+
+func (o observer) EventOverflow(evt mtglib.EventOverflow) {
+	o.writer.send(encode(TypeOverflow, evt, fields{
+		"dropped":   evt.Dropped,
+		"coalesced": evt.Coalesced,
+	}))
+}
+
+func (o observer) EventRateLimited(evt mtglib.EventRateLimited) {
+	o.writer.send(encode(TypeRateLimited, evt, fields{
+		"remoteIp": ipToString(evt.RemoteIP),
+		"reason":   evt.Reason,
+	}))
+}
+
+func (o observer) EventIPBanned(evt mtglib.EventIPBanned) {
+	o.writer.send(encode(TypeIPBanned, evt, fields{
+		"remoteIp": ipToString(evt.RemoteIP),
+		"reason":   evt.Reason,
+		"duration": evt.Duration.Seconds(),
+	}))
+}
+
+// Shutdown does nothing: events are written by the factory, so they
+// survive a replacement of the event stream.
+func (o observer) Shutdown() {}
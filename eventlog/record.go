@@ -0,0 +1,68 @@
+package eventlog
+
+import (
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+// Values of the 'type' field of records, one per each event.
+const (
+	TypeStart              = "start"
+	TypeConnectedToDC      = "connected_to_dc"
+	TypeTraffic            = "traffic"
+	TypeFinish             = "finish"
+	TypeDomainFronting     = "domain_fronting"
+	TypeConcurrencyLimited = "concurrency_limited"
+	TypeIPBlocklisted      = "ip_blocklisted"
+	TypeReplayAttack       = "replay_attack"
+	TypePreviousSecretUsed = "previous_secret_used"
+	TypeIPListSize         = "ip_list_size"
+	TypeTelegramProbe      = "telegram_probe"
+	TypeWarmPool           = "warm_pool"
+	TypeHandshakeFailed    = "handshake_failed"
+	TypeClockSkew          = "clock_skew"
+	TypeHandshakeSucceeded = "handshake_succeeded"
+	TypeOverflow           = "overflow"
+	TypeRateLimited        = "rate_limited"
+	TypeIPBanned           = "ip_banned"
+)
+
+type fields map[string]any
+
+type record struct {
+	Type      string `json:"type"`
+	StreamID  string `json:"streamId,omitempty"`
+	Timestamp string `json:"timestamp"`
+	Secret    string `json:"secret,omitempty"`
+	Fields    fields `json:"fields,omitempty"`
+}
+
+// encode makes a JSON line of the event. Durations are in seconds.
+func encode(eventType string, evt mtglib.Event, eventFields fields) []byte {
+	value := record{
+		Type:      eventType,
+		StreamID:  evt.StreamID(),
+		Timestamp: evt.Timestamp().UTC().Format(time.RFC3339Nano),
+		Fields:    eventFields,
+	}
+
+	if namer, ok := evt.(mtglib.SecretNamer); ok {
+		value.Secret = namer.SecretName()
+	}
+
+	// fields have only primitive types so it is always encoded
+	encoded, _ := json.Marshal(value) //nolint: errchkjson
+
+	return append(encoded, '\n')
+}
+
+func ipToString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
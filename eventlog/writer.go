@@ -0,0 +1,220 @@
+package eventlog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// target is something writer can open: a file or a unix socket.
+type target func() (io.WriteCloser, error)
+
+func fileTarget(path string) target {
+	return func() (io.WriteCloser, error) {
+		// O_NONBLOCK makes opening of named pipe without a reader to fail
+		// instead of waiting for it. Go still blocks on writes to it.
+		file, err := os.OpenFile(path,
+			os.O_WRONLY|os.O_APPEND|os.O_CREATE|syscall.O_NONBLOCK,
+			0o640) //nolint: mnd
+		if err != nil {
+			return nil, fmt.Errorf("cannot open event log: %w", err)
+		}
+
+		return file, nil
+	}
+}
+
+func unixSocketTarget(path string) target {
+	return func() (io.WriteCloser, error) {
+		conn, err := net.DialTimeout("unix", path, WriteTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("cannot connect to event log socket: %w", err)
+		}
+
+		return conn, nil
+	}
+}
+
+// writer owns a target. Records are sent into a bounded queue and
+// written by a single goroutine, so sending never blocks.
+type writer struct {
+	queue   chan []byte
+	dropped atomic.Uint64
+	open    target
+	logger  Logger
+
+	ctx       context.Context //nolint: containedctx
+	ctxCancel context.CancelFunc
+	done      chan struct{}
+
+	// these fields are accessed only by a writer goroutine.
+	conn         io.WriteCloser
+	buf          *bufio.Writer
+	pending      uint64
+	lastOpenedAt time.Time
+	lastReported uint64
+	unavailable  bool
+}
+
+func (w *writer) send(data []byte) {
+	select {
+	case w.queue <- data:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+func (w *writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(ReportDropsEach)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			w.drain()
+			w.disconnect()
+			w.reportDrops()
+
+			return
+		case <-ticker.C:
+			w.reportDrops()
+		case data := <-w.queue:
+			w.write(data)
+
+			if len(w.queue) == 0 {
+				w.flush()
+			}
+		}
+	}
+}
+
+// drain writes what is left in the queue on shutdown.
+func (w *writer) drain() {
+	for {
+		select {
+		case data := <-w.queue:
+			w.write(data)
+		default:
+			w.flush()
+
+			return
+		}
+	}
+}
+
+func (w *writer) write(data []byte) {
+	if w.conn == nil && !w.connect() {
+		w.dropped.Add(1)
+
+		return
+	}
+
+	if w.buf.Available() < len(data) {
+		w.flush()
+
+		if w.conn == nil {
+			w.dropped.Add(1)
+
+			return
+		}
+	}
+
+	w.buf.Write(data) //nolint: errcheck
+	w.pending++
+}
+
+func (w *writer) flush() {
+	if w.conn == nil || w.buf.Buffered() == 0 {
+		return
+	}
+
+	if conn, ok := w.conn.(interface{ SetWriteDeadline(time.Time) error }); ok {
+		conn.SetWriteDeadline(time.Now().Add(WriteTimeout)) //nolint: errcheck
+	}
+
+	if err := w.buf.Flush(); err != nil {
+		w.logger.WarningError("cannot write events", err)
+		w.dropped.Add(w.pending)
+		w.disconnect()
+
+		return
+	}
+
+	w.pending = 0
+}
+
+func (w *writer) connect() bool {
+	if time.Since(w.lastOpenedAt) < ReconnectEach {
+		return false
+	}
+
+	w.lastOpenedAt = time.Now()
+
+	conn, err := w.open()
+	if err != nil {
+		// target could be unavailable for a long time, no need to
+		// report each attempt.
+		if !w.unavailable {
+			w.logger.WarningError("cannot open event log target", err)
+		}
+
+		w.unavailable = true
+
+		return false
+	}
+
+	w.unavailable = false
+	w.conn = conn
+	w.buf = bufio.NewWriter(conn)
+	w.pending = 0
+
+	return true
+}
+
+func (w *writer) disconnect() {
+	if w.conn == nil {
+		return
+	}
+
+	w.conn.Close() //nolint: errcheck
+	w.conn = nil
+	w.buf = nil
+}
+
+func (w *writer) reportDrops() {
+	dropped := w.dropped.Load()
+
+	if dropped > w.lastReported {
+		w.logger.Warning(fmt.Sprintf("%d events were dropped", dropped-w.lastReported))
+		w.lastReported = dropped
+	}
+}
+
+func (w *writer) Close() {
+	w.ctxCancel()
+	<-w.done
+}
+
+func newWriter(open target, logger Logger, bufferSize int) *writer {
+	ctx, cancel := context.WithCancel(context.Background())
+	rv := &writer{
+		queue:     make(chan []byte, bufferSize),
+		open:      open,
+		logger:    logger,
+		ctx:       ctx,
+		ctxCancel: cancel,
+		done:      make(chan struct{}),
+	}
+
+	go rv.run()
+
+	return rv
+}
@@ -4,11 +4,23 @@ import (
 	"context"
 	"math/rand/v2"
 	"runtime"
+	"sync/atomic"
+	"time"
 
 	"github.com/9seconds/mtg/v2/mtglib"
 	"github.com/OneOfOne/xxhash"
 )
 
+const (
+	// QueueSize defines a number of events which could wait for each
+	// observer.
+	QueueSize = 64
+
+	// ReportOverflowEach defines how often observers get
+	// [mtglib.EventOverflow] if some events were dropped or coalesced.
+	ReportOverflowEach = time.Second
+)
+
 // EventStream is a default implementation of the [mtglib.EventStream]
 // interface.
 //
@@ -21,11 +33,17 @@ import (
 type EventStream struct {
 	ctx       context.Context
 	ctxCancel context.CancelFunc
-	chans     []chan mtglib.Event
+	shards    []*shard
+	policy    Policy
+	dropped   *atomic.Uint64
+	coalesced *atomic.Uint64
 }
 
 // Send starts delivering of the message to observer with respect to a
 // given context If context is closed, message could be not delivered.
+//
+// If observers are slow, Send behaves according to a [Policy] of the
+// stream.
 func (e EventStream) Send(ctx context.Context, evt mtglib.Event) {
 	var chanNo uint32
 
@@ -35,10 +53,39 @@ func (e EventStream) Send(ctx context.Context, evt mtglib.Event) {
 		chanNo = rand.Uint32()
 	}
 
+	shard := e.shards[chanNo%uint32(len(e.shards))]
+
+	switch e.policy {
+	case PolicyDropNewest:
+		switch evt.(type) {
+		case mtglib.EventStart, mtglib.EventFinish:
+		default:
+			select {
+			case shard.queue <- evt:
+			default:
+				e.dropped.Add(1)
+			}
+
+			return
+		}
+	case PolicyCoalesceTraffic:
+		if trafficEvt, ok := evt.(mtglib.EventTraffic); ok {
+			select {
+			case shard.queue <- evt:
+			default:
+				shard.coalesce(trafficEvt)
+				e.coalesced.Add(1)
+			}
+
+			return
+		}
+	case PolicyBlock:
+	}
+
 	select {
 	case <-ctx.Done():
 	case <-e.ctx.Done():
-	case e.chans[chanNo%uint32(len(e.chans))] <- evt:
+	case shard.queue <- evt:
 	}
 }
 
@@ -47,12 +94,18 @@ func (e EventStream) Shutdown() {
 	e.ctxCancel()
 }
 
-// NewEventStream builds a new default event stream.
+// NewEventStream builds a new default event stream with [DefaultPolicy].
 //
 // If you give an empty array of observers, then NoopObserver is going
 // to be used. If you give many observers, then they will process a
 // message concurrently.
 func NewEventStream(observerFactories []ObserverFactory) EventStream {
+	return NewEventStreamWithPolicy(observerFactories, DefaultPolicy)
+}
+
+// NewEventStreamWithPolicy builds a new default event stream which
+// treats slow observers according to a given policy.
+func NewEventStreamWithPolicy(observerFactories []ObserverFactory, policy Policy) EventStream {
 	if len(observerFactories) == 0 {
 		observerFactories = append(observerFactories, NewNoopObserver)
 	}
@@ -61,50 +114,112 @@ func NewEventStream(observerFactories []ObserverFactory) EventStream {
 	rv := EventStream{
 		ctx:       ctx,
 		ctxCancel: cancel,
-		chans:     make([]chan mtglib.Event, runtime.NumCPU()),
+		shards:    make([]*shard, runtime.NumCPU()),
+		policy:    policy,
+		dropped:   &atomic.Uint64{},
+		coalesced: &atomic.Uint64{},
 	}
 
 	for i := range runtime.NumCPU() {
-		rv.chans[i] = make(chan mtglib.Event, 1)
+		rv.shards[i] = newShard(QueueSize)
+
+		var observer Observer
 
 		if len(observerFactories) == 1 {
-			go eventStreamProcessor(ctx, rv.chans[i], observerFactories[0]())
+			observer = observerFactories[0]()
 		} else {
-			go eventStreamProcessor(ctx, rv.chans[i], newMultiObserver(observerFactories))
+			observer = newMultiObserver(observerFactories)
 		}
+
+		go rv.process(rv.shards[i], observer)
 	}
 
 	return rv
 }
 
-func eventStreamProcessor(ctx context.Context, eventChan <-chan mtglib.Event, observer Observer) { //nolint: cyclop
+func (e EventStream) process(shard *shard, observer Observer) {
 	defer observer.Shutdown()
 
+	ticker := time.NewTicker(ReportOverflowEach)
+	defer ticker.Stop()
+
 	for {
 		select {
-		case <-ctx.Done():
+		case <-e.ctx.Done():
 			return
-		case evt := <-eventChan:
-			switch typedEvt := evt.(type) {
-			case mtglib.EventTraffic:
-				observer.EventTraffic(typedEvt)
-			case mtglib.EventStart:
-				observer.EventStart(typedEvt)
-			case mtglib.EventFinish:
-				observer.EventFinish(typedEvt)
-			case mtglib.EventConnectedToDC:
-				observer.EventConnectedToDC(typedEvt)
-			case mtglib.EventDomainFronting:
-				observer.EventDomainFronting(typedEvt)
-			case mtglib.EventIPBlocklisted:
-				observer.EventIPBlocklisted(typedEvt)
-			case mtglib.EventConcurrencyLimited:
-				observer.EventConcurrencyLimited(typedEvt)
-			case mtglib.EventReplayAttack:
-				observer.EventReplayAttack(typedEvt)
-			case mtglib.EventIPListSize:
-				observer.EventIPListSize(typedEvt)
+		case <-ticker.C:
+			dropped := e.dropped.Swap(0)
+			coalesced := e.coalesced.Swap(0)
+
+			if dropped > 0 || coalesced > 0 {
+				observer.EventOverflow(mtglib.NewEventOverflow(dropped, coalesced))
+			}
+		case <-shard.notifyCh:
+			// events which were queued before traffic was coalesced
+			// have to be seen first: EventStart or EventConnectedToDC
+			// of the same stream could be among them.
+			for range len(shard.queue) {
+				dispatchQueued(shard, observer, <-shard.queue)
+			}
+
+			for _, evt := range shard.takeAllPending() {
+				dispatch(observer, evt)
 			}
+		case evt := <-shard.queue:
+			dispatchQueued(shard, observer, evt)
 		}
 	}
 }
+
+func dispatchQueued(shard *shard, observer Observer, evt mtglib.Event) {
+	// coalesced traffic has to be seen before EventFinish of the
+	// stream.
+	if finishEvt, ok := evt.(mtglib.EventFinish); ok {
+		for _, pendingEvt := range shard.takePending(finishEvt.StreamID()) {
+			dispatch(observer, pendingEvt)
+		}
+	}
+
+	dispatch(observer, evt)
+}
+
+func dispatch(observer Observer, evt mtglib.Event) { //nolint: cyclop
+	switch typedEvt := evt.(type) {
+	case mtglib.EventTraffic:
+		observer.EventTraffic(typedEvt)
+	case mtglib.EventStart:
+		observer.EventStart(typedEvt)
+	case mtglib.EventFinish:
+		observer.EventFinish(typedEvt)
+	case mtglib.EventConnectedToDC:
+		observer.EventConnectedToDC(typedEvt)
+	case mtglib.EventDomainFronting:
+		observer.EventDomainFronting(typedEvt)
+	case mtglib.EventIPBlocklisted:
+		observer.EventIPBlocklisted(typedEvt)
+	case mtglib.EventConcurrencyLimited:
+		observer.EventConcurrencyLimited(typedEvt)
+	case mtglib.EventReplayAttack:
+		observer.EventReplayAttack(typedEvt)
+	case mtglib.EventPreviousSecretUsed:
+		observer.EventPreviousSecretUsed(typedEvt)
+	case mtglib.EventIPListSize:
+		observer.EventIPListSize(typedEvt)
+	case mtglib.EventTelegramProbe:
+		observer.EventTelegramProbe(typedEvt)
+	case mtglib.EventWarmPool:
+		observer.EventWarmPool(typedEvt)
+	case mtglib.EventHandshakeFailed:
+		observer.EventHandshakeFailed(typedEvt)
+	case mtglib.EventClockSkew:
+		observer.EventClockSkew(typedEvt)
+	case mtglib.EventHandshakeSucceeded:
+		observer.EventHandshakeSucceeded(typedEvt)
+	case mtglib.EventOverflow:
+		observer.EventOverflow(typedEvt)
+	case mtglib.EventRateLimited:
+		observer.EventRateLimited(typedEvt)
+	case mtglib.EventIPBanned:
+		observer.EventIPBanned(typedEvt)
+	}
+}
@@ -53,9 +53,40 @@ type Observer interface {
 	// EventReplayAttack reacts on incoming mtglib.EventReplayAttack event.
 	EventReplayAttack(mtglib.EventReplayAttack)
 
+	// EventPreviousSecretUsed reacts on incoming
+	// mtglib.EventPreviousSecretUsed event.
+	EventPreviousSecretUsed(mtglib.EventPreviousSecretUsed)
+
 	// EventIPListSize reacts on incoming mtglib.EventIPListSize
 	EventIPListSize(mtglib.EventIPListSize)
 
+	// EventTelegramProbe reacts on incoming mtglib.EventTelegramProbe
+	// event.
+	EventTelegramProbe(mtglib.EventTelegramProbe)
+
+	// EventWarmPool reacts on incoming mtglib.EventWarmPool event.
+	EventWarmPool(mtglib.EventWarmPool)
+
+	// EventHandshakeFailed reacts on incoming mtglib.EventHandshakeFailed
+	// event.
+	EventHandshakeFailed(mtglib.EventHandshakeFailed)
+
+	// EventClockSkew reacts on incoming mtglib.EventClockSkew event.
+	EventClockSkew(mtglib.EventClockSkew)
+
+	// EventHandshakeSucceeded reacts on incoming
+	// mtglib.EventHandshakeSucceeded event.
+	EventHandshakeSucceeded(mtglib.EventHandshakeSucceeded)
+
+	// EventOverflow reacts on incoming mtglib.EventOverflow event.
+	EventOverflow(mtglib.EventOverflow)
+
+	// EventRateLimited reacts on incoming mtglib.EventRateLimited event.
+	EventRateLimited(mtglib.EventRateLimited)
+
+	// EventIPBanned reacts on incoming mtglib.EventIPBanned event.
+	EventIPBanned(mtglib.EventIPBanned)
+
 	// Shutdown stop observer. Default event stream guarantees:
 	//   1. If shutdown is executed, it is executed only once
 	//   2. Observer won't receieve any new message after this
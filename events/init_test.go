@@ -41,10 +41,46 @@ func (o *ObserverMock) EventReplayAttack(evt mtglib.EventReplayAttack) {
 	o.Called(evt)
 }
 
+func (o *ObserverMock) EventPreviousSecretUsed(evt mtglib.EventPreviousSecretUsed) {
+	o.Called(evt)
+}
+
 func (o *ObserverMock) EventIPListSize(evt mtglib.EventIPListSize) {
 	o.Called(evt)
 }
 
+func (o *ObserverMock) EventTelegramProbe(evt mtglib.EventTelegramProbe) {
+	o.Called(evt)
+}
+
+func (o *ObserverMock) EventWarmPool(evt mtglib.EventWarmPool) {
+	o.Called(evt)
+}
+
+func (o *ObserverMock) EventHandshakeFailed(evt mtglib.EventHandshakeFailed) {
+	o.Called(evt)
+}
+
+func (o *ObserverMock) EventClockSkew(evt mtglib.EventClockSkew) {
+	o.Called(evt)
+}
+
+func (o *ObserverMock) EventHandshakeSucceeded(evt mtglib.EventHandshakeSucceeded) {
+	o.Called(evt)
+}
+
+func (o *ObserverMock) EventOverflow(evt mtglib.EventOverflow) {
+	o.Called(evt)
+}
+
+func (o *ObserverMock) EventRateLimited(evt mtglib.EventRateLimited) {
+	o.Called(evt)
+}
+
+func (o *ObserverMock) EventIPBanned(evt mtglib.EventIPBanned) {
+	o.Called(evt)
+}
+
 func (o *ObserverMock) Shutdown() {
 	o.Called()
 }
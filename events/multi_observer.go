@@ -106,6 +106,18 @@ func (m multiObserver) EventReplayAttack(evt mtglib.EventReplayAttack) {
 	wg.Wait()
 }
 
+func (m multiObserver) EventPreviousSecretUsed(evt mtglib.EventPreviousSecretUsed) {
+	wg := &sync.WaitGroup{}
+
+	for _, v := range m.observers {
+		wg.Go(func() {
+			v.EventPreviousSecretUsed(evt)
+		})
+	}
+
+	wg.Wait()
+}
+
 func (m multiObserver) EventIPListSize(evt mtglib.EventIPListSize) {
 	wg := &sync.WaitGroup{}
 
@@ -118,6 +130,102 @@ func (m multiObserver) EventIPListSize(evt mtglib.EventIPListSize) {
 	wg.Wait()
 }
 
+func (m multiObserver) EventTelegramProbe(evt mtglib.EventTelegramProbe) {
+	wg := &sync.WaitGroup{}
+
+	for _, v := range m.observers {
+		wg.Go(func() {
+			v.EventTelegramProbe(evt)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (m multiObserver) EventWarmPool(evt mtglib.EventWarmPool) {
+	wg := &sync.WaitGroup{}
+
+	for _, v := range m.observers {
+		wg.Go(func() {
+			v.EventWarmPool(evt)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (m multiObserver) EventHandshakeFailed(evt mtglib.EventHandshakeFailed) {
+	wg := &sync.WaitGroup{}
+
+	for _, v := range m.observers {
+		wg.Go(func() {
+			v.EventHandshakeFailed(evt)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (m multiObserver) EventClockSkew(evt mtglib.EventClockSkew) {
+	wg := &sync.WaitGroup{}
+
+	for _, v := range m.observers {
+		wg.Go(func() {
+			v.EventClockSkew(evt)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (m multiObserver) EventHandshakeSucceeded(evt mtglib.EventHandshakeSucceeded) {
+	wg := &sync.WaitGroup{}
+
+	for _, v := range m.observers {
+		wg.Go(func() {
+			v.EventHandshakeSucceeded(evt)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (m multiObserver) EventOverflow(evt mtglib.EventOverflow) {
+	wg := &sync.WaitGroup{}
+
+	for _, v := range m.observers {
+		wg.Go(func() {
+			v.EventOverflow(evt)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (m multiObserver) EventRateLimited(evt mtglib.EventRateLimited) {
+	wg := &sync.WaitGroup{}
+
+	for _, v := range m.observers {
+		wg.Go(func() {
+			v.EventRateLimited(evt)
+		})
+	}
+
+	wg.Wait()
+}
+
+func (m multiObserver) EventIPBanned(evt mtglib.EventIPBanned) {
+	wg := &sync.WaitGroup{}
+
+	for _, v := range m.observers {
+		wg.Go(func() {
+			v.EventIPBanned(evt)
+		})
+	}
+
+	wg.Wait()
+}
+
 func (m multiObserver) Shutdown() {
 	for _, v := range m.observers {
 		v.Shutdown()
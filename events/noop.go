@@ -25,7 +25,16 @@ func (n noopObserver) EventFinish(_ mtglib.EventFinish)
 func (n noopObserver) EventConcurrencyLimited(_ mtglib.EventConcurrencyLimited) {}
 func (n noopObserver) EventIPBlocklisted(_ mtglib.EventIPBlocklisted)           {}
 func (n noopObserver) EventReplayAttack(_ mtglib.EventReplayAttack)             {}
+func (n noopObserver) EventPreviousSecretUsed(_ mtglib.EventPreviousSecretUsed) {}
 func (n noopObserver) EventIPListSize(_ mtglib.EventIPListSize)                 {}
+func (n noopObserver) EventTelegramProbe(_ mtglib.EventTelegramProbe)           {}
+func (n noopObserver) EventWarmPool(_ mtglib.EventWarmPool)                     {}
+func (n noopObserver) EventHandshakeFailed(_ mtglib.EventHandshakeFailed)       {}
+func (n noopObserver) EventClockSkew(_ mtglib.EventClockSkew)                   {}
+func (n noopObserver) EventHandshakeSucceeded(_ mtglib.EventHandshakeSucceeded) {}
+func (n noopObserver) EventOverflow(_ mtglib.EventOverflow)                     {}
+func (n noopObserver) EventRateLimited(_ mtglib.EventRateLimited)               {}
+func (n noopObserver) EventIPBanned(_ mtglib.EventIPBanned)                     {}
 func (n noopObserver) Shutdown()                                                {}
 
 // NewNoopObserver creates an observer which discards each message.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/9seconds/mtg/v2/events"
 	"github.com/9seconds/mtg/v2/mtglib"
@@ -28,6 +29,15 @@ func (suite *NoopTestSuite) SetupSuite() {
 		"ip-blacklisted":      mtglib.NewEventIPBlocklisted(net.ParseIP("10.0.0.10")),
 		"replay-attack":       mtglib.NewEventReplayAttack("connID"),
 		"ip-list-size":        mtglib.NewEventIPListSize(10, true),
+		"previous-secret":     mtglib.NewEventPreviousSecretUsed("connID"),
+		"telegram-probe":      mtglib.NewEventTelegramProbe(net.ParseIP("127.1.0.1"), 2, time.Second, 0.5),
+		"warm-pool":           mtglib.NewEventWarmPool("connID", 2, true),
+		"handshake-failed":    mtglib.NewEventHandshakeFailed("connID", net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureNotTLS),
+		"clock-skew":          mtglib.NewEventClockSkew("connID", -time.Minute, false),
+		"handshake-succeeded": mtglib.NewEventHandshakeSucceeded("connID", time.Second, time.Millisecond),
+		"overflow":            mtglib.NewEventOverflow(1, 2),
+		"rate-limited":        mtglib.NewEventRateLimited(net.ParseIP("10.0.0.10"), mtglib.RateLimitIPRate),
+		"ip-banned":           mtglib.NewEventIPBanned(net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureReplay, time.Hour),
 	}
 	suite.ctx = context.Background()
 }
@@ -66,8 +76,26 @@ func (suite *NoopTestSuite) TestObserver() {
 				observer.EventIPBlocklisted(typedEvt)
 			case mtglib.EventReplayAttack:
 				observer.EventReplayAttack(typedEvt)
+			case mtglib.EventPreviousSecretUsed:
+				observer.EventPreviousSecretUsed(typedEvt)
 			case mtglib.EventIPListSize:
 				observer.EventIPListSize(typedEvt)
+			case mtglib.EventTelegramProbe:
+				observer.EventTelegramProbe(typedEvt)
+			case mtglib.EventWarmPool:
+				observer.EventWarmPool(typedEvt)
+			case mtglib.EventHandshakeFailed:
+				observer.EventHandshakeFailed(typedEvt)
+			case mtglib.EventClockSkew:
+				observer.EventClockSkew(typedEvt)
+			case mtglib.EventHandshakeSucceeded:
+				observer.EventHandshakeSucceeded(typedEvt)
+			case mtglib.EventOverflow:
+				observer.EventOverflow(typedEvt)
+			case mtglib.EventRateLimited:
+				observer.EventRateLimited(typedEvt)
+			case mtglib.EventIPBanned:
+				observer.EventIPBanned(typedEvt)
 			}
 		})
 	}
@@ -0,0 +1,127 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+// Policy defines what [EventStream.Send] does if observers are slow and
+// a queue of events is full.
+type Policy string
+
+const (
+	// PolicyBlock waits until observers take an event. Nothing is lost
+	// but slow observers slow down a sender, including relaying of
+	// traffic.
+	PolicyBlock Policy = "block"
+
+	// PolicyDropNewest drops an event which does not fit into a queue.
+	// [mtglib.EventStart] and [mtglib.EventFinish] are never dropped:
+	// observers allocate and release their per-stream state on them.
+	PolicyDropNewest Policy = "drop-newest"
+
+	// PolicyCoalesceTraffic merges [mtglib.EventTraffic] which does not
+	// fit into a queue with pending traffic of the same stream and
+	// direction. Observers get the same amount of bytes but with fewer
+	// events. Other events are sent as with [PolicyBlock]: they are rare
+	// and happen out of relaying of traffic.
+	PolicyCoalesceTraffic Policy = "coalesce-traffic"
+
+	// DefaultPolicy is a policy of [NewEventStream]. Observers should
+	// never add latency to relaying of traffic and counters of traffic
+	// stay correct.
+	DefaultPolicy = PolicyCoalesceTraffic
+)
+
+type coalesceKey struct {
+	streamID string
+	isRead   bool
+}
+
+// shard is a queue of events processed by a single observer. Traffic
+// events which do not fit into the queue are kept in pending until an
+// observer is ready to take them.
+type shard struct {
+	queue    chan mtglib.Event
+	notifyCh chan struct{}
+
+	pendingMutex sync.Mutex
+	pending      map[coalesceKey]mtglib.EventTraffic
+}
+
+func (s *shard) coalesce(evt mtglib.EventTraffic) {
+	key := coalesceKey{
+		streamID: evt.StreamID(),
+		isRead:   evt.IsRead,
+	}
+
+	s.pendingMutex.Lock()
+
+	if stored, ok := s.pending[key]; ok {
+		evt.Traffic += stored.Traffic
+	}
+
+	s.pending[key] = evt
+
+	s.pendingMutex.Unlock()
+
+	select {
+	case s.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// takePending returns pending traffic events of a stream.
+func (s *shard) takePending(streamID string) []mtglib.EventTraffic {
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	var rv []mtglib.EventTraffic
+
+	for _, isRead := range [2]bool{true, false} {
+		key := coalesceKey{
+			streamID: streamID,
+			isRead:   isRead,
+		}
+
+		if evt, ok := s.pending[key]; ok {
+			rv = append(rv, evt)
+			delete(s.pending, key)
+		}
+	}
+
+	return rv
+}
+
+// takeAllPending returns pending traffic events of all streams.
+func (s *shard) takeAllPending() []mtglib.EventTraffic {
+	s.pendingMutex.Lock()
+	defer s.pendingMutex.Unlock()
+
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	rv := make([]mtglib.EventTraffic, 0, len(s.pending))
+
+	for _, evt := range s.pending {
+		rv = append(rv, evt)
+	}
+
+	clear(s.pending)
+
+	return rv
+}
+
+func newShard(size int) *shard {
+	return &shard{
+		queue:    make(chan mtglib.Event, size),
+		notifyCh: make(chan struct{}, 1),
+		pending:  make(map[coalesceKey]mtglib.EventTraffic),
+	}
+}
@@ -0,0 +1,186 @@
+package events_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/events"
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/stretchr/testify/suite"
+)
+
+// slowObserver is stuck on EventStart until it is released.
+type slowObserver struct {
+	events.Observer
+
+	started  chan struct{}
+	released chan struct{}
+
+	mutex           sync.Mutex
+	traffic         uint
+	trafficOnFinish uint
+	trafficOnDC     uint
+	finished        bool
+	overflow        mtglib.EventOverflow
+}
+
+func (s *slowObserver) EventStart(_ mtglib.EventStart) {
+	close(s.started)
+	<-s.released
+}
+
+func (s *slowObserver) EventTraffic(evt mtglib.EventTraffic) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.traffic += evt.Traffic
+}
+
+func (s *slowObserver) EventConnectedToDC(_ mtglib.EventConnectedToDC) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.trafficOnDC = s.traffic
+}
+
+func (s *slowObserver) EventFinish(_ mtglib.EventFinish) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.finished = true
+	s.trafficOnFinish = s.traffic
+}
+
+func (s *slowObserver) EventOverflow(evt mtglib.EventOverflow) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.overflow.Dropped += evt.Dropped
+	s.overflow.Coalesced += evt.Coalesced
+}
+
+func (s *slowObserver) Shutdown() {}
+
+type PolicyTestSuite struct {
+	suite.Suite
+
+	ctx      context.Context
+	observer *slowObserver
+}
+
+func (suite *PolicyTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.observer = &slowObserver{
+		Observer: events.NewNoopObserver(),
+		started:  make(chan struct{}),
+		released: make(chan struct{}),
+	}
+}
+
+func (suite *PolicyTestSuite) Make(policy events.Policy) events.EventStream {
+	stream := events.NewEventStreamWithPolicy([]events.ObserverFactory{
+		func() events.Observer { return suite.observer },
+	}, policy)
+
+	suite.T().Cleanup(stream.Shutdown)
+
+	stream.Send(suite.ctx, mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	<-suite.observer.started
+
+	return stream
+}
+
+// SendTraffic sends more traffic events than queue can take. Sending
+// must not block.
+func (suite *PolicyTestSuite) SendTraffic(stream events.EventStream, count int) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for range count {
+			stream.Send(suite.ctx, mtglib.NewEventTraffic("connID", 10, true))
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.FailNow("send is blocked")
+	}
+}
+
+func (suite *PolicyTestSuite) Finish(stream events.EventStream) {
+	go stream.Send(suite.ctx, mtglib.NewEventFinish("connID"))
+
+	close(suite.observer.released)
+
+	suite.Eventually(func() bool {
+		suite.observer.mutex.Lock()
+		defer suite.observer.mutex.Unlock()
+
+		return suite.observer.finished
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (suite *PolicyTestSuite) Overflow() mtglib.EventOverflow {
+	time.Sleep(2 * events.ReportOverflowEach)
+
+	suite.observer.mutex.Lock()
+	defer suite.observer.mutex.Unlock()
+
+	return suite.observer.overflow
+}
+
+func (suite *PolicyTestSuite) TestCoalesceTraffic() {
+	stream := suite.Make(events.PolicyCoalesceTraffic)
+
+	suite.SendTraffic(stream, events.QueueSize+100)
+	suite.Finish(stream)
+
+	suite.observer.mutex.Lock()
+	suite.EqualValues(10*(events.QueueSize+100), suite.observer.trafficOnFinish)
+	suite.observer.mutex.Unlock()
+
+	overflow := suite.Overflow()
+	suite.Zero(overflow.Dropped)
+	suite.EqualValues(100, overflow.Coalesced)
+}
+
+func (suite *PolicyTestSuite) TestCoalescedTrafficAfterQueued() {
+	stream := suite.Make(events.PolicyCoalesceTraffic)
+
+	suite.SendTraffic(stream, events.QueueSize-1)
+	stream.Send(suite.ctx, mtglib.NewEventConnectedToDC("connID", net.ParseIP("10.1.0.10"), 2))
+	suite.SendTraffic(stream, 100)
+	suite.Finish(stream)
+
+	suite.observer.mutex.Lock()
+	defer suite.observer.mutex.Unlock()
+
+	suite.EqualValues(10*(events.QueueSize-1), suite.observer.trafficOnDC)
+	suite.EqualValues(10*(events.QueueSize+99), suite.observer.trafficOnFinish)
+}
+
+func (suite *PolicyTestSuite) TestDropNewest() {
+	stream := suite.Make(events.PolicyDropNewest)
+
+	suite.SendTraffic(stream, events.QueueSize+100)
+	suite.Finish(stream)
+
+	suite.observer.mutex.Lock()
+	suite.EqualValues(10*events.QueueSize, suite.observer.trafficOnFinish)
+	suite.observer.mutex.Unlock()
+
+	overflow := suite.Overflow()
+	suite.EqualValues(100, overflow.Dropped)
+	suite.Zero(overflow.Coalesced)
+}
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &PolicyTestSuite{})
+}
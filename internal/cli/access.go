@@ -3,22 +3,30 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/9seconds/mtg/v2/internal/config"
 	"github.com/9seconds/mtg/v2/internal/utils"
+	"github.com/9seconds/mtg/v2/mtglib"
 )
 
 type accessResponse struct {
 	IPv4   *accessResponseURLs `json:"ipv4,omitempty"`
 	IPv6   *accessResponseURLs `json:"ipv6,omitempty"`
 	Secret struct {
-		Hex    string `json:"hex"`
-		Base64 string `json:"base64"`
+		Name      string    `json:"name"`
+		Hex       string    `json:"hex"`
+		Base64    string    `json:"base64"`
+		NotBefore time.Time `json:"not_before,omitzero"` //nolint: tagliatelle
+		NotAfter  time.Time `json:"not_after,omitzero"`  //nolint: tagliatelle
 	} `json:"secret"`
 }
 
@@ -37,6 +45,7 @@ type Access struct {
 	PublicIPv6 net.IP `kong:"help='Public IPv6 address for proxy. By default it is resolved via remote website',name='ipv6',short='I'"`   //nolint: lll
 	Port       uint   `kong:"help='Port number. Default port is taken from configuration file, bind-to parameter',type:'uint',short='p'"` //nolint: lll
 	Hex        bool   `kong:"help='Print secret in hex encoding.',short='x'"`
+	SecretName string `kong:"help='Name of the secret from secrets section. By default, secret option is used.',name='secret-name',short='s'"` //nolint: lll
 }
 
 func (a *Access) Run(cli *CLI, version string) error {
@@ -45,9 +54,17 @@ func (a *Access) Run(cli *CLI, version string) error {
 		return fmt.Errorf("cannot init config: %w", err)
 	}
 
+	name, secret, err := a.getSecret(conf)
+	if err != nil {
+		return fmt.Errorf("cannot choose a secret: %w", err)
+	}
+
 	resp := &accessResponse{}
-	resp.Secret.Base64 = conf.Secret.Base64()
-	resp.Secret.Hex = conf.Secret.Hex()
+	resp.Secret.Name = name
+	resp.Secret.Base64 = secret.Base64()
+	resp.Secret.Hex = secret.Hex()
+	resp.Secret.NotBefore = secret.NotBefore
+	resp.Secret.NotAfter = secret.NotAfter
 
 	ntw, err := makeNetwork(conf, version)
 	if err != nil {
@@ -69,7 +86,7 @@ func (a *Access) Run(cli *CLI, version string) error {
 			ip = ip.To4()
 		}
 
-		resp.IPv4 = a.makeURLs(conf, ip)
+		resp.IPv4 = a.makeURLs(conf, secret, ip)
 	})
 	wg.Go(func() {
 		ip := a.PublicIPv6
@@ -84,7 +101,7 @@ func (a *Access) Run(cli *CLI, version string) error {
 			ip = ip.To16()
 		}
 
-		resp.IPv6 = a.makeURLs(conf, ip)
+		resp.IPv6 = a.makeURLs(conf, secret, ip)
 	})
 
 	wg.Wait()
@@ -100,7 +117,33 @@ func (a *Access) Run(cli *CLI, version string) error {
 	return nil
 }
 
-func (a *Access) makeURLs(conf *config.Config, ip net.IP) *accessResponseURLs {
+func (a *Access) getSecret(conf *config.Config) (string, mtglib.Secret, error) {
+	secrets := conf.GetSecrets()
+
+	switch {
+	case a.SecretName == "" && conf.Secret.Valid():
+		return mtglib.DefaultSecretName, conf.GetSecret(), nil
+	case a.SecretName == "" && len(secrets) == 1:
+		for name, secret := range secrets {
+			return name, secret, nil
+		}
+	case a.SecretName == "":
+		names := slices.Sorted(maps.Keys(secrets))
+
+		return "", mtglib.Secret{}, fmt.Errorf("please choose one of %s", strings.Join(names, ", "))
+	case a.SecretName == mtglib.DefaultSecretName && conf.Secret.Valid():
+		return a.SecretName, conf.GetSecret(), nil
+	}
+
+	secret, ok := secrets[a.SecretName]
+	if !ok {
+		return "", mtglib.Secret{}, fmt.Errorf("unknown secret %s", a.SecretName)
+	}
+
+	return a.SecretName, secret, nil
+}
+
+func (a *Access) makeURLs(conf *config.Config, secret mtglib.Secret, ip net.IP) *accessResponseURLs {
 	if ip == nil {
 		return nil
 	}
@@ -115,9 +158,9 @@ func (a *Access) makeURLs(conf *config.Config, ip net.IP) *accessResponseURLs {
 	values.Set("port", strconv.Itoa(int(portNo)))
 
 	if a.Hex {
-		values.Set("secret", conf.Secret.Hex())
+		values.Set("secret", secret.Hex())
 	} else {
-		values.Set("secret", conf.Secret.Base64())
+		values.Set("secret", secret.Base64())
 	}
 
 	urlQuery := values.Encode()
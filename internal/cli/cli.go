@@ -8,5 +8,6 @@ type CLI struct {
 	Access         Access           `kong:"cmd,help='Print access information.'"`
 	Run            Run              `kong:"cmd,help='Run proxy.'"`
 	SimpleRun      SimpleRun        `kong:"cmd,help='Run proxy without config file.'"`
+	Top            Top              `kong:"cmd,help='Show live dashboard of a running proxy.'"`
 	Version        kong.VersionFlag `kong:"help='Print version.',short='v'"`
 }
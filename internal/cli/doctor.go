@@ -297,7 +297,7 @@ func (d *Doctor) checkNetworkAddresses(ntw mtglib.Network, addresses []string) e
 }
 
 func (d *Doctor) checkFrontingDomain(ntw mtglib.Network) bool {
-	host := d.conf.Secret.Host
+	host := d.conf.GetSecretHost()
 	if ip := d.conf.GetDomainFrontingIP(nil); ip != "" {
 		host = ip
 	}
@@ -329,10 +329,10 @@ func (d *Doctor) checkFrontingDomain(ntw mtglib.Network) bool {
 }
 
 func (d *Doctor) checkSecretHost(resolver *net.Resolver, ntw mtglib.Network) bool {
-	addresses, err := resolver.LookupIPAddr(context.Background(), d.conf.Secret.Host)
+	addresses, err := resolver.LookupIPAddr(context.Background(), d.conf.GetSecretHost())
 	if err != nil {
 		tplError.Execute(os.Stdout, map[string]any{ //nolint: errcheck
-			"description": fmt.Sprintf("cannot resolve DNS name of %s", d.conf.Secret.Host),
+			"description": fmt.Sprintf("cannot resolve DNS name of %s", d.conf.GetSecretHost()),
 			"error":       err,
 		})
 		return false
@@ -362,7 +362,7 @@ func (d *Doctor) checkSecretHost(resolver *net.Resolver, ntw mtglib.Network) boo
 			(ourIP6 != nil && value.IP.String() == ourIP6.String()) {
 			tplODNSSNIMatch.Execute(os.Stdout, map[string]any{ //nolint: errcheck
 				"ip":       value.IP,
-				"hostname": d.conf.Secret.Host,
+				"hostname": d.conf.GetSecretHost(),
 			})
 			return true
 		}
@@ -371,7 +371,7 @@ func (d *Doctor) checkSecretHost(resolver *net.Resolver, ntw mtglib.Network) boo
 	}
 
 	tplEDNSSNIMatch.Execute(os.Stdout, map[string]any{ //nolint: errcheck
-		"hostname": d.conf.Secret.Host,
+		"hostname": d.conf.GetSecretHost(),
 		"resolved": strings.Join(strAddresses, ", "),
 		"ip4":      ourIP4,
 		"ip6":      ourIP6,
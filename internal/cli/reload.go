@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/9seconds/mtg/v2/internal/utils"
+	"github.com/9seconds/mtg/v2/ipblocklist"
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+type eventStreamState struct {
+	stream  mtglib.EventStream
+	closers []io.Closer
+	servers []func() error
+}
+
+// Start starts HTTP endpoints of observers. A new state may use the same
+// addresses as a previous one, so it has to be started once a previous
+// state is closed.
+func (e *eventStreamState) Start() error {
+	for _, v := range e.servers {
+		if err := v(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *eventStreamState) Close() {
+	if shutdowner, ok := e.stream.(interface{ Shutdown() }); ok {
+		shutdowner.Shutdown()
+	}
+
+	for _, v := range e.closers {
+		v.Close() //nolint: errcheck
+	}
+}
+
+// reloadableEventStream is an event stream that is given to a proxy once
+// but whose stats observers can be replaced when stats settings are
+// changed.
+//
+// Observers which track streams (admin api, access log etc) are attached
+// to a base stream which is never replaced: a new observer would not
+// know streams started before a reload.
+type reloadableEventStream struct {
+	base  mtglib.EventStream
+	state atomic.Pointer[eventStreamState]
+}
+
+func (r *reloadableEventStream) Send(ctx context.Context, evt mtglib.Event) {
+	r.base.Send(ctx, evt)
+	r.state.Load().stream.Send(ctx, evt)
+}
+
+func (r *reloadableEventStream) Swap(state *eventStreamState) *eventStreamState {
+	return r.state.Swap(state)
+}
+
+func (r *reloadableEventStream) Shutdown() {
+	r.state.Load().Close()
+
+	if shutdowner, ok := r.base.(interface{ Shutdown() }); ok {
+		shutdowner.Shutdown()
+	}
+}
+
+type proxyReloader struct {
+	mutex sync.Mutex
+
+	proxy         *mtglib.Proxy
+	logger        mtglib.Logger
+	network       mtglib.Network
+	configPath    string
+	initialConf   *config.Config
+	conf          *config.Config
+	opts          mtglib.ProxyOpts
+	eventStream   *reloadableEventStream
+	geoIPDatabase *ipblocklist.GeoIPDatabase
+	asnDatabase   *ipblocklist.ASNDatabase
+}
+
+func (r *proxyReloader) Reload() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.configPath == "" {
+		return errors.New("configuration reload is not supported without a config file")
+	}
+
+	conf, err := utils.ReadConfig(r.configPath)
+	if err != nil {
+		return fmt.Errorf("cannot read configuration: %w", err)
+	}
+
+	r.warnNotReloadable(conf)
+
+	opts := r.opts
+
+	// databases of geoip and asn are not reloadable, only lists are.
+	databasesChanged := !sameJSON(r.conf.Defense.GeoIP, conf.Defense.GeoIP) ||
+		!sameJSON(r.conf.Defense.ASN, conf.Defense.ASN)
+
+	if databasesChanged || !sameJSON(r.conf.Defense.Blocklist, conf.Defense.Blocklist) {
+		opts.IPBlocklist, err = makeIPBlocklist(
+			conf,
+			r.geoIPDatabase,
+			r.asnDatabase,
+			r.logger.Named("blocklist"),
+			r.network,
+			func(ctx context.Context, size int) {
+				r.eventStream.Send(ctx, mtglib.NewEventIPListSize(size, true))
+			},
+			true)
+		if err != nil {
+			return fmt.Errorf("cannot build ip blocklist: %w", err)
+		}
+	}
+
+	if databasesChanged || !sameJSON(r.conf.Defense.Allowlist, conf.Defense.Allowlist) {
+		opts.IPAllowlist, err = makeIPAllowlist(
+			conf,
+			r.geoIPDatabase,
+			r.asnDatabase,
+			r.logger.Named("allowlist"),
+			r.network,
+			func(ctx context.Context, size int) {
+				r.eventStream.Send(ctx, mtglib.NewEventIPListSize(size, false))
+			},
+			true)
+		if err != nil {
+			r.shutdownNewLists(opts)
+
+			return fmt.Errorf("cannot build ip allowlist: %w", err)
+		}
+	}
+
+	opts = makeProxyOpts(conf, opts)
+
+	if err := r.proxy.Reload(opts); err != nil {
+		r.shutdownNewLists(opts)
+
+		return fmt.Errorf("cannot apply configuration: %w", err)
+	}
+
+	// asn lists define values of asn tag.
+	if !sameJSON(r.conf.Stats, conf.Stats) || !slices.Equal(getListedASNs(r.conf), getListedASNs(conf)) {
+		// a new stream is swapped in before a previous one is closed, so no
+		// events are lost in between.
+		state, err := makeEventStream(conf, r.logger)
+		if err != nil {
+			r.logger.WarningError("cannot build event stream, previous stats settings are kept", err)
+		} else {
+			r.eventStream.Swap(state).Close()
+
+			if err := state.Start(); err != nil {
+				r.logger.WarningError("cannot start stats endpoints", err)
+			}
+		}
+	}
+
+	r.conf = conf
+	r.opts = opts
+
+	r.logger.Info("configuration is reloaded")
+
+	return nil
+}
+
+func (r *proxyReloader) shutdownNewLists(opts mtglib.ProxyOpts) {
+	if opts.IPBlocklist != r.opts.IPBlocklist {
+		opts.IPBlocklist.Shutdown()
+	}
+
+	if opts.IPAllowlist != r.opts.IPAllowlist {
+		opts.IPAllowlist.Shutdown()
+	}
+}
+
+func (r *proxyReloader) warnNotReloadable(conf *config.Config) {
+	initial := r.initialConf
+	checks := []struct {
+		name    string
+		current any
+		updated any
+	}{
+		{"debug", initial.Debug, conf.Debug},
+		{"bind-to", initial.BindTo, conf.BindTo},
+		{"proxy-protocol-listener", initial.ProxyProtocolListener, conf.ProxyProtocolListener},
+		{"prefer-ip", initial.PreferIP, conf.PreferIP},
+		{"auto-update", initial.AutoUpdate, conf.AutoUpdate},
+		{"auto-update-first", initial.AutoUpdateFirst, conf.AutoUpdateFirst},
+		{"telegram", initial.Telegram, conf.Telegram},
+		{"network.timeout.tcp", initial.Network.Timeout.TCP, conf.Network.Timeout.TCP},
+		{"network.timeout.http", initial.Network.Timeout.HTTP, conf.Network.Timeout.HTTP},
+		{"network.keep-alive", initial.Network.KeepAlive, conf.Network.KeepAlive},
+		{"network.dns", initial.Network.DNS, conf.Network.DNS},
+		{"network.doh-ip", initial.Network.DOHIP, conf.Network.DOHIP},
+		{"network.proxies", initial.Network.Proxies, conf.Network.Proxies},
+		{"defense.anti-replay", initial.Defense.AntiReplay, conf.Defense.AntiReplay},
+		{"defense.doppelganger.repeats-per-raid",
+			initial.Defense.Doppelganger.Repeats, conf.Defense.Doppelganger.Repeats},
+		{"defense.doppelganger.raid-each",
+			initial.Defense.Doppelganger.UpdateEach, conf.Defense.Doppelganger.UpdateEach},
+		{"defense.doppelganger.drs", initial.Defense.Doppelganger.DRS, conf.Defense.Doppelganger.DRS},
+		{"defense.auto-ban", initial.Defense.AutoBan, conf.Defense.AutoBan},
+		{"defense.geoip.enabled", initial.Defense.GeoIP.Enabled, conf.Defense.GeoIP.Enabled},
+		{"defense.geoip.database", initial.Defense.GeoIP.Database, conf.Defense.GeoIP.Database},
+		{"defense.geoip.update-each", initial.Defense.GeoIP.UpdateEach, conf.Defense.GeoIP.UpdateEach},
+		{"defense.asn.enabled", initial.Defense.ASN.Enabled, conf.Defense.ASN.Enabled},
+		{"defense.asn.database", initial.Defense.ASN.Database, conf.Defense.ASN.Database},
+		{"defense.asn.update-each", initial.Defense.ASN.UpdateEach, conf.Defense.ASN.UpdateEach},
+		{"quota", initial.Quota, conf.Quota},
+		{"public-ipv4", initial.PublicIPv4, conf.PublicIPv4},
+		{"public-ipv6", initial.PublicIPv6, conf.PublicIPv6},
+		{"middle-proxy", initial.MiddleProxy, conf.MiddleProxy},
+		{"admin", initial.Admin, conf.Admin},
+		{"access-log", initial.AccessLog, conf.AccessLog},
+		{"event-log", initial.EventLog, conf.EventLog},
+		{"webhook", initial.Webhook, conf.Webhook},
+	}
+
+	for _, v := range checks {
+		if !sameJSON(v.current, v.updated) {
+			r.logger.
+				BindStr("setting", v.name).
+				Warning("setting cannot be changed without restart, ignoring it")
+		}
+	}
+}
+
+func sameJSON(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
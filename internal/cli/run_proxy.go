@@ -2,12 +2,20 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/9seconds/mtg/v2/accesslog"
+	"github.com/9seconds/mtg/v2/admin"
 	"github.com/9seconds/mtg/v2/antireplay"
+	"github.com/9seconds/mtg/v2/banlist"
+	"github.com/9seconds/mtg/v2/eventlog"
 	"github.com/9seconds/mtg/v2/events"
 	"github.com/9seconds/mtg/v2/internal/config"
 	"github.com/9seconds/mtg/v2/internal/proxyprotocol"
@@ -17,7 +25,9 @@ import (
 	"github.com/9seconds/mtg/v2/logger"
 	"github.com/9seconds/mtg/v2/mtglib"
 	"github.com/9seconds/mtg/v2/network/v2"
+	"github.com/9seconds/mtg/v2/quota"
 	"github.com/9seconds/mtg/v2/stats"
+	"github.com/9seconds/mtg/v2/webhook"
 	"github.com/pires/go-proxyproto"
 	"github.com/rs/zerolog"
 	"github.com/yl2chen/cidranger"
@@ -94,10 +104,28 @@ func makeAntiReplayCache(conf *config.Config) mtglib.AntiReplayCache {
 	)
 }
 
-func makeIPBlocklist(conf config.ListConfig,
+// runFirehol starts a background update of a list. If load is set, it
+// returns after a list is loaded: lists which replace ones in use should
+// not be empty.
+func runFirehol(list *ipblocklist.Firehol, updateEach time.Duration, load bool) error {
+	if load {
+		if err := list.Load(); err != nil {
+			list.Shutdown()
+
+			return fmt.Errorf("cannot load ip list: %w", err)
+		}
+	}
+
+	go list.Run(updateEach)
+
+	return nil
+}
+
+func makeFirehol(conf config.ListConfig,
 	logger mtglib.Logger,
 	ntw mtglib.Network,
 	updateCallback ipblocklist.FireholUpdateCallback,
+	load bool,
 ) (mtglib.IPBlocklist, error) {
 	if !conf.Enabled.Get(false) {
 		return ipblocklist.NewNoop(), nil
@@ -124,92 +152,464 @@ func makeIPBlocklist(conf config.ListConfig,
 		return nil, fmt.Errorf("incorrect parameters for firehol: %w", err)
 	}
 
-	go blocklist.Run(conf.UpdateEach.Get(ipblocklist.DefaultFireholUpdateEach))
+	if err := runFirehol(blocklist, conf.UpdateEach.Get(ipblocklist.DefaultFireholUpdateEach), load); err != nil {
+		return nil, err
+	}
 
 	return blocklist, nil
 }
 
-func makeIPAllowlist(conf config.ListConfig,
+// makeGeoIPDatabase returns nil if geoip is disabled.
+func makeGeoIPDatabase(conf *config.Config, logger mtglib.Logger) (*ipblocklist.GeoIPDatabase, error) {
+	if !conf.Defense.GeoIP.Enabled.Get(false) {
+		return nil, nil //nolint: nilnil
+	}
+
+	geoIPDatabase, err := ipblocklist.NewGeoIPDatabase(logger, conf.Defense.GeoIP.Database.Get(""))
+	if err != nil {
+		return nil, fmt.Errorf("incorrect parameters for geoip: %w", err)
+	}
+
+	go geoIPDatabase.Run(conf.Defense.GeoIP.UpdateEach.Get(ipblocklist.DefaultGeoIPUpdateEach))
+
+	return geoIPDatabase, nil
+}
+
+// makeASNDatabase returns nil if asn is disabled.
+func makeASNDatabase(conf *config.Config, logger mtglib.Logger) (*ipblocklist.ASNDatabase, error) {
+	if !conf.Defense.ASN.Enabled.Get(false) {
+		return nil, nil //nolint: nilnil
+	}
+
+	asnDatabase, err := ipblocklist.NewASNDatabase(logger, conf.Defense.ASN.Database.Get(""))
+	if err != nil {
+		return nil, fmt.Errorf("incorrect parameters for asn: %w", err)
+	}
+
+	go asnDatabase.Run(conf.Defense.ASN.UpdateEach.Get(ipblocklist.DefaultASNUpdateEach))
+
+	return asnDatabase, nil
+}
+
+// makeDatabaseLists builds geoip and asn lists. Only enabled and
+// non-empty ones are returned.
+func makeDatabaseLists(geoIPList config.GeoIPList,
+	asnList []config.TypeASN,
+	geoIPDatabase *ipblocklist.GeoIPDatabase,
+	asnDatabase *ipblocklist.ASNDatabase,
+) []mtglib.IPBlocklist {
+	lists := []mtglib.IPBlocklist{}
+
+	if geoIPDatabase != nil && !geoIPList.Empty() {
+		countries := make([]string, len(geoIPList.Countries))
+		for i, v := range geoIPList.Countries {
+			countries[i] = v.Get("")
+		}
+
+		continents := make([]string, len(geoIPList.Continents))
+		for i, v := range geoIPList.Continents {
+			continents[i] = v.Get("")
+		}
+
+		lists = append(lists, geoIPDatabase.Blocklist(countries, continents))
+	}
+
+	if asnDatabase != nil && len(asnList) > 0 {
+		asns := make([]uint, len(asnList))
+		for i, v := range asnList {
+			asns[i] = v.Get(0)
+		}
+
+		lists = append(lists, asnDatabase.Blocklist(asns))
+	}
+
+	return lists
+}
+
+func makeIPBlocklist(conf *config.Config,
+	geoIPDatabase *ipblocklist.GeoIPDatabase,
+	asnDatabase *ipblocklist.ASNDatabase,
 	logger mtglib.Logger,
 	ntw mtglib.Network,
 	updateCallback ipblocklist.FireholUpdateCallback,
+	load bool,
 ) (mtglib.IPBlocklist, error) {
-	var (
-		allowlist mtglib.IPBlocklist
-		err       error
-	)
+	lists := makeDatabaseLists(
+		conf.Defense.GeoIP.Blocklist,
+		conf.Defense.ASN.Blocklist,
+		geoIPDatabase,
+		asnDatabase)
 
-	if !conf.Enabled.Get(false) {
-		allowlist, err = ipblocklist.NewFireholFromFiles(
-			logger.Named("ipblocklist"),
-			1,
-			[]files.File{
-				files.NewMem([]*net.IPNet{
-					cidranger.AllIPv4,
-					cidranger.AllIPv6,
-				}),
-			},
-			updateCallback,
-		)
+	blocklist, err := makeFirehol(conf.Defense.Blocklist, logger, ntw, updateCallback, load)
+	if err != nil {
+		ipblocklist.NewMulti(lists...).Shutdown()
 
-		go allowlist.Run(conf.UpdateEach.Get(ipblocklist.DefaultFireholUpdateEach))
-	} else {
-		allowlist, err = makeIPBlocklist(
-			conf,
-			logger,
-			ntw,
-			updateCallback,
-		)
+		return nil, err
+	}
+
+	if len(lists) == 0 {
+		return blocklist, nil
+	}
+
+	return ipblocklist.NewMulti(append([]mtglib.IPBlocklist{blocklist}, lists...)...), nil
+}
+
+// makeIPAllowlist builds an allowlist. If only geoip or asn allowlists
+// are enabled, they are used as is. Otherwise, a client has to be in any
+// of them.
+func makeIPAllowlist(conf *config.Config,
+	geoIPDatabase *ipblocklist.GeoIPDatabase,
+	asnDatabase *ipblocklist.ASNDatabase,
+	logger mtglib.Logger,
+	ntw mtglib.Network,
+	updateCallback ipblocklist.FireholUpdateCallback,
+	load bool,
+) (mtglib.IPBlocklist, error) {
+	lists := makeDatabaseLists(
+		conf.Defense.GeoIP.Allowlist,
+		conf.Defense.ASN.Allowlist,
+		geoIPDatabase,
+		asnDatabase)
+
+	switch {
+	case len(lists) == 1 && !conf.Defense.Allowlist.Enabled.Get(false):
+		return lists[0], nil
+	case len(lists) > 1 && !conf.Defense.Allowlist.Enabled.Get(false):
+		return ipblocklist.NewMulti(lists...), nil
 	}
 
+	allowlist, err := makeFireholAllowlist(conf.Defense.Allowlist, logger, ntw, updateCallback, load)
 	if err != nil {
+		ipblocklist.NewMulti(lists...).Shutdown()
+
+		return nil, err
+	}
+
+	if len(lists) == 0 {
+		return allowlist, nil
+	}
+
+	return ipblocklist.NewMulti(append([]mtglib.IPBlocklist{allowlist}, lists...)...), nil
+}
+
+func makeFireholAllowlist(conf config.ListConfig,
+	logger mtglib.Logger,
+	ntw mtglib.Network,
+	updateCallback ipblocklist.FireholUpdateCallback,
+	load bool,
+) (mtglib.IPBlocklist, error) {
+	if conf.Enabled.Get(false) {
+		allowlist, err := makeFirehol(conf, logger, ntw, updateCallback, load)
+		if err != nil {
+			return nil, fmt.Errorf("cannot build allowlist: %w", err)
+		}
+
+		return allowlist, nil
+	}
+
+	allowlist, err := ipblocklist.NewFireholFromFiles(
+		logger.Named("ipblocklist"),
+		1,
+		[]files.File{
+			files.NewMem([]*net.IPNet{
+				cidranger.AllIPv4,
+				cidranger.AllIPv6,
+			}),
+		},
+		updateCallback,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build allowlist: %w", err)
+	}
+
+	if err := runFirehol(allowlist, conf.UpdateEach.Get(ipblocklist.DefaultFireholUpdateEach), load); err != nil {
 		return nil, fmt.Errorf("cannot build allowlist: %w", err)
 	}
 
 	return allowlist, nil
 }
 
-func makeEventStream(conf *config.Config, logger mtglib.Logger) (mtglib.EventStream, error) {
-	factories := make([]events.ObserverFactory, 0, 2)
+func makeTrafficQuota(conf *config.Config, logger mtglib.Logger) (mtglib.TrafficQuota, error) {
+	if len(conf.Quota.Secrets) == 0 {
+		return quota.NewNoop(), nil
+	}
+
+	limits := make(map[string]quota.Limits, len(conf.Quota.Secrets))
+
+	for name, value := range conf.Quota.Secrets {
+		limits[name] = quota.Limits{
+			Daily:      uint64(value.Daily.Get(0)),
+			Monthly:    uint64(value.Monthly.Get(0)),
+			Throughput: uint64(value.Throughput.Get(0)),
+		}
+	}
+
+	trafficQuota, err := quota.New(logger, conf.Quota.StateFile.Get(""), limits)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect parameters for quota: %w", err)
+	}
+
+	go trafficQuota.Run(conf.Quota.SaveEach.Get(quota.DefaultSaveEach))
+
+	return trafficQuota, nil
+}
+
+func makeIPBanlist(
+	conf *config.Config,
+	logger mtglib.Logger,
+	updateCallback banlist.UpdateCallback,
+) (mtglib.IPBanlist, error) {
+	if !conf.Defense.AutoBan.Enabled.Get(false) {
+		return banlist.NewNoop(), nil
+	}
+
+	ipBanlist, err := banlist.New(logger, conf.Defense.AutoBan.StateFile.Get(""), banlist.Rules{
+		MaxOffenses: conf.Defense.AutoBan.MaxOffenses.Get(banlist.DefaultMaxOffenses),
+		Window:      conf.Defense.AutoBan.Window.Get(banlist.DefaultWindow),
+		BanFor:      conf.Defense.AutoBan.BanFor.Get(banlist.DefaultBanFor),
+	}, updateCallback)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect parameters for banlist: %w", err)
+	}
+
+	go ipBanlist.Run(conf.Defense.AutoBan.SaveEach.Get(banlist.DefaultSaveEach))
+
+	return ipBanlist, nil
+}
+
+// makeBaseEventStream builds a stream for observers which are not
+// reloadable. They are not closed with the stream.
+func makeBaseEventStream(conf *config.Config, observers []events.ObserverFactory) mtglib.EventStream {
+	if len(observers) == 0 {
+		return events.NewNoopStream()
+	}
+
+	policy := conf.Stats.EventPolicy.Get(string(events.DefaultPolicy))
+
+	return events.NewEventStreamWithPolicy(observers, events.Policy(policy))
+}
+
+// getListedASNs returns autonomous systems of asn blocklist and
+// allowlist. Only they have own values of asn tag in metrics.
+func getListedASNs(conf *config.Config) []uint {
+	asns := []uint{}
+
+	for _, v := range slices.Concat(conf.Defense.ASN.Blocklist, conf.Defense.ASN.Allowlist) {
+		asns = append(asns, v.Get(0))
+	}
+
+	return asns
+}
+
+// makeEventStream builds observers defined by stats settings. HTTP
+// endpoints are not started until Start of a returned state is called.
+func makeEventStream(conf *config.Config, logger mtglib.Logger) (*eventStreamState, error) {
+	factories := make([]events.ObserverFactory, 0, 3) //nolint: mnd
+	state := &eventStreamState{}
+	asns := getListedASNs(conf)
 
 	if conf.Stats.StatsD.Enabled.Get(false) {
 		statsdFactory, err := stats.NewStatsd(
 			conf.Stats.StatsD.Address.Get(""),
 			logger.Named("statsd"),
 			conf.Stats.StatsD.MetricPrefix.Get(stats.DefaultStatsdMetricPrefix),
-			conf.Stats.StatsD.TagFormat.Get(stats.DefaultStatsdTagFormat))
+			conf.Stats.StatsD.TagFormat.Get(stats.DefaultStatsdTagFormat),
+			asns)
 		if err != nil {
 			return nil, fmt.Errorf("cannot build statsd observer: %w", err)
 		}
 
 		factories = append(factories, statsdFactory.Make)
+		state.closers = append(state.closers, statsdFactory)
 	}
 
 	if conf.Stats.Prometheus.Enabled.Get(false) {
 		prometheus := stats.NewPrometheus(
 			conf.Stats.Prometheus.MetricPrefix.Get(stats.DefaultMetricPrefix),
 			conf.Stats.Prometheus.HTTPPath.Get("/"),
+			asns,
 		)
 
-		listener, err := net.Listen("tcp", conf.Stats.Prometheus.BindTo.Get(""))
+		factories = append(factories, prometheus.Make)
+		state.closers = append(state.closers, prometheus)
+		state.servers = append(state.servers, func() error {
+			listener, err := net.Listen("tcp", conf.Stats.Prometheus.BindTo.Get(""))
+			if err != nil {
+				return fmt.Errorf("cannot start a listener for prometheus: %w", err)
+			}
+
+			go prometheus.Serve(listener) //nolint: errcheck
+
+			return nil
+		})
+	}
+
+	if conf.Stats.OTLP.Enabled.Get(false) {
+		endpoint := conf.Stats.OTLP.Endpoint.Get(nil)
+
+		otlp, err := stats.NewOTLP(
+			endpoint.Host,
+			endpoint.Path,
+			conf.Stats.OTLP.MetricPrefix.Get(stats.DefaultMetricPrefix),
+			endpoint.Scheme == "http",
+			conf.Stats.OTLP.PushInterval.Get(stats.DefaultOTLPPushInterval),
+			asns)
 		if err != nil {
-			return nil, fmt.Errorf("cannot start a listener for prometheus: %w", err)
-		}
+			state.Close()
 
-		go prometheus.Serve(listener) //nolint: errcheck
+			return nil, fmt.Errorf("cannot build otlp observer: %w", err)
+		}
 
-		factories = append(factories, prometheus.Make)
+		factories = append(factories, otlp.Make)
+		state.closers = append(state.closers, otlp)
 	}
 
 	if len(factories) > 0 {
-		return events.NewEventStream(factories), nil
+		policy := conf.Stats.EventPolicy.Get(string(events.DefaultPolicy))
+		state.stream = events.NewEventStreamWithPolicy(factories, events.Policy(policy))
+	} else {
+		state.stream = events.NewNoopStream()
+	}
+
+	return state, nil
+}
+
+func makeAdminServer(conf *config.Config) (*admin.Server, error) {
+	listeners := []net.Listener{}
+
+	if addr := conf.Admin.BindTo.Get(""); addr != "" {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot start a listener for admin api: %w", err)
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	if path := conf.Admin.UnixSocket.Get(""); path != "" {
+		// a socket file is left if previous process was killed. Anything
+		// else at this path is not ours to remove.
+		if stat, err := os.Lstat(path); err == nil && stat.Mode()&fs.ModeSocket != 0 {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("cannot remove stale admin api socket: %w", err)
+			}
+		}
+
+		listener, err := net.Listen("unix", path)
+		if err != nil {
+			for _, v := range listeners {
+				v.Close() //nolint: errcheck
+			}
+
+			return nil, fmt.Errorf("cannot start a unix socket listener for admin api: %w", err)
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	server := admin.NewServer(conf.Admin.Token.Get(""))
+
+	for _, listener := range listeners {
+		go server.Serve(listener) //nolint: errcheck
+	}
+
+	return server, nil
+}
+
+func makeAccessLog(conf *config.Config, logger mtglib.Logger) (accesslog.Factory, error) {
+	return accesslog.NewFactory(accesslog.Opts{ //nolint: wrapcheck
+		Path:       conf.AccessLog.Path.Get(""),
+		Format:     conf.AccessLog.Format.Get(accesslog.DefaultFormat),
+		MaxSize:    conf.AccessLog.MaxSize.Get(accesslog.DefaultMaxSize),
+		RotateEach: conf.AccessLog.RotateEach.Get(accesslog.DefaultRotateEach),
+		MaxBackups: conf.AccessLog.MaxBackups.Get(accesslog.DefaultMaxBackups),
+		Logger:     logger,
+	})
+}
+
+func makeEventLog(conf *config.Config, logger mtglib.Logger) (eventlog.Factory, error) {
+	return eventlog.NewFactory(eventlog.Opts{ //nolint: wrapcheck
+		Path:       conf.EventLog.Path.Get(""),
+		UnixSocket: conf.EventLog.UnixSocket.Get(""),
+		BufferSize: conf.EventLog.BufferSize.Get(eventlog.DefaultBufferSize),
+		Logger:     logger,
+	})
+}
+
+func makeWebhook(conf *config.Config, ntw mtglib.Network, logger mtglib.Logger) (webhook.Factory, error) {
+	urls := make([]string, len(conf.Webhook.URLs))
+	for i, v := range conf.Webhook.URLs {
+		urls[i] = v.String()
+	}
+
+	thresholds := conf.Webhook.Thresholds
+
+	return webhook.NewFactory(webhook.Opts{ //nolint: wrapcheck
+		URLs: urls,
+		Thresholds: webhook.Thresholds{
+			ReplayAttacks:      thresholds.ReplayAttacks.Get(0),
+			IPBlocklisted:      thresholds.IPBlocklisted.Get(0),
+			ConcurrencyLimited: thresholds.ConcurrencyLimited.Get(0),
+			HandshakeFailures:  thresholds.HandshakeFailures.Get(0),
+			BlocklistEmpty:     thresholds.BlocklistEmpty.Get(false),
+		},
+		Window:     conf.Webhook.Window.Get(webhook.DefaultWindow),
+		Cooldown:   conf.Webhook.Cooldown.Get(webhook.DefaultCooldown),
+		BatchEach:  conf.Webhook.BatchEach.Get(webhook.DefaultBatchEach),
+		HTTPClient: ntw.MakeHTTPClient(nil),
+		Logger:     logger,
+	})
+}
+
+func makeProxyOpts(conf *config.Config, base mtglib.ProxyOpts) mtglib.ProxyOpts {
+	doppelGangerURLs := make([]string, len(conf.Defense.Doppelganger.URLs))
+	for i, v := range conf.Defense.Doppelganger.URLs {
+		doppelGangerURLs[i] = v.String()
 	}
 
-	return events.NewNoopStream(), nil
+	opts := base
+
+	opts.Secret = conf.GetSecret()
+	opts.Secrets = conf.GetSecrets()
+	opts.PreviousSecret = conf.GetPreviousSecret(mtglib.DefaultPreviousSecretGracePeriod)
+	opts.Concurrency = conf.GetConcurrency(mtglib.DefaultConcurrency)
+	opts.DomainFrontingPort = conf.GetDomainFrontingPort(mtglib.DefaultDomainFrontingPort)
+	opts.DomainFrontingIP = conf.GetDomainFrontingIP(nil)
+	opts.DomainFrontingProxyProtocol = conf.GetDomainFrontingProxyProtocol(false)
+	opts.PreferIP = conf.PreferIP.Get(mtglib.DefaultPreferIP)
+	opts.AutoUpdate = conf.AutoUpdate.Get(false)
+	opts.AutoUpdateFirst = conf.AutoUpdateFirst.Get(false)
+	opts.DCOverrides = conf.GetDCOverrides()
+	opts.ReplaceOverriddenDCs = conf.Telegram.ReplaceDC.Get(false)
+	opts.WarmPoolSize = conf.GetWarmPoolSize(mtglib.DefaultWarmPoolSize)
+	opts.WarmPoolMaxIdle = conf.Telegram.WarmPool.MaxIdle.Get(mtglib.DefaultWarmPoolMaxIdle)
+	opts.DCProbeEach = conf.GetDCProbeEach(mtglib.DefaultDCProbeEach)
+
+	opts.AllowFallbackOnUnknownDC = conf.AllowFallbackOnUnknownDC.Get(false)
+	opts.TolerateTimeSkewness = conf.TolerateTimeSkewness.Value
+	opts.IdleTimeout = conf.Network.Timeout.Idle.Get(mtglib.DefaultIdleTimeout)
+	opts.HandshakeTimeout = conf.Network.Timeout.Handshake.Get(mtglib.DefaultHandshakeTimeout)
+
+	opts.DoppelGangerURLs = doppelGangerURLs
+	opts.DoppelGangerPerRaid = conf.Defense.Doppelganger.Repeats.Get(mtglib.DoppelGangerPerRaid)
+	opts.DoppelGangerEach = conf.Defense.Doppelganger.UpdateEach.Get(mtglib.DoppelGangerEach)
+	opts.DoppelGangerDRS = conf.Defense.Doppelganger.DRS.Get(false)
+
+	clientLimits := conf.Defense.ClientLimits
+	opts.ClientLimits = mtglib.ClientLimits{
+		ConcurrentPerIP:     clientLimits.ConcurrentPerIP.Get(0),
+		ConcurrentPerSubnet: clientLimits.ConcurrentPerSubnet.Get(0),
+		RatePerIP:           clientLimits.RatePerIP.Get(0),
+		RatePerSubnet:       clientLimits.RatePerSubnet.Get(0),
+		DomainFronting: clientLimits.Action.Get(config.TypeClientLimitActionDrop) ==
+			config.TypeClientLimitActionDomainFronting,
+	}
+
+	return opts
 }
 
 func warnSNIMismatch(conf *config.Config, ntw mtglib.Network, log mtglib.Logger) {
-	host := conf.Secret.Host
+	host := conf.GetSecretHost()
 	if host == "" {
 		return
 	}
@@ -287,15 +687,16 @@ func warnSNIMismatch(conf *config.Config, ntw mtglib.Network, log mtglib.Logger)
 		"DPI may detect and block the proxy. See 'mtg doctor' for details")
 }
 
-func runProxy(conf *config.Config, version string) error { //nolint: funlen, cyclop
+func runProxy(conf *config.Config, configPath, version string) error { //nolint: funlen, cyclop
 	logger := makeLogger(conf)
 
 	logger.BindJSON("configuration", conf.String()).Debug("configuration")
 
-	eventStream, err := makeEventStream(conf, logger)
-	if err != nil {
-		return fmt.Errorf("cannot build event stream: %w", err)
-	}
+	var (
+		adminServer *admin.Server
+		observers   []events.ObserverFactory
+		err         error
+	)
 
 	ntw, err := makeNetwork(conf, version)
 	if err != nil {
@@ -304,59 +705,146 @@ func runProxy(conf *config.Config, version string) error { //nolint: funlen, cyc
 
 	warnSNIMismatch(conf, ntw, logger)
 
+	if conf.Admin.Enabled.Get(false) {
+		adminServer, err = makeAdminServer(conf)
+		if err != nil {
+			return err
+		}
+
+		defer adminServer.Close() //nolint: errcheck
+
+		observers = append(observers, adminServer.Make)
+	}
+
+	if conf.AccessLog.Enabled.Get(false) {
+		accessLog, err := makeAccessLog(conf, logger.Named("access-log"))
+		if err != nil {
+			return fmt.Errorf("cannot build access log: %w", err)
+		}
+
+		defer accessLog.Close() //nolint: errcheck
+
+		observers = append(observers, accessLog.Make)
+	}
+
+	if conf.EventLog.Enabled.Get(false) {
+		eventLog, err := makeEventLog(conf, logger.Named("event-log"))
+		if err != nil {
+			return fmt.Errorf("cannot build event log: %w", err)
+		}
+
+		defer eventLog.Close() //nolint: errcheck
+
+		observers = append(observers, eventLog.Make)
+	}
+
+	if conf.Webhook.Enabled.Get(false) {
+		alerts, err := makeWebhook(conf, ntw, logger.Named("webhook"))
+		if err != nil {
+			return fmt.Errorf("cannot build webhook: %w", err)
+		}
+
+		defer alerts.Close() //nolint: errcheck
+
+		observers = append(observers, alerts.Make)
+	}
+
+	eventStreamState, err := makeEventStream(conf, logger)
+	if err != nil {
+		return fmt.Errorf("cannot build event stream: %w", err)
+	}
+
+	if err := eventStreamState.Start(); err != nil {
+		eventStreamState.Close()
+
+		return fmt.Errorf("cannot start event stream: %w", err)
+	}
+
+	eventStream := &reloadableEventStream{
+		base: makeBaseEventStream(conf, observers),
+	}
+	eventStream.Swap(eventStreamState)
+
+	geoIPDatabase, err := makeGeoIPDatabase(conf, logger)
+	if err != nil {
+		return fmt.Errorf("cannot build geoip database: %w", err)
+	}
+
+	if geoIPDatabase != nil {
+		defer geoIPDatabase.Shutdown()
+	}
+
+	asnDatabase, err := makeASNDatabase(conf, logger)
+	if err != nil {
+		return fmt.Errorf("cannot build asn database: %w", err)
+	}
+
+	if asnDatabase != nil {
+		defer asnDatabase.Shutdown()
+	}
+
 	blocklist, err := makeIPBlocklist(
-		conf.Defense.Blocklist,
+		conf,
+		geoIPDatabase,
+		asnDatabase,
 		logger.Named("blocklist"),
 		ntw,
 		func(ctx context.Context, size int) {
 			eventStream.Send(ctx, mtglib.NewEventIPListSize(size, true))
-		})
+		},
+		false)
 	if err != nil {
 		return fmt.Errorf("cannot build ip blocklist: %w", err)
 	}
 
 	allowlist, err := makeIPAllowlist(
-		conf.Defense.Allowlist,
+		conf,
+		geoIPDatabase,
+		asnDatabase,
 		logger.Named("allowlist"),
 		ntw,
 		func(ctx context.Context, size int) {
 			eventStream.Send(ctx, mtglib.NewEventIPListSize(size, false))
 		},
+		false,
 	)
 	if err != nil {
 		return fmt.Errorf("cannot build ip allowlist: %w", err)
 	}
 
-	doppelGangerURLs := make([]string, len(conf.Defense.Doppelganger.URLs))
-	for i, v := range conf.Defense.Doppelganger.URLs {
-		doppelGangerURLs[i] = v.String()
+	ipBanlist, err := makeIPBanlist(
+		conf,
+		logger.Named("banlist"),
+		func(ctx context.Context, size int) {
+			eventStream.Send(ctx, mtglib.NewEventIPBanlistSize(size))
+		})
+	if err != nil {
+		return fmt.Errorf("cannot build ip banlist: %w", err)
 	}
 
-	opts := mtglib.ProxyOpts{
+	trafficQuota, err := makeTrafficQuota(conf, logger.Named("quota"))
+	if err != nil {
+		return fmt.Errorf("cannot build traffic quota: %w", err)
+	}
+
+	opts := makeProxyOpts(conf, mtglib.ProxyOpts{
 		Logger:          logger,
 		Network:         ntw,
 		AntiReplayCache: makeAntiReplayCache(conf),
 		IPBlocklist:     blocklist,
 		IPAllowlist:     allowlist,
+		IPBanlist:       ipBanlist,
+		TrafficQuota:    trafficQuota,
 		EventStream:     eventStream,
 
-		Secret:                      conf.Secret,
-		Concurrency:                 conf.GetConcurrency(mtglib.DefaultConcurrency),
-		DomainFrontingPort:          conf.GetDomainFrontingPort(mtglib.DefaultDomainFrontingPort),
-		DomainFrontingIP:            conf.GetDomainFrontingIP(nil),
-		DomainFrontingProxyProtocol: conf.GetDomainFrontingProxyProtocol(false),
-		PreferIP:                    conf.PreferIP.Get(mtglib.DefaultPreferIP),
-		AutoUpdate:                  conf.AutoUpdate.Get(false),
-
-		AllowFallbackOnUnknownDC: conf.AllowFallbackOnUnknownDC.Get(false),
-		TolerateTimeSkewness:     conf.TolerateTimeSkewness.Value,
-		IdleTimeout:              conf.Network.Timeout.Idle.Get(mtglib.DefaultIdleTimeout),
-		HandshakeTimeout:         conf.Network.Timeout.Handshake.Get(mtglib.DefaultHandshakeTimeout),
+		UseMiddleProxy: conf.MiddleProxy.Enabled.Get(false),
+		AdTag:          conf.MiddleProxy.AdTag.Get(nil),
+		PublicIPv4:     conf.PublicIPv4.Get(nil),
+		PublicIPv6:     conf.PublicIPv6.Get(nil),
+	})
 
-		DoppelGangerURLs:    doppelGangerURLs,
-		DoppelGangerPerRaid: conf.Defense.Doppelganger.Repeats.Get(mtglib.DoppelGangerPerRaid),
-		DoppelGangerEach:    conf.Defense.Doppelganger.UpdateEach.Get(mtglib.DoppelGangerEach),
-		DoppelGangerDRS:     conf.Defense.Doppelganger.DRS.Get(false),
+	if asnDatabase != nil {
+		opts.ASNLookup = asnDatabase
 	}
 
 	proxy, err := mtglib.NewProxy(opts)
@@ -364,6 +852,10 @@ func runProxy(conf *config.Config, version string) error { //nolint: funlen, cyc
 		return fmt.Errorf("cannot create a proxy: %w", err)
 	}
 
+	if adminServer != nil {
+		adminServer.SetProxy(proxy)
+	}
+
 	listener, err := utils.NewListener(conf.BindTo.Get(""), 0)
 	if err != nil {
 		return fmt.Errorf("cannot start proxy: %w", err)
@@ -378,12 +870,35 @@ func runProxy(conf *config.Config, version string) error { //nolint: funlen, cyc
 	}
 
 	ctx := utils.RootContext()
+	reloader := &proxyReloader{
+		proxy:         proxy,
+		logger:        logger.Named("reload"),
+		network:       ntw,
+		configPath:    configPath,
+		initialConf:   conf,
+		conf:          conf,
+		opts:          opts,
+		eventStream:   eventStream,
+		geoIPDatabase: geoIPDatabase,
+		asnDatabase:   asnDatabase,
+	}
 
 	go proxy.Serve(listener) //nolint: errcheck
 
-	<-ctx.Done()
-	listener.Close() //nolint: errcheck
-	proxy.Shutdown()
+	reloadSignals := utils.ReloadSignals()
 
-	return nil
+	for {
+		select {
+		case <-ctx.Done():
+			listener.Close() //nolint: errcheck
+			proxy.Shutdown()
+			eventStream.Shutdown()
+
+			return nil
+		case <-reloadSignals:
+			if err := reloader.Reload(); err != nil {
+				reloader.logger.WarningError("cannot reload configuration", err)
+			}
+		}
+	}
 }
@@ -0,0 +1,422 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/9seconds/mtg/v2/admin"
+)
+
+type Top struct {
+	Address  string        `kong:"arg,required,help='Address of admin API: host:port or absolute path to unix socket.',name='address'"` //nolint: lll
+	Interval time.Duration `kong:"help='How often to refresh a view.',default='2s',short='i'"`
+	Limit    int           `kong:"help='How many rows to show in each table.',default='10',short='n'"`
+}
+
+func (t *Top) Run(cli *CLI, version string) error {
+	if t.Interval <= 0 {
+		return fmt.Errorf("interval should be positive, got %v", t.Interval)
+	}
+
+	if t.Limit < 0 {
+		return fmt.Errorf("limit should not be negative, got %d", t.Limit)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	client := admin.NewClient(t.Address)
+	view := &topView{}
+	ticker := time.NewTicker(t.Interval)
+
+	defer ticker.Stop()
+
+	for {
+		snapshot, err := fetchTopSnapshot(ctx, client)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return fmt.Errorf("cannot fetch data from admin api: %w", err)
+		}
+
+		view.update(snapshot)
+		view.render(os.Stdout, t.Address, t.Limit)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+type topSnapshot struct {
+	takenAt  time.Time
+	streams  []admin.Stream
+	counters admin.Counters
+}
+
+func fetchTopSnapshot(ctx context.Context, client *admin.Client) (topSnapshot, error) {
+	snapshot := topSnapshot{}
+
+	streams, err := client.Streams(ctx)
+	if err != nil {
+		return snapshot, err //nolint: wrapcheck
+	}
+
+	counters, err := client.Counters(ctx)
+	if err != nil {
+		return snapshot, err //nolint: wrapcheck
+	}
+
+	snapshot.takenAt = time.Now()
+	snapshot.streams = streams
+	snapshot.counters = counters
+
+	return snapshot, nil
+}
+
+type topStream struct {
+	admin.Stream
+
+	toClientRate   float64
+	fromClientRate float64
+}
+
+func (t topStream) rate() float64 {
+	return t.toClientRate + t.fromClientRate
+}
+
+type topGroup struct {
+	name    string
+	streams int
+	related map[string]struct{}
+	rate    float64
+	traffic uint
+}
+
+func (t *topGroup) add(stream topStream, related string) {
+	t.streams++
+	t.rate += stream.rate()
+	t.traffic += stream.TrafficToClient + stream.TrafficFromClient
+
+	if related != "" {
+		t.related[related] = struct{}{}
+	}
+}
+
+func (t *topGroup) relatedList() string {
+	values := make([]string, 0, len(t.related))
+	for v := range t.related {
+		values = append(values, v)
+	}
+
+	slices.Sort(values)
+
+	return strings.Join(values, ",")
+}
+
+// topView keeps a previous snapshot to calculate rates: throughput of
+// each stream and a rate of events.
+type topView struct {
+	previous topSnapshot
+	current  topSnapshot
+	streams  []topStream
+}
+
+func (t *topView) update(snapshot topSnapshot) {
+	t.previous = t.current
+	t.current = snapshot
+
+	elapsed := t.elapsed()
+	previousStreams := make(map[string]admin.Stream, len(t.previous.streams))
+
+	for _, v := range t.previous.streams {
+		previousStreams[v.ID] = v
+	}
+
+	t.streams = make([]topStream, 0, len(snapshot.streams))
+
+	for _, v := range snapshot.streams {
+		stream := topStream{
+			Stream: v,
+		}
+
+		// new streams have started within a period so all their traffic
+		// counts.
+		previous := previousStreams[v.ID]
+
+		if elapsed > 0 {
+			stream.toClientRate = float64(counterDelta(v.TrafficToClient, previous.TrafficToClient)) / elapsed
+			stream.fromClientRate = float64(counterDelta(v.TrafficFromClient, previous.TrafficFromClient)) / elapsed
+		}
+
+		t.streams = append(t.streams, stream)
+	}
+
+	slices.SortStableFunc(t.streams, func(a, b topStream) int {
+		return cmp.Compare(b.rate(), a.rate())
+	})
+}
+
+// counterDelta returns a growth of a counter. Counters are reset if proxy
+// is restarted, growth is 0 then.
+func counterDelta[T uint | uint64](current, previous T) T {
+	if current < previous {
+		return 0
+	}
+
+	return current - previous
+}
+
+func (t *topView) elapsed() float64 {
+	if t.previous.takenAt.IsZero() {
+		return 0
+	}
+
+	return t.current.takenAt.Sub(t.previous.takenAt).Seconds()
+}
+
+func (t *topView) eventRate(getter func(admin.Counters) uint64) float64 {
+	elapsed := t.elapsed()
+	if elapsed == 0 {
+		return 0
+	}
+
+	return float64(counterDelta(getter(t.current.counters), getter(t.previous.counters))) / elapsed
+}
+
+// failureReasons returns rates of handshake failures by reason, the most
+// frequent first. Reasons which were not seen within a period are
+// skipped.
+func (t *topView) failureReasons() string {
+	elapsed := t.elapsed()
+	if elapsed == 0 {
+		return ""
+	}
+
+	type reasonRate struct {
+		reason string
+		rate   float64
+	}
+
+	rates := []reasonRate{}
+
+	for reason, value := range t.current.counters.HandshakeFailureReasons {
+		if delta := counterDelta(value, t.previous.counters.HandshakeFailureReasons[reason]); delta > 0 {
+			rates = append(rates, reasonRate{
+				reason: string(reason),
+				rate:   float64(delta) / elapsed,
+			})
+		}
+	}
+
+	if len(rates) == 0 {
+		return ""
+	}
+
+	slices.SortFunc(rates, func(a, b reasonRate) int {
+		if rv := cmp.Compare(b.rate, a.rate); rv != 0 {
+			return rv
+		}
+
+		return strings.Compare(a.reason, b.reason)
+	})
+
+	chunks := make([]string, 0, len(rates))
+	for _, v := range rates {
+		chunks = append(chunks, fmt.Sprintf("%s %.1f/s", v.reason, v.rate))
+	}
+
+	return ": " + strings.Join(chunks, ", ")
+}
+
+func (t *topView) groups(keyFunc func(topStream) (string, string)) []*topGroup {
+	index := map[string]*topGroup{}
+	groups := []*topGroup{}
+
+	for _, stream := range t.streams {
+		key, related := keyFunc(stream)
+
+		group, ok := index[key]
+		if !ok {
+			group = &topGroup{
+				name:    key,
+				related: map[string]struct{}{},
+			}
+			index[key] = group
+			groups = append(groups, group)
+		}
+
+		group.add(stream, related)
+	}
+
+	slices.SortStableFunc(groups, func(a, b *topGroup) int {
+		if rv := cmp.Compare(b.rate, a.rate); rv != 0 {
+			return rv
+		}
+
+		return cmp.Compare(b.streams, a.streams)
+	})
+
+	return groups
+}
+
+func (t *topView) render(writer io.Writer, address string, limit int) {
+	var (
+		toClient   float64
+		fromClient float64
+		fronted    int
+	)
+
+	for _, v := range t.streams {
+		toClient += v.toClientRate
+		fromClient += v.fromClientRate
+
+		if v.DomainFronting {
+			fronted++
+		}
+	}
+
+	newStreams := t.eventRate(func(c admin.Counters) uint64 { return c.Streams })
+	failures := t.eventRate(func(c admin.Counters) uint64 { return c.HandshakeFailures })
+	failureShare := 0.0
+
+	if newStreams > 0 {
+		failureShare = 100 * failures / newStreams
+	}
+
+	fmt.Fprint(writer, "\033[H\033[2J")
+	fmt.Fprintf(writer, "mtg top: %s, %s\n\n", address, t.current.takenAt.Format(time.DateTime))
+	fmt.Fprintf(writer,
+		"Streams: %d active, %.1f/s new\n",
+		len(t.streams), newStreams)
+	fmt.Fprintf(writer,
+		"Handshake failures: %.1f/s (%.1f%% of new streams)%s\n",
+		failures, failureShare, t.failureReasons())
+	fmt.Fprintf(writer,
+		"Domain fronting: %d active, %.1f/s new\n",
+		fronted, t.eventRate(func(c admin.Counters) uint64 { return c.DomainFronting }))
+	fmt.Fprintf(writer,
+		"Replay attacks: %.1f/s, rejected by ip lists: %.1f/s, concurrency limited: %.1f/s\n",
+		t.eventRate(func(c admin.Counters) uint64 { return c.ReplayAttacks }),
+		t.eventRate(func(c admin.Counters) uint64 { return c.Rejected }),
+		t.eventRate(func(c admin.Counters) uint64 { return c.ConcurrencyLimited }))
+	fmt.Fprintf(writer,
+		"Throughput: %s to clients, %s from clients\n\n",
+		formatTopRate(toClient), formatTopRate(fromClient))
+
+	rows := [][]string{}
+
+	for _, v := range t.streams[:min(limit, len(t.streams))] {
+		rows = append(rows, []string{
+			v.ID,
+			v.ClientIP.String(),
+			cmp.Or(v.SecretName, "-"),
+			formatTopDC(v.Stream),
+			formatTopRate(v.toClientRate),
+			formatTopRate(v.fromClientRate),
+			v.Age,
+		})
+	}
+
+	writeTopTable(writer,
+		"TOP TALKERS",
+		[]string{"STREAM", "CLIENT IP", "SECRET", "DC", "TO CLIENT", "FROM CLIENT", "AGE"},
+		rows)
+
+	rows = rows[:0]
+	byIP := t.groups(func(stream topStream) (string, string) {
+		return stream.ClientIP.String(), formatTopDC(stream.Stream)
+	})
+
+	for _, v := range byIP[:min(limit, len(byIP))] {
+		rows = append(rows, []string{
+			v.name,
+			strconv.Itoa(v.streams),
+			v.relatedList(),
+			formatTopRate(v.rate),
+			formatTopBytes(float64(v.traffic)),
+		})
+	}
+
+	writeTopTable(writer,
+		"BY CLIENT IP",
+		[]string{"CLIENT IP", "STREAMS", "DCS", "THROUGHPUT", "TRAFFIC"},
+		rows)
+
+	rows = rows[:0]
+	byDC := t.groups(func(stream topStream) (string, string) {
+		return formatTopDC(stream.Stream), stream.ClientIP.String()
+	})
+
+	for _, v := range byDC[:min(limit, len(byDC))] {
+		rows = append(rows, []string{
+			v.name,
+			strconv.Itoa(v.streams),
+			strconv.Itoa(len(v.related)),
+			formatTopRate(v.rate),
+			formatTopBytes(float64(v.traffic)),
+		})
+	}
+
+	writeTopTable(writer,
+		"BY DC",
+		[]string{"DC", "STREAMS", "CLIENTS", "THROUGHPUT", "TRAFFIC"},
+		rows)
+}
+
+func writeTopTable(writer io.Writer, title string, header []string, rows [][]string) {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0) //nolint: mnd
+
+	fmt.Fprintln(table, title)
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+
+	for _, row := range rows {
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+
+	fmt.Fprintln(table)
+	table.Flush() //nolint: errcheck
+}
+
+// formatTopDC returns a DC of a stream. Streams which are not connected
+// to Telegram yet or fronted have no DC.
+func formatTopDC(stream admin.Stream) string {
+	switch {
+	case stream.DomainFronting:
+		return "fronting"
+	case stream.TelegramIP == nil:
+		return "-"
+	}
+
+	return strconv.Itoa(stream.DC)
+}
+
+func formatTopRate(value float64) string {
+	return formatTopBytes(value) + "/s"
+}
+
+func formatTopBytes(value float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB"}
+	unit := 0
+
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+
+	return strconv.FormatFloat(value, 'f', 1, 64) + " " + units[unit]
+}
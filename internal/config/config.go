@@ -3,9 +3,11 @@ package config
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"time"
 
 	"github.com/9seconds/mtg/v2/mtglib"
 )
@@ -22,6 +24,15 @@ type ListConfig struct {
 	UpdateEach          TypeDuration       `json:"updateEach"`
 }
 
+type GeoIPList struct {
+	Countries  []TypeCountryCode   `json:"countries"`
+	Continents []TypeContinentCode `json:"continents"`
+}
+
+func (g GeoIPList) Empty() bool {
+	return len(g.Countries) == 0 && len(g.Continents) == 0
+}
+
 type Config struct {
 	Debug                       TypeBool        `json:"debug"`
 	AllowFallbackOnUnknownDC    TypeBool        `json:"allowFallbackOnUnknownDc"`
@@ -57,6 +68,38 @@ type Config struct {
 			UpdateEach TypeDuration    `json:"raid_each"`
 			DRS        TypeBool        `json:"drs"`
 		} `json:"doppelganger"`
+		ClientLimits struct {
+			ConcurrentPerIP     TypeConcurrency       `json:"concurrentPerIp"`
+			ConcurrentPerSubnet TypeConcurrency       `json:"concurrentPerSubnet"`
+			RatePerIP           TypeConcurrency       `json:"ratePerIp"`
+			RatePerSubnet       TypeConcurrency       `json:"ratePerSubnet"`
+			Action              TypeClientLimitAction `json:"action"`
+		} `json:"clientLimits"`
+		GeoIP struct {
+			Optional
+
+			Database   TypePath     `json:"database"`
+			UpdateEach TypeDuration `json:"updateEach"`
+			Blocklist  GeoIPList    `json:"blocklist"`
+			Allowlist  GeoIPList    `json:"allowlist"`
+		} `json:"geoip"`
+		ASN struct {
+			Optional
+
+			Database   TypePath     `json:"database"`
+			UpdateEach TypeDuration `json:"updateEach"`
+			Blocklist  []TypeASN    `json:"blocklist"`
+			Allowlist  []TypeASN    `json:"allowlist"`
+		} `json:"asn"`
+		AutoBan struct {
+			Optional
+
+			MaxOffenses TypeConcurrency `json:"maxOffenses"`
+			Window      TypeDuration    `json:"window"`
+			BanFor      TypeDuration    `json:"banFor"`
+			StateFile   TypePath        `json:"stateFile"`
+			SaveEach    TypeDuration    `json:"saveEach"`
+		} `json:"autoBan"`
 	} `json:"defense"`
 	Network struct {
 		Timeout struct {
@@ -76,7 +119,8 @@ type Config struct {
 		Proxies []TypeProxyURL `json:"proxies"`
 	} `json:"network"`
 	Stats struct {
-		StatsD struct {
+		EventPolicy TypeEventPolicy `json:"eventPolicy"`
+		StatsD      struct {
 			Optional
 
 			Address      TypeHostPort        `json:"address"`
@@ -90,7 +134,92 @@ type Config struct {
 			HTTPPath     TypeHTTPPath     `json:"httpPath"`
 			MetricPrefix TypeMetricPrefix `json:"metricPrefix"`
 		} `json:"prometheus"`
+		OTLP struct {
+			Optional
+
+			Endpoint     TypeHTTPURL      `json:"endpoint"`
+			PushInterval TypeDuration     `json:"pushInterval"`
+			MetricPrefix TypeMetricPrefix `json:"metricPrefix"`
+		} `json:"otlp"`
 	} `json:"stats"`
+	Secrets map[string]mtglib.Secret `json:"secrets"`
+	Quota   struct {
+		StateFile TypePath     `json:"stateFile"`
+		SaveEach  TypeDuration `json:"saveEach"`
+		Secrets   map[string]struct {
+			Daily      TypeBytes `json:"daily"`
+			Monthly    TypeBytes `json:"monthly"`
+			Throughput TypeBytes `json:"throughput"`
+		} `json:"secrets"`
+	} `json:"quota"`
+	SecretValidity map[string]struct {
+		NotBefore TypeTime `json:"notBefore"`
+		NotAfter  TypeTime `json:"notAfter"`
+	} `json:"secretValidity"`
+	Rotation struct {
+		PreviousSecret mtglib.Secret `json:"previousSecret"`
+		GracePeriod    TypeDuration  `json:"gracePeriod"`
+		RotatedAt      TypeTime      `json:"rotatedAt"`
+	} `json:"rotation"`
+	MiddleProxy struct {
+		Optional
+
+		AdTag TypeAdTag `json:"adTag"`
+	} `json:"middleProxy"`
+	AutoUpdateFirst TypeBool `json:"autoUpdateFirst"`
+	Telegram        struct {
+		ReplaceDC TypeBool                `json:"replaceDc"`
+		DC        map[int][]TypeDCAddress `json:"dc"`
+		WarmPool  struct {
+			Optional
+
+			MaxSize TypeConcurrency `json:"maxSize"`
+			MaxIdle TypeDuration    `json:"maxIdle"`
+		} `json:"warmPool"`
+		Probe struct {
+			Optional
+
+			Interval TypeDuration `json:"interval"`
+		} `json:"probe"`
+	} `json:"telegram"`
+	Admin struct {
+		Optional
+
+		BindTo     TypeHostPort    `json:"bindTo"`
+		UnixSocket TypePath        `json:"unixSocket"`
+		Token      TypeBearerToken `json:"token"`
+	} `json:"admin"`
+	AccessLog struct {
+		Optional
+
+		Path       TypePath            `json:"path"`
+		Format     TypeAccessLogFormat `json:"format"`
+		MaxSize    TypeBytes           `json:"maxSize"`
+		RotateEach TypeDuration        `json:"rotateEach"`
+		MaxBackups TypeCount           `json:"maxBackups"`
+	} `json:"accessLog"`
+	EventLog struct {
+		Optional
+
+		Path       TypePath        `json:"path"`
+		UnixSocket TypePath        `json:"unixSocket"`
+		BufferSize TypeConcurrency `json:"bufferSize"`
+	} `json:"eventLog"`
+	Webhook struct {
+		Optional
+
+		URLs       []TypeHTTPURL `json:"urls"`
+		Window     TypeDuration  `json:"window"`
+		Cooldown   TypeDuration  `json:"cooldown"`
+		BatchEach  TypeDuration  `json:"batchEach"`
+		Thresholds struct {
+			ReplayAttacks      TypeConcurrency `json:"replayAttacks"`
+			IPBlocklisted      TypeConcurrency `json:"ipBlocklisted"`
+			ConcurrencyLimited TypeConcurrency `json:"concurrencyLimited"`
+			HandshakeFailures  TypeConcurrency `json:"handshakeFailures"`
+			BlocklistEmpty     TypeBool        `json:"blocklistEmpty"`
+		} `json:"thresholds"`
+	} `json:"webhook"`
 }
 
 func (c *Config) GetConcurrency(defaultValue uint) uint {
@@ -131,11 +260,207 @@ func (c *Config) GetDomainFrontingProxyProtocol(defaultValue bool) bool {
 	return c.DomainFronting.ProxyProtocol.Get(false) || c.DomainFrontingProxyProtocol.Get(defaultValue)
 }
 
+func (c *Config) GetSecretHost() string {
+	if c.Secret.Valid() {
+		return c.Secret.Host
+	}
+
+	for _, v := range c.Secrets {
+		return v.Host
+	}
+
+	return ""
+}
+
+func (c *Config) GetSecret() mtglib.Secret {
+	return c.withValidity(mtglib.DefaultSecretName, c.Secret)
+}
+
+func (c *Config) GetSecrets() map[string]mtglib.Secret {
+	secrets := make(map[string]mtglib.Secret, len(c.Secrets))
+
+	for name, secret := range c.Secrets {
+		secrets[name] = c.withValidity(name, secret)
+	}
+
+	return secrets
+}
+
+func (c *Config) GetPreviousSecret(gracePeriod time.Duration) mtglib.Secret {
+	if !c.Rotation.PreviousSecret.Valid() {
+		return mtglib.Secret{}
+	}
+
+	secret := c.withValidity(mtglib.PreviousSecretName, c.Rotation.PreviousSecret)
+	graceEnd := c.Rotation.RotatedAt.Get(time.Time{}).Add(c.Rotation.GracePeriod.Get(gracePeriod))
+
+	if secret.NotAfter.IsZero() || secret.NotAfter.After(graceEnd) {
+		secret.NotAfter = graceEnd
+	}
+
+	return secret
+}
+
+func (c *Config) GetDCOverrides() map[int][]string {
+	if len(c.Telegram.DC) == 0 {
+		return nil
+	}
+
+	overrides := make(map[int][]string, len(c.Telegram.DC))
+
+	for dc, addrs := range c.Telegram.DC {
+		for _, addr := range addrs {
+			overrides[dc] = append(overrides[dc], addr.Get(""))
+		}
+	}
+
+	return overrides
+}
+
+func (c *Config) GetWarmPoolSize(defaultValue uint) uint {
+	if !c.Telegram.WarmPool.Enabled.Get(false) {
+		return 0
+	}
+
+	return c.Telegram.WarmPool.MaxSize.Get(defaultValue)
+}
+
+func (c *Config) GetDCProbeEach(defaultValue time.Duration) time.Duration {
+	if !c.Telegram.Probe.Enabled.Get(false) {
+		return 0
+	}
+
+	return c.Telegram.Probe.Interval.Get(defaultValue)
+}
+
+func (c *Config) withValidity(name string, secret mtglib.Secret) mtglib.Secret {
+	if value, ok := c.SecretValidity[name]; ok {
+		secret.NotBefore = value.NotBefore.Get(time.Time{})
+		secret.NotAfter = value.NotAfter.Get(time.Time{})
+	}
+
+	return secret
+}
+
+func (c *Config) hasSecret(name string) bool {
+	if _, ok := c.Secrets[name]; ok {
+		return true
+	}
+
+	switch name {
+	case mtglib.DefaultSecretName:
+		return c.Secret.Valid()
+	case mtglib.PreviousSecretName:
+		return c.Rotation.PreviousSecret.Valid()
+	}
+
+	return false
+}
+
 func (c *Config) Validate() error {
-	if !c.Secret.Valid() {
+	if !c.Secret.Valid() && len(c.Secrets) == 0 {
 		return fmt.Errorf("invalid secret %s", c.Secret.String())
 	}
 
+	host := c.GetSecretHost()
+
+	for name, secret := range c.Secrets {
+		switch {
+		case name == "":
+			return errors.New("secret name cannot be empty")
+		case name == mtglib.DefaultSecretName && c.Secret.Valid():
+			return fmt.Errorf("secret name %s is reserved for secret option", name)
+		case !secret.Valid():
+			return fmt.Errorf("invalid secret %s: %s", name, secret.String())
+		case secret.Host != host:
+			return fmt.Errorf("secret %s has hostname %s but %s is expected", name, secret.Host, host)
+		}
+	}
+
+	if previous := c.Rotation.PreviousSecret; previous.Valid() {
+		switch {
+		case !c.Secret.Valid():
+			return errors.New("previous secret can be used only with secret option")
+		case previous.Host != c.Secret.Host:
+			return fmt.Errorf("previous secret has hostname %s but %s is expected", previous.Host, c.Secret.Host)
+		case previous.Key == c.Secret.Key:
+			return errors.New("previous secret is the same as current one")
+		case c.Rotation.RotatedAt.Get(time.Time{}).IsZero():
+			return errors.New("previous secret requires rotated-at parameter")
+		}
+	}
+
+	if _, ok := c.Secrets[mtglib.PreviousSecretName]; ok {
+		return fmt.Errorf("secret name %s is reserved for previous secret", mtglib.PreviousSecretName)
+	}
+
+	for name := range c.Quota.Secrets {
+		if !c.hasSecret(name) {
+			return fmt.Errorf("quota is defined for unknown secret %s", name)
+		}
+	}
+
+	for name, value := range c.SecretValidity {
+		notBefore := value.NotBefore.Get(time.Time{})
+		notAfter := value.NotAfter.Get(time.Time{})
+
+		switch {
+		case !c.hasSecret(name):
+			return fmt.Errorf("validity is defined for unknown secret %s", name)
+		case !notBefore.IsZero() && !notAfter.IsZero() && !notAfter.After(notBefore):
+			return fmt.Errorf("secret %s expires before it becomes active", name)
+		}
+	}
+
+	for dc, addrs := range c.Telegram.DC {
+		switch {
+		case dc == 0:
+			return errors.New("DC 0 does not exist")
+		case len(addrs) == 0:
+			return fmt.Errorf("no addresses are defined for DC %d", dc)
+		}
+	}
+
+	if len(c.MiddleProxy.AdTag.Get(nil)) > 0 && !c.MiddleProxy.Enabled.Get(false) {
+		return errors.New("ad tag can be used only with middle proxies")
+	}
+
+	if c.Defense.GeoIP.Enabled.Get(false) && c.Defense.GeoIP.Database.Get("") == "" {
+		return errors.New("geoip requires database parameter")
+	}
+
+	if c.Defense.ASN.Enabled.Get(false) && c.Defense.ASN.Database.Get("") == "" {
+		return errors.New("asn requires database parameter")
+	}
+
+	if c.Stats.OTLP.Enabled.Get(false) && c.Stats.OTLP.Endpoint.Get(nil) == nil {
+		return errors.New("otlp exporter requires endpoint parameter")
+	}
+
+	if c.Admin.Enabled.Get(false) && c.Admin.BindTo.Get("") == "" && c.Admin.UnixSocket.Get("") == "" {
+		return errors.New("admin api requires either bind-to or unix-socket parameter")
+	}
+
+	if c.AccessLog.Enabled.Get(false) && c.AccessLog.Path.Get("") == "" {
+		return errors.New("access log requires path parameter")
+	}
+
+	if c.EventLog.Enabled.Get(false) {
+		path := c.EventLog.Path.Get("")
+		unixSocket := c.EventLog.UnixSocket.Get("")
+
+		switch {
+		case path == "" && unixSocket == "":
+			return errors.New("event log requires either path or unix-socket parameter")
+		case path != "" && unixSocket != "":
+			return errors.New("event log requires only one of path or unix-socket parameters")
+		}
+	}
+
+	if c.Webhook.Enabled.Get(false) && len(c.Webhook.URLs) == 0 {
+		return errors.New("webhook requires urls parameter")
+	}
+
 	if c.BindTo.Get("") == "" {
 		return fmt.Errorf("incorrect bind-to parameter %s", c.BindTo.String())
 	}
@@ -4,7 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/9seconds/mtg/v2/accesslog"
 	"github.com/9seconds/mtg/v2/internal/config"
 	"github.com/stretchr/testify/suite"
 )
@@ -68,6 +70,310 @@ func (suite *ConfigTestSuite) TestParsePublicIPNotSet() {
 	suite.Nil(conf.PublicIPv6.Get(nil))
 }
 
+func (suite *ConfigTestSuite) TestParseSecrets() {
+	conf, err := config.Parse(suite.ReadConfig("secrets.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.False(conf.Secret.Valid())
+	suite.Len(conf.Secrets, 2)
+	suite.Equal("7oe1GqLy6TBc38CV3jx7q09nb29nbGUuY29t", conf.Secrets["team1"].Base64())
+	suite.Equal("google.com", conf.GetSecretHost())
+}
+
+func (suite *ConfigTestSuite) TestParseSecretsHostMismatch() {
+	conf, err := config.Parse(suite.ReadConfig("secrets_host_mismatch.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseQuota() {
+	conf, err := config.Parse(suite.ReadConfig("quota.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.Equal("/var/lib/mtg/quota.json", conf.Quota.StateFile.Get(""))
+	suite.Equal(30*time.Second, conf.Quota.SaveEach.Get(0))
+	suite.Len(conf.Quota.Secrets, 1)
+	suite.EqualValues(1024*1024*1024, conf.Quota.Secrets["team1"].Daily.Get(0))
+	suite.EqualValues(20*1024*1024*1024, conf.Quota.Secrets["team1"].Monthly.Get(0))
+	suite.EqualValues(1024*1024, conf.Quota.Secrets["team1"].Throughput.Get(0))
+}
+
+func (suite *ConfigTestSuite) TestParseQuotaUnknownSecret() {
+	conf, err := config.Parse(suite.ReadConfig("quota_unknown_secret.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseSecretValidity() {
+	conf, err := config.Parse(suite.ReadConfig("secret_validity.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+
+	secret := conf.GetSecret()
+	suite.True(secret.NotBefore.IsZero())
+	suite.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), secret.NotAfter)
+
+	trial := conf.GetSecrets()["trial"]
+	suite.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), trial.NotBefore)
+	suite.Equal(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), trial.NotAfter)
+	suite.True(conf.Secrets["trial"].NotAfter.IsZero())
+}
+
+func (suite *ConfigTestSuite) TestParseSecretValidityInverted() {
+	conf, err := config.Parse(suite.ReadConfig("secret_validity_inverted.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseRotation() {
+	conf, err := config.Parse(suite.ReadConfig("rotation.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+
+	previous := conf.GetPreviousSecret(time.Hour)
+	suite.Equal("ee473ce5d4958eb5f968c87680a23854a0676f6f676c652e636f6d", previous.Hex())
+	suite.Equal(time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC), previous.NotAfter)
+}
+
+func (suite *ConfigTestSuite) TestParseRotationDefaults() {
+	conf, err := config.Parse(suite.ReadConfig("minimal.toml"))
+	suite.NoError(err)
+	suite.False(conf.GetPreviousSecret(time.Hour).Valid())
+}
+
+func (suite *ConfigTestSuite) TestParseRotationWithoutSecret() {
+	conf, err := config.Parse(suite.ReadConfig("rotation_without_secret.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseRotationWithoutRotatedAt() {
+	conf, err := config.Parse(suite.ReadConfig("rotation_without_rotated_at.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseMiddleProxy() {
+	conf, err := config.Parse(suite.ReadConfig("middle_proxy.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.True(conf.MiddleProxy.Enabled.Get(false))
+	suite.Equal("0123456789abcdef0123456789abcdef", conf.MiddleProxy.AdTag.String())
+}
+
+func (suite *ConfigTestSuite) TestParseAdTagWithoutMiddleProxy() {
+	conf, err := config.Parse(suite.ReadConfig("ad_tag_without_middle_proxy.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseAutoUpdate() {
+	conf, err := config.Parse(suite.ReadConfig("auto_update.toml"))
+	suite.NoError(err)
+	suite.True(conf.AutoUpdate.Get(false))
+	suite.True(conf.AutoUpdateFirst.Get(false))
+}
+
+func (suite *ConfigTestSuite) TestParseTelegramDC() {
+	conf, err := config.Parse(suite.ReadConfig("telegram_dc.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.True(conf.Telegram.ReplaceDC.Get(false))
+	suite.Equal(map[int][]string{
+		2:     {"149.154.167.51:443", "[2001:67c:04e8:f002::a]:443"},
+		-2:    {"149.154.167.151:443"},
+		10002: {"127.0.0.1:4430"},
+	}, conf.GetDCOverrides())
+}
+
+func (suite *ConfigTestSuite) TestParseTelegramDCHostname() {
+	conf, err := config.Parse(suite.ReadConfig("telegram_dc_hostname.toml"))
+	suite.NoError(err)
+	suite.Equal(map[int][]string{
+		2: {"telegram.org:443"},
+	}, conf.GetDCOverrides())
+}
+
+func (suite *ConfigTestSuite) TestParseTelegramDCIncorrect() {
+	_, err := config.Parse(suite.ReadConfig("telegram_dc_incorrect.toml"))
+	suite.Error(err)
+}
+
+func (suite *ConfigTestSuite) TestParseTelegramDCDefaults() {
+	conf, err := config.Parse(suite.ReadConfig("minimal.toml"))
+	suite.NoError(err)
+	suite.Nil(conf.GetDCOverrides())
+}
+
+func (suite *ConfigTestSuite) TestParseWarmPool() {
+	conf, err := config.Parse(suite.ReadConfig("warm_pool.toml"))
+	suite.NoError(err)
+	suite.EqualValues(4, conf.GetWarmPoolSize(8))
+	suite.Equal(5*time.Second, conf.Telegram.WarmPool.MaxIdle.Get(time.Second))
+}
+
+func (suite *ConfigTestSuite) TestParseWarmPoolDefaults() {
+	conf, err := config.Parse(suite.ReadConfig("minimal.toml"))
+	suite.NoError(err)
+	suite.Zero(conf.GetWarmPoolSize(8))
+}
+
+func (suite *ConfigTestSuite) TestParseDCProbe() {
+	conf, err := config.Parse(suite.ReadConfig("dc_probe.toml"))
+	suite.NoError(err)
+	suite.Equal(30*time.Second, conf.GetDCProbeEach(time.Minute))
+}
+
+func (suite *ConfigTestSuite) TestParseDCProbeDefaults() {
+	conf, err := config.Parse(suite.ReadConfig("minimal.toml"))
+	suite.NoError(err)
+	suite.Zero(conf.GetDCProbeEach(time.Minute))
+}
+
+func (suite *ConfigTestSuite) TestParseAdmin() {
+	conf, err := config.Parse(suite.ReadConfig("admin.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.True(conf.Admin.Enabled.Get(false))
+	suite.Equal("127.0.0.1:3130", conf.Admin.BindTo.Get(""))
+	suite.Equal("/run/mtg/admin.sock", conf.Admin.UnixSocket.Get(""))
+	suite.Equal("s3cr3t", conf.Admin.Token.Get(""))
+}
+
+func (suite *ConfigTestSuite) TestParseAdminNoBindTo() {
+	conf, err := config.Parse(suite.ReadConfig("admin_no_bind_to.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseAccessLog() {
+	conf, err := config.Parse(suite.ReadConfig("access_log.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.True(conf.AccessLog.Enabled.Get(false))
+	suite.Equal("/var/log/mtg/access.log", conf.AccessLog.Path.Get(""))
+	suite.Equal(accesslog.FormatLogfmt, conf.AccessLog.Format.Get(""))
+	suite.EqualValues(10*1024*1024, conf.AccessLog.MaxSize.Get(0))
+	suite.Equal(time.Hour, conf.AccessLog.RotateEach.Get(0))
+	suite.EqualValues(3, conf.AccessLog.MaxBackups.Get(0))
+}
+
+func (suite *ConfigTestSuite) TestParseOTLP() {
+	conf, err := config.Parse(suite.ReadConfig("otlp.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.True(conf.Stats.OTLP.Enabled.Get(false))
+	suite.Equal("http://otel-collector:4318", conf.Stats.OTLP.Endpoint.String())
+	suite.Equal(30*time.Second, conf.Stats.OTLP.PushInterval.Get(0))
+	suite.Equal("proxy", conf.Stats.OTLP.MetricPrefix.Get(""))
+	suite.Equal(config.TypeEventPolicyDropNewest, conf.Stats.EventPolicy.Get(""))
+}
+
+func (suite *ConfigTestSuite) TestParseOTLPNoEndpoint() {
+	conf, err := config.Parse(suite.ReadConfig("otlp_no_endpoint.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseEventLog() {
+	conf, err := config.Parse(suite.ReadConfig("event_log.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.True(conf.EventLog.Enabled.Get(false))
+	suite.Empty(conf.EventLog.Path.Get(""))
+	suite.Equal("/run/mtg/events.sock", conf.EventLog.UnixSocket.Get(""))
+	suite.EqualValues(1024, conf.EventLog.BufferSize.Get(0))
+}
+
+func (suite *ConfigTestSuite) TestParseEventLogManyTargets() {
+	conf, err := config.Parse(suite.ReadConfig("event_log_many_targets.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseWebhook() {
+	conf, err := config.Parse(suite.ReadConfig("webhook.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.True(conf.Webhook.Enabled.Get(false))
+	suite.Len(conf.Webhook.URLs, 2)
+	suite.Equal("https://hooks.example.com/mtg", conf.Webhook.URLs[0].String())
+	suite.Equal(5*time.Minute, conf.Webhook.Window.Get(0))
+	suite.Equal(time.Hour, conf.Webhook.Cooldown.Get(0))
+	suite.Equal(30*time.Second, conf.Webhook.BatchEach.Get(0))
+	suite.EqualValues(10, conf.Webhook.Thresholds.ReplayAttacks.Get(0))
+	suite.EqualValues(0, conf.Webhook.Thresholds.IPBlocklisted.Get(0))
+	suite.EqualValues(100, conf.Webhook.Thresholds.HandshakeFailures.Get(0))
+	suite.True(conf.Webhook.Thresholds.BlocklistEmpty.Get(false))
+}
+
+func (suite *ConfigTestSuite) TestParseWebhookNoURLs() {
+	conf, err := config.Parse(suite.ReadConfig("webhook_no_urls.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseClientLimits() {
+	conf, err := config.Parse(suite.ReadConfig("client_limits.toml"))
+	suite.NoError(err)
+	suite.EqualValues(16, conf.Defense.ClientLimits.ConcurrentPerIP.Get(0))
+	suite.EqualValues(64, conf.Defense.ClientLimits.ConcurrentPerSubnet.Get(0))
+	suite.EqualValues(5, conf.Defense.ClientLimits.RatePerIP.Get(0))
+	suite.EqualValues(0, conf.Defense.ClientLimits.RatePerSubnet.Get(0))
+	suite.Equal(config.TypeClientLimitActionDomainFronting,
+		conf.Defense.ClientLimits.Action.Get(config.TypeClientLimitActionDrop))
+}
+
+func (suite *ConfigTestSuite) TestParseAutoBan() {
+	conf, err := config.Parse(suite.ReadConfig("auto_ban.toml"))
+	suite.NoError(err)
+	suite.True(conf.Defense.AutoBan.Enabled.Get(false))
+	suite.EqualValues(5, conf.Defense.AutoBan.MaxOffenses.Get(0))
+	suite.Equal(30*time.Second, conf.Defense.AutoBan.Window.Get(0))
+	suite.Equal(6*time.Hour, conf.Defense.AutoBan.BanFor.Get(0))
+	suite.Equal("/var/lib/mtg/bans.json", conf.Defense.AutoBan.StateFile.Get(""))
+	suite.Equal(time.Minute, conf.Defense.AutoBan.SaveEach.Get(time.Minute))
+}
+
+func (suite *ConfigTestSuite) TestParseGeoIP() {
+	conf, err := config.Parse(suite.ReadConfig("geoip.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.True(conf.Defense.GeoIP.Enabled.Get(false))
+	suite.Equal("/var/lib/GeoIP/GeoLite2-Country.mmdb", conf.Defense.GeoIP.Database.Get(""))
+	suite.Equal(10*time.Minute, conf.Defense.GeoIP.UpdateEach.Get(0))
+	suite.Len(conf.Defense.GeoIP.Blocklist.Countries, 2)
+	suite.Equal("CN", conf.Defense.GeoIP.Blocklist.Countries[0].Get(""))
+	suite.Empty(conf.Defense.GeoIP.Blocklist.Continents)
+	suite.False(conf.Defense.GeoIP.Blocklist.Empty())
+	suite.Equal("EU", conf.Defense.GeoIP.Allowlist.Continents[0].Get(""))
+}
+
+func (suite *ConfigTestSuite) TestParseGeoIPNoDatabase() {
+	conf, err := config.Parse(suite.ReadConfig("geoip_no_database.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
+func (suite *ConfigTestSuite) TestParseASN() {
+	conf, err := config.Parse(suite.ReadConfig("asn.toml"))
+	suite.NoError(err)
+	suite.NoError(conf.Validate())
+	suite.True(conf.Defense.ASN.Enabled.Get(false))
+	suite.Equal("/var/lib/GeoIP/GeoLite2-ASN.mmdb", conf.Defense.ASN.Database.Get(""))
+	suite.Equal(10*time.Minute, conf.Defense.ASN.UpdateEach.Get(0))
+	suite.Len(conf.Defense.ASN.Blocklist, 2)
+	suite.EqualValues(14061, conf.Defense.ASN.Blocklist[0].Get(0))
+	suite.EqualValues(16509, conf.Defense.ASN.Blocklist[1].Get(0))
+	suite.Empty(conf.Defense.ASN.Allowlist)
+}
+
+func (suite *ConfigTestSuite) TestParseASNNoDatabase() {
+	conf, err := config.Parse(suite.ReadConfig("asn_no_database.toml"))
+	suite.NoError(err)
+	suite.Error(conf.Validate())
+}
+
 func (suite *ConfigTestSuite) TestString() {
 	conf, err := config.Parse(suite.ReadConfig("minimal.toml"))
 	suite.NoError(err)
@@ -11,7 +11,7 @@ import (
 type tomlConfig struct {
 	Debug                       bool   `toml:"debug" json:"debug,omitempty"`
 	AllowFallbackOnUnknownDC    bool   `toml:"allow-fallback-on-unknown-dc" json:"allowFallbackOnUnknownDc,omitempty"`
-	Secret                      string `toml:"secret" json:"secret"`
+	Secret                      string `toml:"secret" json:"secret,omitempty"`
 	BindTo                      string `toml:"bind-to" json:"bindTo"`
 	ProxyProtocolListener       bool   `toml:"proxy-protocol-listener" json:"proxyProtocolListener"`
 	PreferIP                    string `toml:"prefer-ip" json:"preferIp,omitempty"`
@@ -52,6 +52,41 @@ type tomlConfig struct {
 			UpdateEach string   `toml:"raid-each" json:"raid_each,omitempty"`
 			DRS        bool     `toml:"drs" json:"drs,omitempty"`
 		} `toml:"doppelganger" json:"doppelganger,omitempty"`
+		ClientLimits struct {
+			ConcurrentPerIP     uint   `toml:"concurrent-per-ip" json:"concurrentPerIp,omitempty"`
+			ConcurrentPerSubnet uint   `toml:"concurrent-per-subnet" json:"concurrentPerSubnet,omitempty"`
+			RatePerIP           uint   `toml:"rate-per-ip" json:"ratePerIp,omitempty"`
+			RatePerSubnet       uint   `toml:"rate-per-subnet" json:"ratePerSubnet,omitempty"`
+			Action              string `toml:"action" json:"action,omitempty"`
+		} `toml:"client-limits" json:"clientLimits,omitempty"`
+		GeoIP struct {
+			Enabled    bool   `toml:"enabled" json:"enabled,omitempty"`
+			Database   string `toml:"database" json:"database,omitempty"`
+			UpdateEach string `toml:"update-each" json:"updateEach,omitempty"`
+			Blocklist  struct {
+				Countries  []string `toml:"countries" json:"countries,omitempty"`
+				Continents []string `toml:"continents" json:"continents,omitempty"`
+			} `toml:"blocklist" json:"blocklist,omitempty"`
+			Allowlist struct {
+				Countries  []string `toml:"countries" json:"countries,omitempty"`
+				Continents []string `toml:"continents" json:"continents,omitempty"`
+			} `toml:"allowlist" json:"allowlist,omitempty"`
+		} `toml:"geoip" json:"geoip,omitempty"`
+		ASN struct {
+			Enabled    bool   `toml:"enabled" json:"enabled,omitempty"`
+			Database   string `toml:"database" json:"database,omitempty"`
+			UpdateEach string `toml:"update-each" json:"updateEach,omitempty"`
+			Blocklist  []uint `toml:"blocklist" json:"blocklist,omitempty"`
+			Allowlist  []uint `toml:"allowlist" json:"allowlist,omitempty"`
+		} `toml:"asn" json:"asn,omitempty"`
+		AutoBan struct {
+			Enabled     bool   `toml:"enabled" json:"enabled,omitempty"`
+			MaxOffenses uint   `toml:"max-offenses" json:"maxOffenses,omitempty"`
+			Window      string `toml:"window" json:"window,omitempty"`
+			BanFor      string `toml:"ban-for" json:"banFor,omitempty"`
+			StateFile   string `toml:"state-file" json:"stateFile,omitempty"`
+			SaveEach    string `toml:"save-each" json:"saveEach,omitempty"`
+		} `toml:"auto-ban" json:"autoBan,omitempty"`
 	} `toml:"defense" json:"defense,omitempty"`
 	Network struct {
 		Timeout struct {
@@ -71,7 +106,8 @@ type tomlConfig struct {
 		Proxies []string `toml:"proxies" json:"proxies,omitempty"`
 	} `toml:"network" json:"network,omitempty"`
 	Stats struct {
-		StatsD struct {
+		EventPolicy string `toml:"event-policy" json:"eventPolicy,omitempty"`
+		StatsD      struct {
 			Enabled      bool   `toml:"enabled" json:"enabled,omitempty"`
 			Address      string `toml:"address" json:"address,omitempty"`
 			MetricPrefix string `toml:"metric-prefix" json:"metricPrefix,omitempty"`
@@ -83,7 +119,84 @@ type tomlConfig struct {
 			HTTPPath     string `toml:"http-path" json:"httpPath,omitempty"`
 			MetricPrefix string `toml:"metric-prefix" json:"metricPrefix,omitempty"`
 		} `toml:"prometheus" json:"prometheus,omitempty"`
+		OTLP struct {
+			Enabled      bool   `toml:"enabled" json:"enabled,omitempty"`
+			Endpoint     string `toml:"endpoint" json:"endpoint,omitempty"`
+			PushInterval string `toml:"push-interval" json:"pushInterval,omitempty"`
+			MetricPrefix string `toml:"metric-prefix" json:"metricPrefix,omitempty"`
+		} `toml:"otlp" json:"otlp,omitempty"`
 	} `toml:"stats" json:"stats,omitempty"`
+	Secrets map[string]string `toml:"secrets" json:"secrets,omitempty"`
+	Quota   struct {
+		StateFile string `toml:"state-file" json:"stateFile,omitempty"`
+		SaveEach  string `toml:"save-each" json:"saveEach,omitempty"`
+		Secrets   map[string]struct {
+			Daily      string `toml:"daily" json:"daily,omitempty"`
+			Monthly    string `toml:"monthly" json:"monthly,omitempty"`
+			Throughput string `toml:"throughput" json:"throughput,omitempty"`
+		} `toml:"secrets" json:"secrets,omitempty"`
+	} `toml:"quota" json:"quota,omitempty"`
+	SecretValidity map[string]struct {
+		NotBefore string `toml:"not-before" json:"notBefore,omitempty"`
+		NotAfter  string `toml:"not-after" json:"notAfter,omitempty"`
+	} `toml:"secret-validity" json:"secretValidity,omitempty"`
+	Rotation struct {
+		PreviousSecret string `toml:"previous-secret" json:"previousSecret,omitempty"`
+		GracePeriod    string `toml:"grace-period" json:"gracePeriod,omitempty"`
+		RotatedAt      string `toml:"rotated-at" json:"rotatedAt,omitempty"`
+	} `toml:"rotation" json:"rotation,omitempty"`
+	MiddleProxy struct {
+		Enabled bool   `toml:"enabled" json:"enabled,omitempty"`
+		AdTag   string `toml:"ad-tag" json:"adTag,omitempty"`
+	} `toml:"middle-proxy" json:"middleProxy,omitempty"`
+	AutoUpdateFirst bool `toml:"auto-update-first" json:"autoUpdateFirst,omitempty"`
+	Telegram        struct {
+		ReplaceDC bool                `toml:"replace-dc" json:"replaceDc,omitempty"`
+		DC        map[string][]string `toml:"dc" json:"dc,omitempty"`
+		WarmPool  struct {
+			Enabled bool   `toml:"enabled" json:"enabled,omitempty"`
+			MaxSize uint   `toml:"max-size" json:"maxSize,omitempty"`
+			MaxIdle string `toml:"max-idle" json:"maxIdle,omitempty"`
+		} `toml:"warm-pool" json:"warmPool,omitempty"`
+		Probe struct {
+			Enabled  bool   `toml:"enabled" json:"enabled,omitempty"`
+			Interval string `toml:"interval" json:"interval,omitempty"`
+		} `toml:"probe" json:"probe,omitempty"`
+	} `toml:"telegram" json:"telegram,omitempty"`
+	Admin struct {
+		Enabled    bool   `toml:"enabled" json:"enabled,omitempty"`
+		BindTo     string `toml:"bind-to" json:"bindTo,omitempty"`
+		UnixSocket string `toml:"unix-socket" json:"unixSocket,omitempty"`
+		Token      string `toml:"token" json:"token,omitempty"`
+	} `toml:"admin" json:"admin,omitempty"`
+	AccessLog struct {
+		Enabled    bool   `toml:"enabled" json:"enabled,omitempty"`
+		Path       string `toml:"path" json:"path,omitempty"`
+		Format     string `toml:"format" json:"format,omitempty"`
+		MaxSize    string `toml:"max-size" json:"maxSize,omitempty"`
+		RotateEach string `toml:"rotate-each" json:"rotateEach,omitempty"`
+		MaxBackups uint   `toml:"max-backups" json:"maxBackups,omitempty"`
+	} `toml:"access-log" json:"accessLog,omitempty"`
+	EventLog struct {
+		Enabled    bool   `toml:"enabled" json:"enabled,omitempty"`
+		Path       string `toml:"path" json:"path,omitempty"`
+		UnixSocket string `toml:"unix-socket" json:"unixSocket,omitempty"`
+		BufferSize uint   `toml:"buffer-size" json:"bufferSize,omitempty"`
+	} `toml:"event-log" json:"eventLog,omitempty"`
+	Webhook struct {
+		Enabled    bool     `toml:"enabled" json:"enabled,omitempty"`
+		URLs       []string `toml:"urls" json:"urls,omitempty"`
+		Window     string   `toml:"window" json:"window,omitempty"`
+		Cooldown   string   `toml:"cooldown" json:"cooldown,omitempty"`
+		BatchEach  string   `toml:"batch-each" json:"batchEach,omitempty"`
+		Thresholds struct {
+			ReplayAttacks      uint `toml:"replay-attacks" json:"replayAttacks,omitempty"`
+			IPBlocklisted      uint `toml:"ip-blocklisted" json:"ipBlocklisted,omitempty"`
+			ConcurrencyLimited uint `toml:"concurrency-limited" json:"concurrencyLimited,omitempty"`
+			HandshakeFailures  uint `toml:"handshake-failures" json:"handshakeFailures,omitempty"`
+			BlocklistEmpty     bool `toml:"blocklist-empty" json:"blocklistEmpty,omitempty"`
+		} `toml:"thresholds" json:"thresholds,omitempty"`
+	} `toml:"webhook" json:"webhook,omitempty"`
 }
 
 func Parse(rawData []byte) (*Config, error) {
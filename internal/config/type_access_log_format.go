@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/9seconds/mtg/v2/accesslog"
+)
+
+type TypeAccessLogFormat struct {
+	Value string
+}
+
+func (t *TypeAccessLogFormat) Set(value string) error {
+	lowercasedValue := strings.ToLower(value)
+
+	switch lowercasedValue {
+	case accesslog.FormatJSON, accesslog.FormatLogfmt:
+		t.Value = lowercasedValue
+
+		return nil
+	default:
+		return fmt.Errorf("unknown access log format %s", value)
+	}
+}
+
+func (t TypeAccessLogFormat) Get(defaultValue string) string {
+	if t.Value == "" {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeAccessLogFormat) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeAccessLogFormat) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeAccessLogFormat) String() string {
+	return t.Value
+}
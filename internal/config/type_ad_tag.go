@@ -0,0 +1,47 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+const typeAdTagLength = 16
+
+type TypeAdTag struct {
+	Value []byte
+}
+
+func (t *TypeAdTag) Set(value string) error {
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("incorrect ad tag %s: %w", value, err)
+	}
+
+	if len(decoded) != typeAdTagLength {
+		return fmt.Errorf("ad tag should be %d bytes long (%s)", typeAdTagLength, value)
+	}
+
+	t.Value = decoded
+
+	return nil
+}
+
+func (t TypeAdTag) Get(defaultValue []byte) []byte {
+	if len(t.Value) == 0 {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeAdTag) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeAdTag) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeAdTag) String() string {
+	return hex.EncodeToString(t.Value)
+}
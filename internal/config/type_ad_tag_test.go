@@ -0,0 +1,84 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeAdTagTestStruct struct {
+	Value config.TypeAdTag `json:"value"`
+}
+
+type TypeAdTagTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TypeAdTagTestSuite) TestUnmarshalFail() {
+	testData := []string{
+		"",
+		"xx",
+		"0123456789abcdef",
+		"0123456789abcdef0123456789abcdef01",
+		"0123456789abcdef0123456789abcdeg",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeAdTagTestStruct{}))
+		})
+	}
+}
+
+func (suite *TypeAdTagTestSuite) TestUnmarshalOk() {
+	testData := map[string]string{
+		"0123456789abcdef0123456789abcdef": "0123456789abcdef0123456789abcdef",
+		"0123456789ABCDEF0123456789ABCDEF": "0123456789abcdef0123456789abcdef",
+	}
+
+	for k, v := range testData {
+		value := v
+
+		data, err := json.Marshal(map[string]string{
+			"value": k,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(k, func(t *testing.T) {
+			testStruct := &typeAdTagTestStruct{}
+			assert.NoError(t, json.Unmarshal(data, testStruct))
+			assert.Equal(t, value, testStruct.Value.String())
+			assert.Len(t, testStruct.Value.Get(nil), 16)
+		})
+	}
+}
+
+func (suite *TypeAdTagTestSuite) TestMarshalOk() {
+	value := typeAdTagTestStruct{}
+	suite.NoError(value.Value.Set("0123456789abcdef0123456789abcdef"))
+
+	data, err := json.Marshal(value)
+	suite.NoError(err)
+	suite.JSONEq(`{"value": "0123456789abcdef0123456789abcdef"}`, string(data))
+}
+
+func (suite *TypeAdTagTestSuite) TestGet() {
+	value := config.TypeAdTag{}
+	suite.Equal([]byte{1}, value.Get([]byte{1}))
+
+	suite.NoError(value.Set("0123456789abcdef0123456789abcdef"))
+	suite.Len(value.Get([]byte{1}), 16)
+}
+
+func TestTypeAdTag(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &TypeAdTagTestSuite{})
+}
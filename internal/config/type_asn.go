@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TypeASN is a number of autonomous system.
+type TypeASN struct {
+	Value uint
+}
+
+func (t *TypeASN) Set(value string) error {
+	asnValue, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return fmt.Errorf("value is not 32-bit uint (%s): %w", value, err)
+	}
+
+	if asnValue == 0 {
+		return fmt.Errorf("value should be >0 (%s)", value)
+	}
+
+	t.Value = uint(asnValue)
+
+	return nil
+}
+
+func (t TypeASN) Get(defaultValue uint) uint {
+	if t.Value == 0 {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeASN) UnmarshalJSON(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeASN) MarshalJSON() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeASN) String() string {
+	return strconv.FormatUint(uint64(t.Value), 10)
+}
@@ -0,0 +1,72 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeASNTestStruct struct {
+	Value config.TypeASN `json:"value"`
+}
+
+type TypeASNTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TypeASNTestSuite) TestUnmarshalFail() {
+	testData := []string{
+		"-1",
+		"0",
+		"1.0",
+		"4294967296",
+		"AS13335",
+		"some_value",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeASNTestStruct{}))
+		})
+	}
+}
+
+func (suite *TypeASNTestSuite) TestUnmarshalOk() {
+	testStruct := &typeASNTestStruct{}
+
+	suite.NoError(json.Unmarshal([]byte(`{"value": 4294967295}`), testStruct))
+	suite.EqualValues(4294967295, testStruct.Value.Get(2))
+}
+
+func (suite *TypeASNTestSuite) TestMarshalOk() {
+	testStruct := &typeASNTestStruct{
+		Value: config.TypeASN{
+			Value: 13335,
+		},
+	}
+
+	data, err := json.Marshal(testStruct)
+	suite.NoError(err)
+	suite.JSONEq(`{"value": 13335}`, string(data))
+}
+
+func (suite *TypeASNTestSuite) TestGet() {
+	value := config.TypeASN{}
+	suite.EqualValues(1, value.Get(1))
+
+	value.Value = 13335
+	suite.EqualValues(13335, value.Get(1))
+}
+
+func TestTypeASN(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &TypeASNTestSuite{})
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// characters of b64token from RFC 6750.
+var typeBearerTokenRegexp = regexp.MustCompile(`^[A-Za-z0-9._~+/-]+=*$`)
+
+type TypeBearerToken struct {
+	Value string
+}
+
+func (t *TypeBearerToken) Set(value string) error {
+	if !typeBearerTokenRegexp.MatchString(value) {
+		return fmt.Errorf("incorrect bearer token %q", value)
+	}
+
+	t.Value = value
+
+	return nil
+}
+
+func (t TypeBearerToken) Get(defaultValue string) string {
+	if t.Value == "" {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeBearerToken) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeBearerToken) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeBearerToken) String() string {
+	return t.Value
+}
@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeBearerTokenTestStruct struct {
+	Value config.TypeBearerToken `json:"value"`
+}
+
+type TypeBearerTokenTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TypeBearerTokenTestSuite) TestUnmarshalFail() {
+	testData := []string{
+		"",
+		"hello world",
+		"token\n",
+		"a=b",
+		"токен",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeBearerTokenTestStruct{}))
+		})
+	}
+}
+
+func (suite *TypeBearerTokenTestSuite) TestUnmarshalOk() {
+	testData := []string{
+		"s3cr3t",
+		"aGVsbG8gd29ybGQ=",
+		"a.b-c_d~e+f/g",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			testStruct := &typeBearerTokenTestStruct{}
+			assert.NoError(t, json.Unmarshal(data, testStruct))
+			assert.Equal(t, v, testStruct.Value.Get(""))
+		})
+	}
+}
+
+func (suite *TypeBearerTokenTestSuite) TestMarshalOk() {
+	testStruct := &typeBearerTokenTestStruct{
+		Value: config.TypeBearerToken{
+			Value: "s3cr3t",
+		},
+	}
+
+	data, err := json.Marshal(testStruct)
+	suite.NoError(err)
+	suite.JSONEq(`{"value": "s3cr3t"}`, string(data))
+}
+
+func (suite *TypeBearerTokenTestSuite) TestGet() {
+	value := config.TypeBearerToken{}
+	suite.Equal("lalala", value.Get("lalala"))
+
+	value.Value = "s3cr3t"
+	suite.Equal("s3cr3t", value.Get("lalala"))
+}
+
+func TestTypeBearerToken(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &TypeBearerTokenTestSuite{})
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// TypeClientLimitActionDrop defines an action which closes
+	// connections of clients that have exceeded their limits.
+	TypeClientLimitActionDrop = "drop"
+
+	// TypeClientLimitActionDomainFronting defines an action which routes
+	// connections of clients that have exceeded their limits to a
+	// fronting domain.
+	TypeClientLimitActionDomainFronting = "domain-fronting"
+)
+
+type TypeClientLimitAction struct {
+	Value string
+}
+
+func (t *TypeClientLimitAction) Set(value string) error {
+	lowercasedValue := strings.ToLower(value)
+
+	switch lowercasedValue {
+	case TypeClientLimitActionDrop, TypeClientLimitActionDomainFronting:
+		t.Value = lowercasedValue
+
+		return nil
+	default:
+		return fmt.Errorf("unknown client limit action %s", value)
+	}
+}
+
+func (t TypeClientLimitAction) Get(defaultValue string) string {
+	if t.Value == "" {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeClientLimitAction) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeClientLimitAction) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeClientLimitAction) String() string {
+	return t.Value
+}
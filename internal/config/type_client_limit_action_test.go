@@ -0,0 +1,105 @@
+package config_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeClientLimitActionTestStruct struct {
+	Value config.TypeClientLimitAction `json:"value"`
+}
+
+type ClientLimitActionTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ClientLimitActionTestSuite) TestUnmarshalFail() {
+	testData := []string{
+		"",
+		"reject",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeClientLimitActionTestStruct{}))
+		})
+	}
+}
+
+func (suite *ClientLimitActionTestSuite) TestUnmarshalOk() {
+	testData := []string{
+		config.TypeClientLimitActionDrop,
+		config.TypeClientLimitActionDomainFronting,
+		strings.ToUpper(config.TypeClientLimitActionDrop),
+		strings.ToUpper(config.TypeClientLimitActionDomainFronting),
+	}
+
+	for _, v := range testData {
+		value := v
+
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			testStruct := &typeClientLimitActionTestStruct{}
+			assert.NoError(t, json.Unmarshal(data, testStruct))
+			assert.Equal(t, strings.ToLower(value), testStruct.Value.Value)
+		})
+	}
+}
+
+func (suite *ClientLimitActionTestSuite) TestMarshalOk() {
+	testData := []string{
+		config.TypeClientLimitActionDrop,
+		config.TypeClientLimitActionDomainFronting,
+	}
+
+	for _, v := range testData {
+		value := v
+
+		suite.T().Run(v, func(t *testing.T) {
+			testStruct := &typeClientLimitActionTestStruct{
+				Value: config.TypeClientLimitAction{
+					Value: value,
+				},
+			}
+
+			encodedJSON, err := json.Marshal(testStruct)
+			assert.NoError(t, err)
+
+			expectedJSON, err := json.Marshal(map[string]string{
+				"value": value,
+			})
+			assert.NoError(t, err)
+
+			assert.JSONEq(t, string(expectedJSON), string(encodedJSON))
+		})
+	}
+}
+
+func (suite *ClientLimitActionTestSuite) TestGet() {
+	value := config.TypeClientLimitAction{}
+	suite.Equal(config.TypeClientLimitActionDrop,
+		value.Get(config.TypeClientLimitActionDrop))
+
+	suite.NoError(value.Set(config.TypeClientLimitActionDomainFronting))
+	suite.Equal(config.TypeClientLimitActionDomainFronting,
+		value.Get(config.TypeClientLimitActionDrop))
+}
+
+func TestTypeClientLimitAction(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &ClientLimitActionTestSuite{})
+}
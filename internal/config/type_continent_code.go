@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeContinentCode is a 2-letter code of a continent as it is used in
+// GeoIP databases.
+type TypeContinentCode struct {
+	Value string
+}
+
+func (t *TypeContinentCode) Set(value string) error {
+	uppercasedValue := strings.ToUpper(value)
+
+	switch uppercasedValue {
+	case "AF", "AN", "AS", "EU", "NA", "OC", "SA":
+		t.Value = uppercasedValue
+
+		return nil
+	default:
+		return fmt.Errorf("unknown continent code %s", value)
+	}
+}
+
+func (t TypeContinentCode) Get(defaultValue string) string {
+	if t.Value == "" {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeContinentCode) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeContinentCode) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeContinentCode) String() string {
+	return t.Value
+}
@@ -0,0 +1,75 @@
+package config_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeContinentCodeTestStruct struct {
+	Value config.TypeContinentCode `json:"value"`
+}
+
+type ContinentCodeTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ContinentCodeTestSuite) TestUnmarshalFail() {
+	testData := []string{"", "XX", "EUR"}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeContinentCodeTestStruct{}))
+		})
+	}
+}
+
+func (suite *ContinentCodeTestSuite) TestUnmarshalOk() {
+	testData := []string{"EU", "na", "As"}
+
+	for _, v := range testData {
+		value := v
+
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			testStruct := &typeContinentCodeTestStruct{}
+			assert.NoError(t, json.Unmarshal(data, testStruct))
+			assert.Equal(t, strings.ToUpper(value), testStruct.Value.Value)
+		})
+	}
+}
+
+func (suite *ContinentCodeTestSuite) TestMarshalOk() {
+	testStruct := &typeContinentCodeTestStruct{}
+	suite.NoError(testStruct.Value.Set("EU"))
+
+	encodedJSON, err := json.Marshal(testStruct)
+	suite.NoError(err)
+	suite.JSONEq(`{"value": "EU"}`, string(encodedJSON))
+}
+
+func (suite *ContinentCodeTestSuite) TestGet() {
+	value := config.TypeContinentCode{}
+	suite.Equal("AA", value.Get("AA"))
+
+	suite.NoError(value.Set("EU"))
+	suite.Equal("EU", value.Get("AA"))
+}
+
+func TestTypeContinentCode(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &ContinentCodeTestSuite{})
+}
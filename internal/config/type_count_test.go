@@ -0,0 +1,72 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeCountTestStruct struct {
+	Value config.TypeCount `json:"value"`
+}
+
+type TypeCountTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TypeCountTestSuite) TestUnmarshalFail() {
+	testData := []string{
+		"-1",
+		"0",
+		"0.0",
+		"1.1",
+		"5000000000",
+		"some_value",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeCountTestStruct{}))
+		})
+	}
+}
+
+func (suite *TypeCountTestSuite) TestUnmarshalOk() {
+	testStruct := &typeCountTestStruct{}
+
+	suite.NoError(json.Unmarshal([]byte(`{"value": 100000}`), testStruct))
+	suite.EqualValues(100000, testStruct.Value.Get(2))
+}
+
+func (suite *TypeCountTestSuite) TestMarshalOk() {
+	testStruct := &typeCountTestStruct{
+		Value: config.TypeCount{
+			Value: 2,
+		},
+	}
+
+	data, err := json.Marshal(testStruct)
+	suite.NoError(err)
+	suite.JSONEq(`{"value": 2}`, string(data))
+}
+
+func (suite *TypeCountTestSuite) TestGet() {
+	value := config.TypeCount{}
+	suite.EqualValues(1, value.Get(1))
+
+	value.Value = 3
+	suite.EqualValues(3, value.Get(1))
+}
+
+func TestTypeCount(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &TypeCountTestSuite{})
+}
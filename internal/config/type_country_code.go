@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeCountryCode is ISO 3166-1 alpha-2 code of a country, like DE.
+type TypeCountryCode struct {
+	Value string
+}
+
+func (t *TypeCountryCode) Set(value string) error {
+	uppercasedValue := strings.ToUpper(value)
+
+	if len(uppercasedValue) != 2 || strings.Trim(uppercasedValue, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return fmt.Errorf("incorrect country code %s", value)
+	}
+
+	t.Value = uppercasedValue
+
+	return nil
+}
+
+func (t TypeCountryCode) Get(defaultValue string) string {
+	if t.Value == "" {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeCountryCode) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeCountryCode) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeCountryCode) String() string {
+	return t.Value
+}
@@ -0,0 +1,75 @@
+package config_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeCountryCodeTestStruct struct {
+	Value config.TypeCountryCode `json:"value"`
+}
+
+type CountryCodeTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CountryCodeTestSuite) TestUnmarshalFail() {
+	testData := []string{"", "D", "DEU", "D1"}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeCountryCodeTestStruct{}))
+		})
+	}
+}
+
+func (suite *CountryCodeTestSuite) TestUnmarshalOk() {
+	testData := []string{"DE", "us", "Jp"}
+
+	for _, v := range testData {
+		value := v
+
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			testStruct := &typeCountryCodeTestStruct{}
+			assert.NoError(t, json.Unmarshal(data, testStruct))
+			assert.Equal(t, strings.ToUpper(value), testStruct.Value.Value)
+		})
+	}
+}
+
+func (suite *CountryCodeTestSuite) TestMarshalOk() {
+	testStruct := &typeCountryCodeTestStruct{}
+	suite.NoError(testStruct.Value.Set("DE"))
+
+	encodedJSON, err := json.Marshal(testStruct)
+	suite.NoError(err)
+	suite.JSONEq(`{"value": "DE"}`, string(encodedJSON))
+}
+
+func (suite *CountryCodeTestSuite) TestGet() {
+	value := config.TypeCountryCode{}
+	suite.Equal("AA", value.Get("AA"))
+
+	suite.NoError(value.Set("DE"))
+	suite.Equal("DE", value.Get("AA"))
+}
+
+func TestTypeCountryCode(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &CountryCodeTestSuite{})
+}
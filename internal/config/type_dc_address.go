@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// TypeDCAddress is an address of Telegram DC. Unlike TypeHostPort, its
+// host can be a hostname.
+type TypeDCAddress struct {
+	Value string
+}
+
+func (t *TypeDCAddress) Set(value string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return fmt.Errorf("incorrect host:port value (%v): %w", value, err)
+	}
+
+	portValue, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return fmt.Errorf("incorrect port number (%v): %w", value, err)
+	}
+
+	if portValue == 0 {
+		return fmt.Errorf("incorrect port number (%s)", value)
+	}
+
+	if host == "" {
+		return fmt.Errorf("empty host: %s", value)
+	}
+
+	t.Value = net.JoinHostPort(host, port)
+
+	return nil
+}
+
+func (t TypeDCAddress) Get(defaultValue string) string {
+	if t.Value == "" {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeDCAddress) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeDCAddress) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeDCAddress) String() string {
+	return t.Value
+}
@@ -0,0 +1,89 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeDCAddressTestStruct struct {
+	Value config.TypeDCAddress `json:"value"`
+}
+
+type TypeDCAddressTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TypeDCAddressTestSuite) TestUnmarshalFail() {
+	testData := []string{
+		":",
+		":800",
+		"127.0.0.1:8000000",
+		"127.0.0.1:0",
+		"",
+		"localhost",
+		"google.com:",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeDCAddressTestStruct{}))
+		})
+	}
+}
+
+func (suite *TypeDCAddressTestSuite) TestUnmarshalOk() {
+	testData := []string{
+		"127.0.0.1:80",
+		"[2001:67c:4e8:f002::a]:443",
+		"dc2.example.com:443",
+	}
+
+	for _, v := range testData {
+		value := v
+
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			testStruct := &typeDCAddressTestStruct{}
+			assert.NoError(t, json.Unmarshal(data, testStruct))
+			assert.Equal(t, value, testStruct.Value.Value)
+		})
+	}
+}
+
+func (suite *TypeDCAddressTestSuite) TestMarshalOk() {
+	testStruct := typeDCAddressTestStruct{
+		Value: config.TypeDCAddress{
+			Value: "dc2.example.com:443",
+		},
+	}
+
+	data, err := json.Marshal(testStruct)
+	suite.NoError(err)
+	suite.JSONEq(`{"value": "dc2.example.com:443"}`, string(data))
+}
+
+func (suite *TypeDCAddressTestSuite) TestGet() {
+	value := config.TypeDCAddress{}
+	suite.Equal("127.0.0.1:9000", value.Get("127.0.0.1:9000"))
+
+	value.Value = "dc2.example.com:443"
+	suite.Equal("dc2.example.com:443", value.Get("127.0.0.1:9000"))
+}
+
+func TestTypeDCAddress(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &TypeDCAddressTestSuite{})
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// TypeEventPolicyBlock defines a policy which waits for slow
+	// observers.
+	TypeEventPolicyBlock = "block"
+
+	// TypeEventPolicyDropNewest defines a policy which drops events if
+	// observers are slow.
+	TypeEventPolicyDropNewest = "drop-newest"
+
+	// TypeEventPolicyCoalesceTraffic defines a policy which merges
+	// traffic events if observers are slow.
+	TypeEventPolicyCoalesceTraffic = "coalesce-traffic"
+)
+
+type TypeEventPolicy struct {
+	Value string
+}
+
+func (t *TypeEventPolicy) Set(value string) error {
+	lowercasedValue := strings.ToLower(value)
+
+	switch lowercasedValue {
+	case TypeEventPolicyBlock, TypeEventPolicyDropNewest,
+		TypeEventPolicyCoalesceTraffic:
+		t.Value = lowercasedValue
+
+		return nil
+	default:
+		return fmt.Errorf("unknown event policy %s", value)
+	}
+}
+
+func (t TypeEventPolicy) Get(defaultValue string) string {
+	if t.Value == "" {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeEventPolicy) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeEventPolicy) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeEventPolicy) String() string {
+	return t.Value
+}
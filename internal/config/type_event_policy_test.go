@@ -0,0 +1,108 @@
+package config_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeEventPolicyTestStruct struct {
+	Value config.TypeEventPolicy `json:"value"`
+}
+
+type EventPolicyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EventPolicyTestSuite) TestUnmarshalFail() {
+	testData := []string{
+		"",
+		"drop-oldest",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeEventPolicyTestStruct{}))
+		})
+	}
+}
+
+func (suite *EventPolicyTestSuite) TestUnmarshalOk() {
+	testData := []string{
+		config.TypeEventPolicyDropNewest,
+		config.TypeEventPolicyCoalesceTraffic,
+		config.TypeEventPolicyBlock,
+		strings.ToUpper(config.TypeEventPolicyDropNewest),
+		strings.ToUpper(config.TypeEventPolicyCoalesceTraffic),
+		strings.ToUpper(config.TypeEventPolicyBlock),
+	}
+
+	for _, v := range testData {
+		value := v
+
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			testStruct := &typeEventPolicyTestStruct{}
+			assert.NoError(t, json.Unmarshal(data, testStruct))
+			assert.Equal(t, strings.ToLower(value), testStruct.Value.Value)
+		})
+	}
+}
+
+func (suite *EventPolicyTestSuite) TestMarshalOk() {
+	testData := []string{
+		config.TypeEventPolicyDropNewest,
+		config.TypeEventPolicyCoalesceTraffic,
+		config.TypeEventPolicyBlock,
+	}
+
+	for _, v := range testData {
+		value := v
+
+		suite.T().Run(v, func(t *testing.T) {
+			testStruct := &typeEventPolicyTestStruct{
+				Value: config.TypeEventPolicy{
+					Value: value,
+				},
+			}
+
+			encodedJSON, err := json.Marshal(testStruct)
+			assert.NoError(t, err)
+
+			expectedJSON, err := json.Marshal(map[string]string{
+				"value": value,
+			})
+			assert.NoError(t, err)
+
+			assert.JSONEq(t, string(expectedJSON), string(encodedJSON))
+		})
+	}
+}
+
+func (suite *EventPolicyTestSuite) TestGet() {
+	value := config.TypeEventPolicy{}
+	suite.Equal(config.TypeEventPolicyBlock,
+		value.Get(config.TypeEventPolicyBlock))
+
+	suite.NoError(value.Set(config.TypeEventPolicyDropNewest))
+	suite.Equal(config.TypeEventPolicyDropNewest,
+		value.Get(config.TypeEventPolicyBlock))
+}
+
+func TestTypeEventPolicy(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &EventPolicyTestSuite{})
+}
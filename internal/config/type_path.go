@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+type TypePath struct {
+	Value string
+}
+
+func (t *TypePath) Set(value string) error {
+	if !filepath.IsAbs(value) {
+		return fmt.Errorf("path should be absolute (%s)", value)
+	}
+
+	t.Value = filepath.Clean(value)
+
+	return nil
+}
+
+func (t TypePath) Get(defaultValue string) string {
+	if t.Value == "" {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypePath) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypePath) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypePath) String() string {
+	return t.Value
+}
@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typePathTestStruct struct {
+	Value config.TypePath `json:"value"`
+}
+
+type TypePathTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TypePathTestSuite) TestUnmarshalFail() {
+	testData := []string{
+		"",
+		"file.json",
+		"./file.json",
+		"../var/lib/file.json",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typePathTestStruct{}))
+		})
+	}
+}
+
+func (suite *TypePathTestSuite) TestUnmarshalOk() {
+	testData := map[string]string{
+		"/file.json":              "/file.json",
+		"/var/lib/mtg/quota.json": "/var/lib/mtg/quota.json",
+		"/var/lib/../quota.json":  "/var/quota.json",
+	}
+
+	for k, v := range testData {
+		value := v
+
+		data, err := json.Marshal(map[string]string{
+			"value": k,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(k, func(t *testing.T) {
+			testStruct := &typePathTestStruct{}
+			assert.NoError(t, json.Unmarshal(data, testStruct))
+			assert.Equal(t, value, testStruct.Value.Get(""))
+		})
+	}
+}
+
+func (suite *TypePathTestSuite) TestMarshalOk() {
+	value := typePathTestStruct{
+		Value: config.TypePath{
+			Value: "/var/lib/mtg/quota.json",
+		},
+	}
+
+	data, err := json.Marshal(value)
+	suite.NoError(err)
+	suite.JSONEq(`{"value": "/var/lib/mtg/quota.json"}`, string(data))
+}
+
+func (suite *TypePathTestSuite) TestGet() {
+	value := config.TypePath{}
+	suite.Equal("/hello", value.Get("/hello"))
+
+	suite.NoError(value.Set("/lalala"))
+	suite.Equal("/lalala", value.Get("/hello"))
+}
+
+func TestTypePath(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &TypePathTestSuite{})
+}
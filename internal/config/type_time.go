@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const typeTimeDateLayout = "2006-01-02"
+
+type TypeTime struct {
+	Value time.Time
+}
+
+func (t *TypeTime) Set(value string) error {
+	value = strings.TrimSpace(value)
+
+	parsedValue, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		parsedValue, err = time.Parse(typeTimeDateLayout, value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("incorrect time, RFC3339 or YYYY-MM-DD are expected (%s): %w", value, err)
+	}
+
+	t.Value = parsedValue
+
+	return nil
+}
+
+func (t TypeTime) Get(defaultValue time.Time) time.Time {
+	if t.Value.IsZero() {
+		return defaultValue
+	}
+
+	return t.Value
+}
+
+func (t *TypeTime) UnmarshalText(data []byte) error {
+	return t.Set(string(data))
+}
+
+func (t TypeTime) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TypeTime) String() string {
+	if t.Value.IsZero() {
+		return ""
+	}
+
+	return t.Value.Format(time.RFC3339)
+}
@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeTimeTestStruct struct {
+	Value config.TypeTime `json:"value"`
+}
+
+type TypeTimeTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TypeTimeTestSuite) TestUnmarshalFail() {
+	testData := []string{
+		"",
+		"tomorrow",
+		"2026-13-01",
+		"2026-01-01 10:00",
+	}
+
+	for _, v := range testData {
+		data, err := json.Marshal(map[string]string{
+			"value": v,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(v, func(t *testing.T) {
+			assert.Error(t, json.Unmarshal(data, &typeTimeTestStruct{}))
+		})
+	}
+}
+
+func (suite *TypeTimeTestSuite) TestUnmarshalOk() {
+	testData := map[string]time.Time{
+		"2026-01-02":                time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		"2026-01-02T10:11:12Z":      time.Date(2026, 1, 2, 10, 11, 12, 0, time.UTC),
+		"2026-01-02T12:11:12+02:00": time.Date(2026, 1, 2, 10, 11, 12, 0, time.UTC),
+	}
+
+	for k, v := range testData {
+		value := v
+
+		data, err := json.Marshal(map[string]string{
+			"value": k,
+		})
+		suite.NoError(err)
+
+		suite.T().Run(k, func(t *testing.T) {
+			testStruct := &typeTimeTestStruct{}
+			assert.NoError(t, json.Unmarshal(data, testStruct))
+			assert.True(t, value.Equal(testStruct.Value.Get(time.Time{})))
+		})
+	}
+}
+
+func (suite *TypeTimeTestSuite) TestMarshalOk() {
+	value := typeTimeTestStruct{
+		Value: config.TypeTime{
+			Value: time.Date(2026, 1, 2, 10, 11, 12, 0, time.UTC),
+		},
+	}
+
+	data, err := json.Marshal(value)
+	suite.NoError(err)
+	suite.JSONEq(`{"value": "2026-01-02T10:11:12Z"}`, string(data))
+}
+
+func (suite *TypeTimeTestSuite) TestGet() {
+	defaultValue := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	value := config.TypeTime{}
+	suite.Equal(defaultValue, value.Get(defaultValue))
+
+	suite.NoError(value.Set("2027-01-02"))
+	suite.Equal(2027, value.Get(defaultValue).Year())
+}
+
+func TestTypeTime(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &TypeTimeTestSuite{})
+}
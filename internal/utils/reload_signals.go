@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package utils
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func ReloadSignals() <-chan os.Signal {
+	sigChan := make(chan os.Signal, 1)
+
+	signal.Notify(sigChan, syscall.SIGHUP)
+
+	return sigChan
+}
@@ -0,0 +1,10 @@
+//go:build windows
+// +build windows
+
+package utils
+
+import "os"
+
+func ReloadSignals() <-chan os.Signal {
+	return make(chan os.Signal)
+}
@@ -0,0 +1,121 @@
+package ipblocklist
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+type asnRecord struct {
+	AutonomousSystemNumber uint `maxminddb:"autonomous_system_number"`
+}
+
+// ASNDatabase is a local database of autonomous systems in MaxMind DB
+// format: both GeoLite2 ASN and DB-IP ASN databases are supported. It
+// implements [mtglib.ASNLookup] and builds blocklists of autonomous
+// systems with [ASNDatabase.Blocklist].
+//
+// A database file is read into memory once and shared by all blocklists
+// built from it. Run periodically checks if a file was changed and
+// rereads it, so it is safe to update it in place.
+type ASNDatabase struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	logger    mtglib.Logger
+	db        *mmdb
+}
+
+// LookupASN returns a number of autonomous system of a given IP. If it
+// is unknown, 0 is returned.
+func (a *ASNDatabase) LookupASN(ip net.IP) uint {
+	if ip == nil {
+		return 0
+	}
+
+	record := asnRecord{}
+
+	if err := a.db.lookup(ip, &record); err != nil {
+		a.logger.BindStr("ip", ip.String()).DebugError("Cannot lookup ip in asn database", err)
+
+		return 0
+	}
+
+	return record.AutonomousSystemNumber
+}
+
+// Blocklist returns [mtglib.IPBlocklist] which contains IPs of given
+// autonomous systems.
+//
+// Run and Shutdown of this blocklist do nothing: a database has to be run
+// and shut down on its own.
+func (a *ASNDatabase) Blocklist(asns []uint) mtglib.IPBlocklist {
+	blocklist := &asnBlocklist{
+		db:   a,
+		asns: make(map[uint]bool, len(asns)),
+	}
+
+	for _, v := range asns {
+		blocklist.asns[v] = true
+	}
+
+	return blocklist
+}
+
+// Shutdown stops a background update process.
+func (a *ASNDatabase) Shutdown() {
+	a.ctxCancel()
+}
+
+// Run starts a background process which rereads a database file if it
+// was changed.
+//
+// This is a blocking method so you probably want to run it in a goroutine.
+func (a *ASNDatabase) Run(updateEach time.Duration) {
+	if updateEach == 0 {
+		updateEach = DefaultASNUpdateEach
+	}
+
+	a.db.run(a.ctx, updateEach)
+}
+
+// NewASNDatabase creates a new instance of ASNDatabase. A database file
+// is read immediately.
+//
+// This method does not start an update process so please execute Run when it
+// is necessary.
+func NewASNDatabase(logger mtglib.Logger, path string) (*ASNDatabase, error) {
+	logger = logger.Named("asn")
+
+	db, err := newMMDB(logger, path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &ASNDatabase{
+		ctx:       ctx,
+		ctxCancel: cancel,
+		logger:    logger,
+		db:        db,
+	}, nil
+}
+
+type asnBlocklist struct {
+	db   *ASNDatabase
+	asns map[uint]bool
+}
+
+func (a *asnBlocklist) Contains(ip net.IP) bool {
+	if ip == nil {
+		return true
+	}
+
+	return a.asns[a.db.LookupASN(ip)]
+}
+
+func (a *asnBlocklist) Run(_ time.Duration) {}
+
+func (a *asnBlocklist) Shutdown() {}
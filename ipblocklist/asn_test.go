@@ -0,0 +1,131 @@
+package ipblocklist_test
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/ipblocklist"
+	"github.com/9seconds/mtg/v2/logger"
+	"github.com/stretchr/testify/suite"
+)
+
+// asnNetwork is a network of a test database with a number of autonomous
+// system.
+type asnNetwork struct {
+	prefix string
+	asn    uint64
+}
+
+func writeASNDatabase(path string, networks []asnNetwork) error {
+	records := make([]mmdbRecord, 0, len(networks))
+
+	for _, v := range networks {
+		records = append(records, mmdbRecord{
+			prefix: v.prefix,
+			encode: func(buf *bytes.Buffer) {
+				mmdbMap(buf, 2)
+				mmdbString(buf, "autonomous_system_number")
+				mmdbUint(buf, 6, v.asn)
+				mmdbString(buf, "autonomous_system_organization")
+				mmdbString(buf, "Test")
+			},
+		})
+	}
+
+	return writeMMDB(path, "Test-ASN", records)
+}
+
+type ASNTestSuite struct {
+	suite.Suite
+
+	path string
+}
+
+func (suite *ASNTestSuite) SetupTest() {
+	suite.path = filepath.Join(suite.T().TempDir(), "asn.mmdb")
+
+	suite.Require().NoError(writeASNDatabase(suite.path, []asnNetwork{
+		{"10.0.0.0/8", 14061},
+		{"20.0.0.0/8", 16509},
+		{"2001:db8::/32", 14061},
+	}))
+}
+
+func (suite *ASNTestSuite) TestLookupASN() {
+	db, err := ipblocklist.NewASNDatabase(logger.NewNoopLogger(), suite.path)
+	suite.Require().NoError(err)
+
+	defer db.Shutdown()
+
+	suite.EqualValues(14061, db.LookupASN(net.ParseIP("10.1.2.3")))
+	suite.EqualValues(14061, db.LookupASN(net.ParseIP("::ffff:10.1.2.3")))
+	suite.EqualValues(14061, db.LookupASN(net.ParseIP("2001:db8::1")))
+	suite.EqualValues(16509, db.LookupASN(net.ParseIP("20.0.0.1")))
+	suite.EqualValues(0, db.LookupASN(net.ParseIP("30.0.0.1")))
+	suite.EqualValues(0, db.LookupASN(nil))
+}
+
+func (suite *ASNTestSuite) TestBlocklist() {
+	db, err := ipblocklist.NewASNDatabase(logger.NewNoopLogger(), suite.path)
+	suite.Require().NoError(err)
+
+	defer db.Shutdown()
+
+	blocklist := db.Blocklist([]uint{14061})
+
+	suite.True(blocklist.Contains(net.ParseIP("10.1.2.3")))
+	suite.True(blocklist.Contains(net.ParseIP("2001:db8::1")))
+	suite.False(blocklist.Contains(net.ParseIP("20.0.0.1")))
+	suite.False(blocklist.Contains(net.ParseIP("30.0.0.1")))
+	suite.True(blocklist.Contains(nil))
+
+	blocklist.Shutdown()
+
+	suite.True(db.Blocklist([]uint{16509}).Contains(net.ParseIP("20.0.0.1")))
+}
+
+func (suite *ASNTestSuite) TestNoDatabase() {
+	_, err := ipblocklist.NewASNDatabase(logger.NewNoopLogger(), suite.path+".missing")
+	suite.Error(err)
+}
+
+func (suite *ASNTestSuite) TestBrokenDatabase() {
+	suite.NoError(os.WriteFile(suite.path, []byte("broken"), 0o600))
+
+	_, err := ipblocklist.NewASNDatabase(logger.NewNoopLogger(), suite.path)
+	suite.Error(err)
+}
+
+func (suite *ASNTestSuite) TestUpdate() {
+	db, err := ipblocklist.NewASNDatabase(logger.NewNoopLogger(), suite.path)
+	suite.Require().NoError(err)
+
+	defer db.Shutdown()
+
+	go db.Run(10 * time.Millisecond)
+
+	blocklist := db.Blocklist([]uint{14061})
+
+	suite.False(blocklist.Contains(net.ParseIP("20.0.0.1")))
+
+	suite.NoError(writeASNDatabase(suite.path, []asnNetwork{
+		{"20.0.0.0/8", 14061},
+	}))
+
+	future := time.Now().Add(time.Minute)
+	suite.NoError(os.Chtimes(suite.path, future, future))
+
+	suite.Eventually(func() bool {
+		return blocklist.Contains(net.ParseIP("20.0.0.1"))
+	}, time.Second, 10*time.Millisecond)
+	suite.False(blocklist.Contains(net.ParseIP("10.1.2.3")))
+}
+
+func TestASN(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &ASNTestSuite{})
+}
@@ -3,11 +3,13 @@ package ipblocklist
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/9seconds/mtg/v2/ipblocklist/files"
@@ -48,6 +50,7 @@ type Firehol struct {
 
 	updateCallback FireholUpdateCallback
 	ranger         cidranger.Ranger
+	loaded         atomic.Bool
 
 	blocklists []files.File
 
@@ -76,6 +79,16 @@ func (f *Firehol) Contains(ip net.IP) bool {
 	return ok && err == nil
 }
 
+// Load updates lists right now. It returns an error if any of them
+// cannot be loaded, lists are not updated then. If lists are loaded, Run
+// does not repeat this update.
+//
+// Until lists are loaded, Contains returns false for any IP, so this
+// is useful if a new instance should replace one which is in use.
+func (f *Firehol) Load() error {
+	return f.update(true)
+}
+
 // Run starts a background update process.
 //
 // This is a blocking method so you probably want to run it in a goroutine.
@@ -95,19 +108,24 @@ func (f *Firehol) Run(updateEach time.Duration) {
 		}
 	}()
 
-	f.update()
+	if !f.loaded.Load() {
+		f.update(false) //nolint: errcheck
+	}
 
 	for {
 		select {
 		case <-f.ctx.Done():
 			return
 		case <-ticker.C:
-			f.update()
+			f.update(false) //nolint: errcheck
 		}
 	}
 }
 
-func (f *Firehol) update() {
+// update replaces lists with a fresh version. Lists which cannot be
+// loaded are reported in a returned error. If strict is set, nothing is
+// replaced then, otherwise such lists are skipped.
+func (f *Firehol) update(strict bool) error {
 	ctx, cancel := context.WithCancel(f.ctx)
 	defer cancel()
 
@@ -115,14 +133,16 @@ func (f *Firehol) update() {
 
 	mutex := &sync.Mutex{}
 	ranger := cidranger.NewPCTrieRanger()
+	errs := make([]error, len(f.blocklists))
 
-	for _, v := range f.blocklists {
+	for idx, v := range f.blocklists {
 		wg.Go(func() {
 			logger := f.logger.BindStr("filename", v.String())
 
 			fileContent, err := v.Open(ctx)
 			if err != nil {
 				logger.WarningError("update has failed", err)
+				errs[idx] = fmt.Errorf("cannot open %s: %w", v.String(), err)
 
 				return
 			}
@@ -131,22 +151,31 @@ func (f *Firehol) update() {
 
 			if err := f.updateFromFile(mutex, ranger, bufio.NewScanner(fileContent)); err != nil {
 				logger.WarningError("update has failed", err)
+				errs[idx] = fmt.Errorf("cannot load %s: %w", v.String(), err)
 			}
 		})
 	}
 
 	wg.Wait()
 
+	err := errors.Join(errs...)
+	if strict && err != nil {
+		return err
+	}
+
 	f.updateMutex.Lock()
 	defer f.updateMutex.Unlock()
 
 	f.ranger = ranger
+	f.loaded.Store(true)
 
 	if f.updateCallback != nil {
 		f.updateCallback(ctx, ranger.Len())
 	}
 
 	f.logger.Info("ip list was updated")
+
+	return err
 }
 
 func (f *Firehol) updateFromFile(mutex sync.Locker,
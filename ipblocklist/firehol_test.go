@@ -144,6 +144,37 @@ func (suite *FireholTestSuite) TestMixed() {
 	time.Sleep(500 * time.Millisecond)
 }
 
+func (suite *FireholTestSuite) TestLoadOk() {
+	blocklist, err := ipblocklist.NewFirehol(logger.NewNoopLogger(),
+		suite.networkMock, 2,
+		nil, []string{filepath.Join("testdata", "good_ipset.ipset")},
+		nil)
+
+	suite.NoError(err)
+	suite.NoError(blocklist.Load())
+
+	suite.True(blocklist.Contains(net.ParseIP("10.0.0.10")))
+	suite.False(blocklist.Contains(net.ParseIP("127.0.0.1")))
+
+	blocklist.Shutdown()
+}
+
+func (suite *FireholTestSuite) TestLoadFail() {
+	blocklist, err := ipblocklist.NewFirehol(logger.NewNoopLogger(),
+		suite.networkMock, 2,
+		nil, []string{
+			filepath.Join("testdata", "good_ipset.ipset"),
+			filepath.Join("testdata", "broken_ipset.ipset"),
+		},
+		nil)
+
+	suite.NoError(err)
+	suite.Error(blocklist.Load())
+	suite.False(blocklist.Contains(net.ParseIP("10.0.0.10")))
+
+	blocklist.Shutdown()
+}
+
 func TestFirehol(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, &FireholTestSuite{})
@@ -0,0 +1,132 @@
+package ipblocklist
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+type geoIPRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Continent struct {
+		Code string `maxminddb:"code"`
+	} `maxminddb:"continent"`
+}
+
+// GeoIPDatabase is a local database of countries in MaxMind DB format:
+// both GeoIP2/GeoLite2 Country and DB-IP Country databases are
+// supported. It builds blocklists of countries and continents with
+// [GeoIPDatabase.Blocklist].
+//
+// A database file is read into memory once and shared by all blocklists
+// built from it. Run periodically checks if a file was changed and
+// rereads it, so it is safe to update it in place.
+type GeoIPDatabase struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	logger    mtglib.Logger
+	db        *mmdb
+}
+
+// Blocklist returns [mtglib.IPBlocklist] which contains IPs of given
+// countries and continents.
+//
+// Countries are ISO 3166-1 alpha-2 codes like DE, continents are 2-letter
+// codes like EU.
+//
+// Run and Shutdown of this blocklist do nothing: a database has to be run
+// and shut down on its own.
+func (g *GeoIPDatabase) Blocklist(countries, continents []string) mtglib.IPBlocklist {
+	blocklist := &geoIPBlocklist{
+		db:         g,
+		countries:  make(map[string]bool, len(countries)),
+		continents: make(map[string]bool, len(continents)),
+	}
+
+	for _, v := range countries {
+		blocklist.countries[strings.ToUpper(v)] = true
+	}
+
+	for _, v := range continents {
+		blocklist.continents[strings.ToUpper(v)] = true
+	}
+
+	return blocklist
+}
+
+// Shutdown stops a background update process.
+func (g *GeoIPDatabase) Shutdown() {
+	g.ctxCancel()
+}
+
+// Run starts a background process which rereads a database file if it
+// was changed.
+//
+// This is a blocking method so you probably want to run it in a goroutine.
+func (g *GeoIPDatabase) Run(updateEach time.Duration) {
+	if updateEach == 0 {
+		updateEach = DefaultGeoIPUpdateEach
+	}
+
+	g.db.run(g.ctx, updateEach)
+}
+
+func (g *GeoIPDatabase) lookup(ip net.IP) (geoIPRecord, bool) {
+	record := geoIPRecord{}
+
+	if err := g.db.lookup(ip, &record); err != nil {
+		g.logger.BindStr("ip", ip.String()).DebugError("Cannot lookup ip in geoip database", err)
+
+		return record, false
+	}
+
+	return record, true
+}
+
+// NewGeoIPDatabase creates a new instance of GeoIPDatabase. A database
+// file is read immediately.
+//
+// This method does not start an update process so please execute Run when it
+// is necessary.
+func NewGeoIPDatabase(logger mtglib.Logger, path string) (*GeoIPDatabase, error) {
+	logger = logger.Named("geoip")
+
+	db, err := newMMDB(logger, path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &GeoIPDatabase{
+		ctx:       ctx,
+		ctxCancel: cancel,
+		logger:    logger,
+		db:        db,
+	}, nil
+}
+
+type geoIPBlocklist struct {
+	db         *GeoIPDatabase
+	countries  map[string]bool
+	continents map[string]bool
+}
+
+func (g *geoIPBlocklist) Contains(ip net.IP) bool {
+	if ip == nil {
+		return true
+	}
+
+	record, ok := g.db.lookup(ip)
+
+	return ok && (g.countries[record.Country.ISOCode] || g.continents[record.Continent.Code])
+}
+
+func (g *geoIPBlocklist) Run(_ time.Duration) {}
+
+func (g *geoIPBlocklist) Shutdown() {}
@@ -0,0 +1,124 @@
+package ipblocklist_test
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/ipblocklist"
+	"github.com/9seconds/mtg/v2/logger"
+	"github.com/stretchr/testify/suite"
+)
+
+// geoIPNetwork is a network of a test database with a country and a
+// continent code.
+type geoIPNetwork struct {
+	prefix    string
+	country   string
+	continent string
+}
+
+func writeGeoIPDatabase(path string, networks []geoIPNetwork) error {
+	records := make([]mmdbRecord, 0, len(networks))
+
+	for _, v := range networks {
+		records = append(records, mmdbRecord{
+			prefix: v.prefix,
+			encode: func(buf *bytes.Buffer) {
+				mmdbMap(buf, 2)
+				mmdbString(buf, "country")
+				mmdbMap(buf, 1)
+				mmdbString(buf, "iso_code")
+				mmdbString(buf, v.country)
+				mmdbString(buf, "continent")
+				mmdbMap(buf, 1)
+				mmdbString(buf, "code")
+				mmdbString(buf, v.continent)
+			},
+		})
+	}
+
+	return writeMMDB(path, "Test-Country", records)
+}
+
+type GeoIPTestSuite struct {
+	suite.Suite
+
+	path string
+}
+
+func (suite *GeoIPTestSuite) SetupTest() {
+	suite.path = filepath.Join(suite.T().TempDir(), "country.mmdb")
+
+	suite.Require().NoError(writeGeoIPDatabase(suite.path, []geoIPNetwork{
+		{"10.0.0.0/8", "DE", "EU"},
+		{"20.0.0.0/8", "US", "NA"},
+		{"2001:db8::/32", "JP", "AS"},
+	}))
+}
+
+func (suite *GeoIPTestSuite) TestBlocklist() {
+	db, err := ipblocklist.NewGeoIPDatabase(logger.NewNoopLogger(), suite.path)
+	suite.Require().NoError(err)
+
+	defer db.Shutdown()
+
+	blocklist := db.Blocklist([]string{"de"}, []string{"AS"})
+
+	suite.True(blocklist.Contains(net.ParseIP("10.1.2.3")))
+	suite.True(blocklist.Contains(net.ParseIP("::ffff:10.1.2.3")))
+	suite.True(blocklist.Contains(net.ParseIP("2001:db8::1")))
+	suite.False(blocklist.Contains(net.ParseIP("20.0.0.1")))
+	suite.False(blocklist.Contains(net.ParseIP("30.0.0.1")))
+	suite.False(blocklist.Contains(net.ParseIP("2001:db9::1")))
+	suite.True(blocklist.Contains(nil))
+
+	blocklist.Shutdown()
+
+	suite.True(db.Blocklist(nil, []string{"na"}).Contains(net.ParseIP("20.0.0.1")))
+}
+
+func (suite *GeoIPTestSuite) TestNoDatabase() {
+	_, err := ipblocklist.NewGeoIPDatabase(logger.NewNoopLogger(), suite.path+".missing")
+	suite.Error(err)
+}
+
+func (suite *GeoIPTestSuite) TestBrokenDatabase() {
+	suite.NoError(os.WriteFile(suite.path, []byte("broken"), 0o600))
+
+	_, err := ipblocklist.NewGeoIPDatabase(logger.NewNoopLogger(), suite.path)
+	suite.Error(err)
+}
+
+func (suite *GeoIPTestSuite) TestUpdate() {
+	db, err := ipblocklist.NewGeoIPDatabase(logger.NewNoopLogger(), suite.path)
+	suite.Require().NoError(err)
+
+	defer db.Shutdown()
+
+	go db.Run(10 * time.Millisecond)
+
+	blocklist := db.Blocklist([]string{"DE"}, nil)
+
+	suite.False(blocklist.Contains(net.ParseIP("20.0.0.1")))
+
+	suite.NoError(writeGeoIPDatabase(suite.path, []geoIPNetwork{
+		{"20.0.0.0/8", "DE", "EU"},
+	}))
+
+	future := time.Now().Add(time.Minute)
+	suite.NoError(os.Chtimes(suite.path, future, future))
+
+	suite.Eventually(func() bool {
+		return blocklist.Contains(net.ParseIP("20.0.0.1"))
+	}, time.Second, 10*time.Millisecond)
+	suite.False(blocklist.Contains(net.ParseIP("10.1.2.3")))
+}
+
+func TestGeoIP(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &GeoIPTestSuite{})
+}
@@ -15,4 +15,12 @@ const (
 	// DefaultFireholUpdateEach defines a default time period when Firehol
 	// requests updates of the blocklists.
 	DefaultFireholUpdateEach = 6 * time.Hour
+
+	// DefaultGeoIPUpdateEach defines a default time period when GeoIP
+	// checks if a database file was changed.
+	DefaultGeoIPUpdateEach = time.Minute
+
+	// DefaultASNUpdateEach defines a default time period when ASNDatabase
+	// checks if a database file was changed.
+	DefaultASNUpdateEach = time.Minute
 )
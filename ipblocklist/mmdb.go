@@ -0,0 +1,115 @@
+package ipblocklist
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/oschwald/maxminddb-golang/v2"
+)
+
+// mmdb is a database in MaxMind DB format. A file is read into memory
+// and reread if it is changed, so it is safe to update it in place.
+type mmdb struct {
+	logger mtglib.Logger
+	path   string
+
+	mutex   sync.RWMutex
+	reader  *maxminddb.Reader
+	modTime time.Time
+	size    int64
+}
+
+// lookup decodes a record of an IP into value. If there is no record,
+// value is unchanged.
+func (m *mmdb) lookup(ip net.IP, value any) error {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return fmt.Errorf("incorrect ip %v", ip)
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.reader.Lookup(addr.Unmap()).Decode(value) //nolint: wrapcheck
+}
+
+func (m *mmdb) run(ctx context.Context, updateEach time.Duration) {
+	ticker := time.NewTicker(updateEach)
+
+	defer func() {
+		ticker.Stop()
+
+		select {
+		case <-ticker.C:
+		default:
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.update(); err != nil {
+				m.logger.WarningError("update has failed", err)
+			}
+		}
+	}
+}
+
+func (m *mmdb) update() error {
+	stat, err := os.Stat(m.path)
+	if err != nil {
+		return fmt.Errorf("cannot stat a database file: %w", err)
+	}
+
+	m.mutex.RLock()
+	changed := !stat.ModTime().Equal(m.modTime) || stat.Size() != m.size
+	m.mutex.RUnlock()
+
+	if !changed {
+		return nil
+	}
+
+	// stat is taken before a file is read, so if it is changed while we
+	// read it, we will read it once again on the next update.
+	content, err := os.ReadFile(m.path)
+	if err != nil {
+		return fmt.Errorf("cannot read a database file: %w", err)
+	}
+
+	reader, err := maxminddb.OpenBytes(content)
+	if err != nil {
+		return fmt.Errorf("cannot parse a database file: %w", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.reader = reader
+	m.modTime = stat.ModTime()
+	m.size = stat.Size()
+
+	m.logger.BindStr("type", reader.Metadata.DatabaseType).Info("database was updated")
+
+	return nil
+}
+
+func newMMDB(logger mtglib.Logger, path string) (*mmdb, error) {
+	db := &mmdb{
+		logger: logger,
+		path:   path,
+	}
+
+	if err := db.update(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
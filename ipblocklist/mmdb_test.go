@@ -0,0 +1,139 @@
+package ipblocklist_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/netip"
+	"os"
+	"time"
+)
+
+// mmdbRecord is a network of a test database with a function which
+// encodes its data.
+type mmdbRecord struct {
+	prefix string
+	encode func(*bytes.Buffer)
+}
+
+type mmdbNode struct {
+	children [2]*mmdbNode
+	data     [2]int
+}
+
+// writeMMDB writes a minimal database in MaxMind DB format:
+// https://maxmind.github.io/MaxMind-DB/
+func writeMMDB(path, databaseType string, records []mmdbRecord) error {
+	root := &mmdbNode{}
+	data := &bytes.Buffer{}
+	offsets := []int{}
+
+	for _, v := range records {
+		prefix := netip.MustParsePrefix(v.prefix)
+		addr := prefix.Addr().As16()
+		bits := prefix.Bits()
+
+		if prefix.Addr().Is4() {
+			// IPv4 networks are in ::/96 subtree, not in IPv4-mapped one.
+			copy(addr[:12], make([]byte, 12))
+			bits += 96
+		}
+
+		offsets = append(offsets, data.Len())
+		v.encode(data)
+
+		node := root
+
+		for i := range bits {
+			bit := (addr[i/8] >> (7 - i%8)) & 1
+
+			if i == bits-1 {
+				node.data[bit] = len(offsets)
+
+				break
+			}
+
+			if node.children[bit] == nil {
+				node.children[bit] = &mmdbNode{}
+			}
+
+			node = node.children[bit]
+		}
+	}
+
+	nodes := []*mmdbNode{root}
+	ids := map[*mmdbNode]int{root: 0}
+
+	for i := 0; i < len(nodes); i++ {
+		for _, child := range nodes[i].children {
+			if child != nil {
+				ids[child] = len(nodes)
+				nodes = append(nodes, child)
+			}
+		}
+	}
+
+	content := &bytes.Buffer{}
+
+	for _, node := range nodes {
+		for bit := range 2 {
+			record := len(nodes)
+
+			switch {
+			case node.children[bit] != nil:
+				record = ids[node.children[bit]]
+			case node.data[bit] > 0:
+				record = len(nodes) + 16 + offsets[node.data[bit]-1]
+			}
+
+			content.Write([]byte{byte(record >> 16), byte(record >> 8), byte(record)})
+		}
+	}
+
+	content.Write(make([]byte, 16))
+	content.Write(data.Bytes())
+	content.WriteString("\xab\xcd\xefMaxMind.com")
+
+	mmdbMap(content, 8)
+	mmdbString(content, "node_count")
+	mmdbUint(content, 6, uint64(len(nodes)))
+	mmdbString(content, "record_size")
+	mmdbUint(content, 5, 24)
+	mmdbString(content, "ip_version")
+	mmdbUint(content, 5, 6)
+	mmdbString(content, "database_type")
+	mmdbString(content, databaseType)
+	mmdbString(content, "languages")
+	content.Write([]byte{0, 4})
+	mmdbString(content, "binary_format_major_version")
+	mmdbUint(content, 5, 2)
+	mmdbString(content, "binary_format_minor_version")
+	mmdbUint(content, 5, 0)
+	mmdbString(content, "build_epoch")
+	content.Write([]byte{8, 2})
+	binary.Write(content, binary.BigEndian, uint64(time.Now().Unix())) //nolint: errcheck
+
+	return os.WriteFile(path, content.Bytes(), 0o600)
+}
+
+func mmdbMap(buf *bytes.Buffer, size int) {
+	buf.WriteByte(byte(7<<5 | size))
+}
+
+func mmdbString(buf *bytes.Buffer, value string) {
+	if len(value) < 29 {
+		buf.WriteByte(byte(2<<5 | len(value)))
+	} else {
+		// sizes from 29 to 284 are encoded with an additional byte
+		buf.Write([]byte{2<<5 | 29, byte(len(value) - 29)})
+	}
+
+	buf.WriteString(value)
+}
+
+func mmdbUint(buf *bytes.Buffer, dataType byte, value uint64) {
+	encoded := binary.BigEndian.AppendUint64(nil, value)
+	encoded = bytes.TrimLeft(encoded, "\x00")
+
+	buf.WriteByte(dataType<<5 | byte(len(encoded)))
+	buf.Write(encoded)
+}
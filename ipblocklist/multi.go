@@ -0,0 +1,41 @@
+package ipblocklist
+
+import (
+	"net"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+type multi struct {
+	blocklists []mtglib.IPBlocklist
+}
+
+func (m *multi) Contains(ip net.IP) bool {
+	for _, v := range m.blocklists {
+		if v.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Run does nothing: blocklists have to be run on their own, they may
+// need different update periods.
+func (m *multi) Run(_ time.Duration) {}
+
+func (m *multi) Shutdown() {
+	for _, v := range m.blocklists {
+		v.Shutdown()
+	}
+}
+
+// NewMulti returns a blocklist which contains an IP if any of given
+// blocklists contains it. A shutdown of this blocklist shuts all given
+// ones down.
+func NewMulti(blocklists ...mtglib.IPBlocklist) mtglib.IPBlocklist {
+	return &multi{
+		blocklists: blocklists,
+	}
+}
@@ -0,0 +1,48 @@
+package ipblocklist_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/ipblocklist"
+	"github.com/9seconds/mtg/v2/ipblocklist/files"
+	"github.com/9seconds/mtg/v2/logger"
+	"github.com/stretchr/testify/suite"
+)
+
+type MultiTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MultiTestSuite) TestContains() {
+	_, ipnet, err := net.ParseCIDR("10.0.0.0/8")
+	suite.Require().NoError(err)
+
+	firehol, err := ipblocklist.NewFireholFromFiles(logger.NewNoopLogger(), 1,
+		[]files.File{files.NewMem([]*net.IPNet{ipnet})}, nil)
+	suite.Require().NoError(err)
+
+	go firehol.Run(time.Hour)
+
+	blocklist := ipblocklist.NewMulti(ipblocklist.NewNoop(), firehol)
+	defer blocklist.Shutdown()
+
+	suite.Eventually(func() bool {
+		return blocklist.Contains(net.ParseIP("10.0.0.10"))
+	}, time.Second, 10*time.Millisecond)
+	suite.False(blocklist.Contains(net.ParseIP("20.0.0.10")))
+}
+
+func (suite *MultiTestSuite) TestEmpty() {
+	blocklist := ipblocklist.NewMulti()
+
+	blocklist.Run(0)
+	suite.False(blocklist.Contains(net.ParseIP("10.0.0.10")))
+	blocklist.Shutdown()
+}
+
+func TestMulti(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &MultiTestSuite{})
+}
@@ -0,0 +1,7 @@
+package mtglib
+
+import "net"
+
+type noopASNLookup struct{}
+
+func (n noopASNLookup) LookupASN(_ net.IP) uint { return 0 }
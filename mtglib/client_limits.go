@@ -0,0 +1,77 @@
+package mtglib
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+
+	"github.com/9seconds/mtg/v2/mtglib/internal/clientlimit"
+)
+
+// ClientLimits defines limits of a single client IP and of its subnet:
+// /24 for IPv4 and /64 for IPv6. Zero value disables a limit.
+//
+// Rates are counted in new connections per second. A client can make a
+// burst of connections up to a rate at once.
+type ClientLimits struct {
+	// ConcurrentPerIP is a max count of active streams of a single IP.
+	ConcurrentPerIP uint
+
+	// ConcurrentPerSubnet is a max count of active streams of a subnet.
+	ConcurrentPerSubnet uint
+
+	// RatePerIP is a max count of new connections per second of a single
+	// IP.
+	RatePerIP uint
+
+	// RatePerSubnet is a max count of new connections per second of a
+	// subnet.
+	RatePerSubnet uint
+
+	// DomainFronting defines what to do with a client which has exceeded
+	// limits. If it is true, a connection is routed to a fronting domain
+	// as is, but no more than [LimitedFrontingConcurrency] at once.
+	// Otherwise, it is closed.
+	DomainFronting bool
+}
+
+func (c ClientLimits) toInternal() clientlimit.Limits {
+	return clientlimit.Limits{
+		ConcurrentPerIP:     c.ConcurrentPerIP,
+		ConcurrentPerSubnet: c.ConcurrentPerSubnet,
+		RatePerIP:           c.RatePerIP,
+		RatePerSubnet:       c.RatePerSubnet,
+	}
+}
+
+// acceptedConn is a connection passed to a worker pool by Serve.
+type acceptedConn struct {
+	conn    net.Conn
+	release func()
+
+	// domainFrontingOnly means that a client has exceeded its limits
+	// and is routed to a fronting domain without a handshake.
+	domainFrontingOnly bool
+}
+
+// getRateLimitReason classifies an error of client limiter.
+func getRateLimitReason(err error) RateLimitReason {
+	switch {
+	case errors.Is(err, clientlimit.ErrIPConcurrency):
+		return RateLimitIPConcurrency
+	case errors.Is(err, clientlimit.ErrSubnetConcurrency):
+		return RateLimitSubnetConcurrency
+	case errors.Is(err, clientlimit.ErrIPRate):
+		return RateLimitIPRate
+	}
+
+	return RateLimitSubnetRate
+}
+
+// ipToAddr converts an IP of TCP address. It is always valid so we
+// ignore conversion errors.
+func ipToAddr(ip net.IP) netip.Addr {
+	addr, _ := netip.AddrFromSlice(ip)
+
+	return addr
+}
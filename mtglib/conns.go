@@ -17,16 +17,17 @@ import (
 type connTraffic struct {
 	essentials.Conn
 
-	streamID string
-	stream   EventStream
-	ctx      context.Context
+	streamID   string
+	secretName string
+	stream     EventStream
+	ctx        context.Context
 }
 
 func (c connTraffic) Read(b []byte) (int, error) {
 	n, err := c.Conn.Read(b)
 
 	if n > 0 {
-		c.stream.Send(c.ctx, NewEventTraffic(c.streamID, uint(n), true))
+		c.send(uint(n), true)
 	}
 
 	return n, err //nolint: wrapcheck
@@ -36,25 +37,95 @@ func (c connTraffic) Write(b []byte) (int, error) {
 	n, err := c.Conn.Write(b)
 
 	if n > 0 {
-		c.stream.Send(c.ctx, NewEventTraffic(c.streamID, uint(n), false))
+		c.send(uint(n), false)
 	}
 
 	return n, err //nolint: wrapcheck
 }
 
+func (c connTraffic) send(traffic uint, isRead bool) {
+	evt := NewEventTraffic(c.streamID, traffic, isRead)
+	evt.secretName = c.secretName
+
+	c.stream.Send(c.ctx, evt)
+}
+
+type connTrafficQuota struct {
+	essentials.Conn
+
+	secretName string
+	quota      TrafficQuota
+	ctx        context.Context
+	ctxCancel  context.CancelFunc
+}
+
+func (c connTrafficQuota) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+
+	if n > 0 {
+		if quotaErr := c.consume(n); quotaErr != nil {
+			return n, quotaErr
+		}
+	}
+
+	return n, err //nolint: wrapcheck
+}
+
+func (c connTrafficQuota) Write(b []byte) (int, error) {
+	if err := c.consume(len(b)); err != nil {
+		return 0, err
+	}
+
+	return c.Conn.Write(b) //nolint: wrapcheck
+}
+
+func (c connTrafficQuota) consume(traffic int) error {
+	delay, err := c.quota.Consume(c.secretName, uint(traffic))
+	if err != nil {
+		// we have to terminate both directions of the stream, not only
+		// this one.
+		c.ctxCancel()
+
+		return fmt.Errorf("traffic quota of %s is exhausted: %w", c.secretName, err)
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-c.ctx.Done():
+		return c.ctx.Err() //nolint: wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
+
 type connRewind struct {
 	essentials.Conn
 
-	buf    bytes.Buffer
-	active io.Reader
+	buf     bytes.Buffer
+	active  io.Reader
+	rewound bool
 }
 
+// Read returns errClientClosed instead of io.EOF until connection is
+// rewound: a client hello is read from it.
 func (c *connRewind) Read(p []byte) (int, error) {
-	return c.active.Read(p)
+	n, err := c.active.Read(p)
+	if err == io.EOF && !c.rewound { //nolint: errorlint
+		err = errClientClosed
+	}
+
+	return n, err //nolint: wrapcheck
 }
 
 func (c *connRewind) Rewind() {
 	c.active = io.MultiReader(&c.buf, c.Conn)
+	c.rewound = true
 }
 
 func newConnRewind(conn essentials.Conn) *connRewind {
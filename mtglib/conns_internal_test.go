@@ -46,10 +46,11 @@ func (suite *ConnTrafficTestSuite) SetupTest() {
 	suite.eventStreamMock = &EventStreamMock{}
 	suite.connMock = &testlib.EssentialsConnMock{}
 	suite.conn = connTraffic{
-		Conn:     suite.connMock,
-		streamID: "CONNID",
-		ctx:      context.Background(),
-		stream:   suite.eventStreamMock,
+		Conn:       suite.connMock,
+		streamID:   "CONNID",
+		secretName: "SECRET",
+		ctx:        context.Background(),
+		stream:     suite.eventStreamMock,
 	}
 }
 
@@ -67,6 +68,7 @@ func (suite *ConnTrafficTestSuite) TestReadOk() {
 
 			suite.True(ok)
 			suite.Equal("CONNID", evt.StreamID())
+			suite.Equal("SECRET", evt.SecretName())
 			suite.WithinDuration(time.Now(), evt.Timestamp(), time.Second)
 			suite.EqualValues(10, evt.Traffic)
 			suite.True(evt.IsRead)
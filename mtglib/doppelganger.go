@@ -0,0 +1,30 @@
+package mtglib
+
+// DoppelGangerState is a snapshot of doppelganger. Doppelganger crawls
+// DoppelGangerURLs to learn how fronting domain chunks its traffic and
+// mimics it.
+type DoppelGangerState struct {
+	// URLs which are crawled.
+	URLs []string
+
+	// DRS defines if TLS Dynamic Record Sizing is active.
+	DRS bool
+
+	// CollectedDurations and CollectedCertSizes are counts of
+	// measurements which are collected so far.
+	CollectedDurations int
+	CollectedCertSizes int
+
+	// Calibrated is true if parameters of Weibull distribution of delays
+	// between TLS records are calculated from collected measurements.
+	// Otherwise, predefined ones are used.
+	Calibrated    bool
+	WeibullK      float64
+	WeibullLambda float64
+
+	// NoiseMean and NoiseJitter define a size of noise which is added
+	// to FakeTLS handshake. Zero mean means that certificate size of the
+	// fronting domain is not measured yet.
+	NoiseMean   int
+	NoiseJitter int
+}
@@ -6,8 +6,9 @@ import (
 )
 
 type eventBase struct {
-	streamID  string
-	timestamp time.Time
+	streamID   string
+	secretName string
+	timestamp  time.Time
 }
 
 // StreamID returns a ID of the stream this event belongs to.
@@ -20,6 +21,12 @@ func (e eventBase) Timestamp() time.Time {
 	return e.timestamp
 }
 
+// SecretName returns a name of the secret which was used by a client of
+// the stream. It is empty if the stream is not authenticated (yet).
+func (e eventBase) SecretName() string {
+	return e.secretName
+}
+
 // EventStart is emitted when mtg proxy starts to process a new
 // connection.
 type EventStart struct {
@@ -40,6 +47,10 @@ type EventConnectedToDC struct {
 
 	// DC is an index of the datacenter proxy has been connected to.
 	DC int
+
+	// DialDuration is a time of establishing TCP connection to Telegram.
+	// It is zero if connection was taken from a warm pool.
+	DialDuration time.Duration
 }
 
 // EventTraffic is emitted when we read/write some bytes on a connection.
@@ -64,6 +75,10 @@ type EventTraffic struct {
 // EventFinish is emitted when we stop to manage a connection.
 type EventFinish struct {
 	eventBase
+
+	// Duration is a time passed since a stream has been started. It
+	// could be zero if it is unknown.
+	Duration time.Duration
 }
 
 // EventDomainFronting is emitted when we connect to a front domain instead of
@@ -79,12 +94,18 @@ type EventConcurrencyLimited struct {
 }
 
 // EventIPBlocklisted is emitted when connection was declined because IP
-// address was found in IP blocklist.
+// address was found in IP blocklist, was not found in IP allowlist or
+// is banned by [IPBanlist]. IsBlockList is false for banned IPs.
+//
+// ASN is a number of autonomous system of a remote IP. It is 0 if it is
+// unknown or if [ASNLookup] is not set.
 type EventIPBlocklisted struct {
 	eventBase
 
 	RemoteIP    net.IP
 	IsBlockList bool
+	IsBanned    bool
+	ASN         uint
 }
 
 // EventReplayAttack is emitted when mtg detects a replay attack on a
@@ -93,13 +114,201 @@ type EventReplayAttack struct {
 	eventBase
 }
 
+// EventPreviousSecretUsed is emitted when a client has passed a handshake
+// with a previous secret. It is a signal that some clients have not updated
+// their links after secret rotation yet.
+type EventPreviousSecretUsed struct {
+	eventBase
+}
+
 // EventIPListSize is emitted when mtg updates a contents of the ip lists:
-// allowlist or blocklist.
+// allowlist or blocklist. It is also emitted with a number of active bans
+// of [IPBanlist]; IsBlockList is false then.
 type EventIPListSize struct {
 	eventBase
 
 	Size        int
 	IsBlockList bool
+	IsBanList   bool
+}
+
+// EventTelegramProbe is emitted when mtg has probed an address of Telegram
+// DC. RTT and FailureRate are moving averages across all probes of this
+// address.
+type EventTelegramProbe struct {
+	eventBase
+
+	RemoteIP net.IP
+	DC       int
+
+	// RTT is a time of establishing TCP connection. It is zero if address
+	// has never been reached.
+	RTT time.Duration
+
+	// FailureRate is a rate of failed probes, from 0 to 1.
+	FailureRate float64
+
+	// Forgotten is set if the address does not belong to the DC anymore
+	// and is not probed. Observers should drop everything they know
+	// about this pair.
+	Forgotten bool
+}
+
+// EventWarmPool is emitted when mtg has asked a pool of pre-dialed
+// connections for a connection to Telegram. Hit means that pool had one,
+// otherwise mtg dials a new connection.
+type EventWarmPool struct {
+	eventBase
+
+	DC  int
+	Hit bool
+}
+
+// HandshakeFailureReason defines why a client handshake has failed.
+type HandshakeFailureReason string
+
+const (
+	// HandshakeFailureNotTLS means that a client has sent something
+	// which is not TLS at all. Usually it is active probing or a client
+	// with a wrong secret type.
+	HandshakeFailureNotTLS HandshakeFailureReason = "not_tls"
+
+	// HandshakeFailureMalformedRecord means that TLS records or client
+	// hello are malformed or truncated.
+	HandshakeFailureMalformedRecord HandshakeFailureReason = "malformed_record"
+
+	// HandshakeFailureSNIMismatch means that client hello has no
+	// hostname of the secret in SNI.
+	HandshakeFailureSNIMismatch HandshakeFailureReason = "sni_mismatch"
+
+	// HandshakeFailureBadHMAC means that client random is not signed by
+	// any known secret.
+	HandshakeFailureBadHMAC HandshakeFailureReason = "bad_hmac"
+
+	// HandshakeFailureTimeSkew means that client hello is signed by a
+	// known secret but its timestamp is out of tolerated time skewness.
+	// Usually it is a client with a drifted clock.
+	HandshakeFailureTimeSkew HandshakeFailureReason = "time_skew"
+
+	// HandshakeFailureReplay means that client hello has been seen
+	// before.
+	HandshakeFailureReplay HandshakeFailureReason = "replay"
+
+	// HandshakeFailureUnsupportedConnectionType means that obfuscated
+	// handshake has requested unsupported connection type.
+	HandshakeFailureUnsupportedConnectionType HandshakeFailureReason = "unsupported_connection_type"
+
+	// HandshakeFailureTimeout means that a client has not finished a
+	// handshake in time.
+	HandshakeFailureTimeout HandshakeFailureReason = "timeout"
+
+	// HandshakeFailureClosed means that a client has closed a connection
+	// before a handshake has finished. Usually it is a client on a bad
+	// network.
+	HandshakeFailureClosed HandshakeFailureReason = "closed"
+
+	// HandshakeFailureReset means that a connection of a client has been
+	// reset before a handshake has finished.
+	HandshakeFailureReset HandshakeFailureReason = "reset"
+
+	// HandshakeFailureSecretInactive means that client hello is signed
+	// by a known secret which is outside of its validity window.
+	HandshakeFailureSecretInactive HandshakeFailureReason = "secret_inactive"
+
+	// HandshakeFailureQuotaExceeded means that client hello is signed by
+	// a known secret whose traffic quota is exhausted.
+	HandshakeFailureQuotaExceeded HandshakeFailureReason = "quota_exceeded"
+)
+
+// EventHandshakeFailed is emitted when a client handshake has failed.
+// Depending on a reason, a connection can be domain fronted after that.
+type EventHandshakeFailed struct {
+	eventBase
+
+	RemoteIP net.IP
+	Reason   HandshakeFailureReason
+}
+
+// EventHandshakeSucceeded is emitted when a client has passed both
+// FakeTLS and obfuscated handshakes.
+type EventHandshakeSucceeded struct {
+	eventBase
+
+	// FakeTLSDuration is a time since a stream has been started until
+	// server hello is sent.
+	FakeTLSDuration time.Duration
+
+	// ObfuscatedDuration is a time of obfuscated handshake, which follows
+	// FakeTLS one.
+	ObfuscatedDuration time.Duration
+}
+
+// EventClockSkew is emitted when mtg has verified a timestamp of a
+// client hello signed by a known secret. Skew is a difference between
+// a client clock and a clock of mtg: it is positive if client is ahead.
+// Accepted is false if skew is out of tolerated time skewness and
+// handshake is rejected.
+type EventClockSkew struct {
+	eventBase
+
+	Skew     time.Duration
+	Accepted bool
+}
+
+// RateLimitReason defines which limit of [ClientLimits] a client has
+// exceeded.
+type RateLimitReason string
+
+const (
+	// RateLimitIPConcurrency means that a client IP has too many active
+	// streams.
+	RateLimitIPConcurrency RateLimitReason = "ip_concurrency"
+
+	// RateLimitSubnetConcurrency means that a subnet of the client has
+	// too many active streams.
+	RateLimitSubnetConcurrency RateLimitReason = "subnet_concurrency"
+
+	// RateLimitIPRate means that a client IP opens new connections too
+	// often.
+	RateLimitIPRate RateLimitReason = "ip_rate"
+
+	// RateLimitSubnetRate means that a subnet of the client opens new
+	// connections too often.
+	RateLimitSubnetRate RateLimitReason = "subnet_rate"
+)
+
+// EventRateLimited is emitted when a client connection is rejected
+// because a client IP or its subnet has exceeded [ClientLimits].
+// Depending on settings, such connection is closed or routed to a
+// fronting domain.
+type EventRateLimited struct {
+	eventBase
+
+	RemoteIP net.IP
+	Reason   RateLimitReason
+}
+
+// EventIPBanned is emitted when a client IP is banned by [IPBanlist].
+// Reason is a reason of the failed handshake which has resulted in a
+// ban, Duration is a time of this ban.
+type EventIPBanned struct {
+	eventBase
+
+	RemoteIP net.IP
+	Reason   HandshakeFailureReason
+	Duration time.Duration
+}
+
+// EventOverflow is emitted by an event stream which has not delivered
+// some events because observers were too slow. Dropped is a number of
+// events which were thrown away, Coalesced is a number of traffic
+// events which were merged into other ones. Both values are counted
+// since a previous EventOverflow.
+type EventOverflow struct {
+	eventBase
+
+	Dropped   uint64
+	Coalesced uint64
 }
 
 // NewEventStart creates a new EventStart event.
@@ -190,6 +399,18 @@ func NewEventIPAllowlisted(remoteIP net.IP) EventIPBlocklisted {
 	}
 }
 
+// NewEventIPBanlisted creates a NewEventIPBlocklisted event with a mark that
+// IP is banned by [IPBanlist].
+func NewEventIPBanlisted(remoteIP net.IP) EventIPBlocklisted {
+	return EventIPBlocklisted{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+		},
+		RemoteIP: remoteIP,
+		IsBanned: true,
+	}
+}
+
 // NewEventReplayAttack creates a new EventReplayAttack event.
 func NewEventReplayAttack(streamID string) EventReplayAttack {
 	return EventReplayAttack{
@@ -200,6 +421,16 @@ func NewEventReplayAttack(streamID string) EventReplayAttack {
 	}
 }
 
+// NewEventPreviousSecretUsed creates a new EventPreviousSecretUsed event.
+func NewEventPreviousSecretUsed(streamID string) EventPreviousSecretUsed {
+	return EventPreviousSecretUsed{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+			streamID:  streamID,
+		},
+	}
+}
+
 // NewEventIPListSize creates a new EventIPListSize event.
 func NewEventIPListSize(size int, isBlockList bool) EventIPListSize {
 	return EventIPListSize{
@@ -210,3 +441,113 @@ func NewEventIPListSize(size int, isBlockList bool) EventIPListSize {
 		IsBlockList: isBlockList,
 	}
 }
+
+// NewEventIPBanlistSize creates a new EventIPListSize event with a number of
+// active bans.
+func NewEventIPBanlistSize(size int) EventIPListSize {
+	return EventIPListSize{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+		},
+		Size:      size,
+		IsBanList: true,
+	}
+}
+
+// NewEventTelegramProbe creates a new EventTelegramProbe event.
+func NewEventTelegramProbe(remoteIP net.IP, dc int, rtt time.Duration, failureRate float64) EventTelegramProbe {
+	return EventTelegramProbe{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+		},
+		RemoteIP:    remoteIP,
+		DC:          dc,
+		RTT:         rtt,
+		FailureRate: failureRate,
+	}
+}
+
+// NewEventWarmPool creates a new EventWarmPool event.
+func NewEventWarmPool(streamID string, dc int, hit bool) EventWarmPool {
+	return EventWarmPool{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+			streamID:  streamID,
+		},
+		DC:  dc,
+		Hit: hit,
+	}
+}
+
+// NewEventHandshakeFailed creates a new EventHandshakeFailed event.
+func NewEventHandshakeFailed(streamID string, remoteIP net.IP, reason HandshakeFailureReason) EventHandshakeFailed {
+	return EventHandshakeFailed{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+			streamID:  streamID,
+		},
+		RemoteIP: remoteIP,
+		Reason:   reason,
+	}
+}
+
+// NewEventClockSkew creates a new EventClockSkew event.
+func NewEventClockSkew(streamID string, skew time.Duration, accepted bool) EventClockSkew {
+	return EventClockSkew{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+			streamID:  streamID,
+		},
+		Skew:     skew,
+		Accepted: accepted,
+	}
+}
+
+// NewEventHandshakeSucceeded creates a new EventHandshakeSucceeded event.
+func NewEventHandshakeSucceeded(
+	streamID string,
+	fakeTLSDuration, obfuscatedDuration time.Duration,
+) EventHandshakeSucceeded {
+	return EventHandshakeSucceeded{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+			streamID:  streamID,
+		},
+		FakeTLSDuration:    fakeTLSDuration,
+		ObfuscatedDuration: obfuscatedDuration,
+	}
+}
+
+// NewEventOverflow creates a new EventOverflow event.
+func NewEventOverflow(dropped, coalesced uint64) EventOverflow {
+	return EventOverflow{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+		},
+		Dropped:   dropped,
+		Coalesced: coalesced,
+	}
+}
+
+// NewEventRateLimited creates a new EventRateLimited event.
+func NewEventRateLimited(remoteIP net.IP, reason RateLimitReason) EventRateLimited {
+	return EventRateLimited{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+		},
+		RemoteIP: remoteIP,
+		Reason:   reason,
+	}
+}
+
+// NewEventIPBanned creates a new EventIPBanned event.
+func NewEventIPBanned(remoteIP net.IP, reason HandshakeFailureReason, duration time.Duration) EventIPBanned {
+	return EventIPBanned{
+		eventBase: eventBase{
+			timestamp: time.Now(),
+		},
+		RemoteIP: remoteIP,
+		Reason:   reason,
+		Duration: duration,
+	}
+}
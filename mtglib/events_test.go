@@ -20,6 +20,14 @@ func (suite *EventsTestSuite) TestEventStart() {
 	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
 }
 
+func (suite *EventsTestSuite) TestSecretNamer() {
+	var evt mtglib.Event = mtglib.NewEventStart("CONNID", net.ParseIP("10.0.0.10"))
+
+	namer, ok := evt.(mtglib.SecretNamer)
+	suite.True(ok)
+	suite.Empty(namer.SecretName())
+}
+
 func (suite *EventsTestSuite) TestEventFinish() {
 	evt := mtglib.NewEventFinish("CONNID")
 
@@ -61,6 +69,7 @@ func (suite *EventsTestSuite) TestEventIPBlocklisted() {
 	suite.Empty(evt.StreamID())
 	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
 	suite.True(evt.IsBlockList)
+	suite.Zero(evt.ASN)
 }
 
 func (suite *EventsTestSuite) TestEventIPAllowlisted() {
@@ -71,6 +80,15 @@ func (suite *EventsTestSuite) TestEventIPAllowlisted() {
 	suite.False(evt.IsBlockList)
 }
 
+func (suite *EventsTestSuite) TestEventIPBanlisted() {
+	evt := mtglib.NewEventIPBanlisted(net.ParseIP("10.0.0.10"))
+
+	suite.Empty(evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.False(evt.IsBlockList)
+	suite.True(evt.IsBanned)
+}
+
 func (suite *EventsTestSuite) TestEventReplayAttack() {
 	evt := mtglib.NewEventReplayAttack("CONNID")
 
@@ -78,6 +96,13 @@ func (suite *EventsTestSuite) TestEventReplayAttack() {
 	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
 }
 
+func (suite *EventsTestSuite) TestEventPreviousSecretUsed() {
+	evt := mtglib.NewEventPreviousSecretUsed("CONNID")
+
+	suite.Equal("CONNID", evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+}
+
 func (suite *EventsTestSuite) TestEventIPListSize() {
 	evt := mtglib.NewEventIPListSize(10, false)
 
@@ -87,6 +112,91 @@ func (suite *EventsTestSuite) TestEventIPListSize() {
 	suite.False(evt.IsBlockList)
 }
 
+func (suite *EventsTestSuite) TestEventIPBanlistSize() {
+	evt := mtglib.NewEventIPBanlistSize(10)
+
+	suite.Empty(evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.Equal(10, evt.Size)
+	suite.False(evt.IsBlockList)
+	suite.True(evt.IsBanList)
+}
+
+func (suite *EventsTestSuite) TestEventTelegramProbe() {
+	evt := mtglib.NewEventTelegramProbe(net.ParseIP("10.0.0.10"), 2, time.Second, 0.5)
+
+	suite.Empty(evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.Equal("10.0.0.10", evt.RemoteIP.String())
+	suite.Equal(2, evt.DC)
+	suite.Equal(time.Second, evt.RTT)
+	suite.InDelta(0.5, evt.FailureRate, 0.001)
+}
+
+func (suite *EventsTestSuite) TestEventWarmPool() {
+	evt := mtglib.NewEventWarmPool("CONNID", 2, true)
+
+	suite.Equal("CONNID", evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.Equal(2, evt.DC)
+	suite.True(evt.Hit)
+}
+
+func (suite *EventsTestSuite) TestEventHandshakeFailed() {
+	evt := mtglib.NewEventHandshakeFailed("CONNID", net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureBadHMAC)
+
+	suite.Equal("CONNID", evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.Equal("10.0.0.10", evt.RemoteIP.String())
+	suite.Equal(mtglib.HandshakeFailureBadHMAC, evt.Reason)
+}
+
+func (suite *EventsTestSuite) TestEventClockSkew() {
+	evt := mtglib.NewEventClockSkew("CONNID", 3*time.Second, true)
+
+	suite.Equal("CONNID", evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.Equal(3*time.Second, evt.Skew)
+	suite.True(evt.Accepted)
+}
+
+func (suite *EventsTestSuite) TestEventHandshakeSucceeded() {
+	evt := mtglib.NewEventHandshakeSucceeded("CONNID", time.Second, time.Millisecond)
+
+	suite.Equal("CONNID", evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.Equal(time.Second, evt.FakeTLSDuration)
+	suite.Equal(time.Millisecond, evt.ObfuscatedDuration)
+}
+
+func (suite *EventsTestSuite) TestEventOverflow() {
+	evt := mtglib.NewEventOverflow(3, 10)
+
+	suite.Empty(evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.EqualValues(3, evt.Dropped)
+	suite.EqualValues(10, evt.Coalesced)
+}
+
+func (suite *EventsTestSuite) TestEventRateLimited() {
+	evt := mtglib.NewEventRateLimited(net.ParseIP("10.0.0.10"), mtglib.RateLimitSubnetRate)
+
+	suite.Empty(evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.Equal("10.0.0.10", evt.RemoteIP.String())
+	suite.Equal(mtglib.RateLimitSubnetRate, evt.Reason)
+}
+
+func (suite *EventsTestSuite) TestEventIPBanned() {
+	evt := mtglib.NewEventIPBanned(net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureReplay, time.Hour)
+
+	suite.Empty(evt.StreamID())
+	suite.WithinDuration(time.Now(), evt.Timestamp(), 10*time.Millisecond)
+	suite.Equal("10.0.0.10", evt.RemoteIP.String())
+	suite.Equal(mtglib.HandshakeFailureReplay, evt.Reason)
+	suite.Equal(time.Hour, evt.Duration)
+}
+
 func TestEvents(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, &EventsTestSuite{})
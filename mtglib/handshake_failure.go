@@ -0,0 +1,51 @@
+package mtglib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/9seconds/mtg/v2/mtglib/internal/obfuscation"
+	"github.com/9seconds/mtg/v2/mtglib/internal/tls/fake"
+)
+
+// errClientClosed is returned instead of io.EOF while a handshake is
+// read from a client. Unlike io.EOF, io.ReadFull does not turn it into
+// io.ErrUnexpectedEOF, so a client which has gone in the middle of a
+// record is not taken for a client which has sent a malformed one.
+var errClientClosed = fmt.Errorf("client has closed a connection: %w", io.EOF)
+
+// getHandshakeFailureReason classifies an error of client handshake.
+// Timeouts are checked first: a client which is cut by a deadline has
+// usually sent a truncated record. Closed and reset connections go next
+// for the same reason.
+func getHandshakeFailureReason(err error) HandshakeFailureReason {
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return HandshakeFailureTimeout
+	case errors.Is(err, io.EOF):
+		return HandshakeFailureClosed
+	case errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.EPIPE):
+		return HandshakeFailureReset
+	case errors.Is(err, fake.ErrNotTLS):
+		return HandshakeFailureNotTLS
+	case errors.Is(err, fake.ErrSNIMismatch):
+		return HandshakeFailureSNIMismatch
+	case errors.Is(err, fake.ErrBadDigest):
+		return HandshakeFailureBadHMAC
+	case errors.Is(err, fake.ErrTimeSkew):
+		return HandshakeFailureTimeSkew
+	case errors.Is(err, obfuscation.ErrUnsupportedConnectionType):
+		return HandshakeFailureUnsupportedConnectionType
+	}
+
+	return HandshakeFailureMalformedRecord
+}
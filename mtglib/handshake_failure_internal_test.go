@@ -0,0 +1,68 @@
+package mtglib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/essentials"
+	"github.com/9seconds/mtg/v2/mtglib/internal/obfuscation"
+	"github.com/9seconds/mtg/v2/mtglib/internal/tls/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHandshakeFailureReason(t *testing.T) {
+	t.Parallel()
+
+	testData := map[HandshakeFailureReason]error{
+		HandshakeFailureTimeout:                   os.ErrDeadlineExceeded,
+		HandshakeFailureNotTLS:                    fake.ErrNotTLS,
+		HandshakeFailureSNIMismatch:               fake.ErrSNIMismatch,
+		HandshakeFailureBadHMAC:                   fake.ErrBadDigest,
+		HandshakeFailureTimeSkew:                  fake.ErrTimeSkew,
+		HandshakeFailureUnsupportedConnectionType: obfuscation.ErrUnsupportedConnectionType,
+		HandshakeFailureMalformedRecord:           io.ErrUnexpectedEOF,
+		HandshakeFailureClosed:                    io.EOF,
+		HandshakeFailureReset:                     syscall.ECONNRESET,
+	}
+
+	for reason, err := range testData {
+		t.Run(string(reason), func(t *testing.T) {
+			t.Parallel()
+
+			wrapped := fmt.Errorf("cannot read client hello: %w", err)
+
+			assert.Equal(t, reason, getHandshakeFailureReason(wrapped))
+		})
+	}
+
+	assert.Equal(t,
+		HandshakeFailureTimeout,
+		getHandshakeFailureReason(errors.Join(fake.ErrNotTLS, os.ErrDeadlineExceeded)))
+}
+
+func TestConnRewindClosedMidRecord(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+
+	go func() {
+		client.Write([]byte{0x16, 0x03}) //nolint: errcheck
+		client.Close()                   //nolint: errcheck
+	}()
+
+	rewind := newConnRewind(essentials.WrapNetConn(server))
+
+	_, err := io.ReadFull(rewind, make([]byte, 5))
+	assert.Equal(t, HandshakeFailureClosed, getHandshakeFailureReason(err))
+
+	rewind.Rewind()
+
+	data, err := io.ReadAll(rewind)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x16, 0x03}, data)
+}
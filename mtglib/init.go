@@ -35,6 +35,16 @@ var (
 	// value is invalid (no host or payload are zeroes).
 	ErrSecretInvalid = errors.New("secret is invalid")
 
+	// ErrSecretNameInvalid is returned if you are trying to create a proxy but
+	// some secret has an empty name or its name clashes with
+	// DefaultSecretName or PreviousSecretName.
+	ErrSecretNameInvalid = errors.New("secret name is invalid")
+
+	// ErrSecretHostMismatch is returned if you are trying to create a proxy
+	// with many secrets but they have different hostnames. All secrets
+	// share the same fronting domain.
+	ErrSecretHostMismatch = errors.New("secrets have different hostnames")
+
 	// ErrNetworkIsNotDefined is returned if you are trying to create a proxy but
 	// network value is undefined.
 	ErrNetworkIsNotDefined = errors.New("network is not defined")
@@ -51,10 +61,26 @@ var (
 	// but ip allowlist instance is not defined.
 	ErrIPAllowlistIsNotDefined = errors.New("ip allowlist is not defined")
 
+	// ErrIPBanlistIsNotDefined is returned if you are trying to create a
+	// proxy but ip banlist instance is not defined.
+	ErrIPBanlistIsNotDefined = errors.New("ip banlist is not defined")
+
+	// ErrTrafficQuotaIsNotDefined is returned if you are trying to create a
+	// proxy but traffic quota instance is not defined.
+	ErrTrafficQuotaIsNotDefined = errors.New("traffic quota is not defined")
+
 	// ErrEventStreamIsNotDefined is returned if you are trying to create a proxy
 	// but event stream instance is not defined.
 	ErrEventStreamIsNotDefined = errors.New("event stream is not defined")
 
+	// ErrAdTagInvalid is returned if you are trying to create a proxy with
+	// an advertisement tag of incorrect length or without middle proxies.
+	ErrAdTagInvalid = errors.New("ad tag is invalid")
+
+	// ErrProxyClosed is returned if you are trying to reload a proxy which
+	// is already shut down.
+	ErrProxyClosed = errors.New("proxy is closed")
+
 	// ErrLoggerIsNotDefined is returned if you are trying to create a proxy but
 	// logger is not defined.
 	ErrLoggerIsNotDefined = errors.New("logger is not defined")
@@ -81,6 +107,10 @@ const (
 	// avoid racing with MTProto ping_delay_disconnect (~60s interval).
 	DefaultIdleTimeout = 5 * time.Minute
 
+	// DefaultPreviousSecretGracePeriod is a recommended period of time
+	// when a previous secret is still accepted after rotation.
+	DefaultPreviousSecretGracePeriod = 7 * 24 * time.Hour
+
 	// DefaultHandshakeTimeout defines a time period during which the
 	// all handshake ceremonies must be completed.
 	DefaultHandshakeTimeout = 10 * time.Second
@@ -92,10 +122,36 @@ const (
 	// DefaultPreferIP is a default value for Telegram IP connectivity preference.
 	DefaultPreferIP = "prefer-ipv6"
 
+	// DefaultWarmPoolSize is a default max count of pre-dialed connections
+	// to each DC.
+	DefaultWarmPoolSize = 8
+
+	// DefaultWarmPoolMaxIdle is a default time period after which a
+	// pre-dialed connection to Telegram is dropped. Telegram closes
+	// connections which do not send anything, so it should be short.
+	DefaultWarmPoolMaxIdle = 10 * time.Second
+
+	// DefaultDCProbeEach is a default time period between probes of
+	// Telegram addresses.
+	DefaultDCProbeEach = time.Minute
+
+	// LimitedFrontingConcurrency is a max count of simultaneous streams
+	// of clients which have exceeded their limits and are routed to a
+	// fronting domain. Other connections of such clients are closed, so
+	// they cannot take all workers with domain fronting.
+	LimitedFrontingConcurrency = 64
+
 	// SecretKeyLength defines a length of the secret bytes used by Telegram and a
 	// proxy.
 	SecretKeyLength = 16
 
+	// DefaultSecretName is a name of the secret given as ProxyOpts.Secret.
+	DefaultSecretName = "default"
+
+	// PreviousSecretName is a name of the secret given as
+	// ProxyOpts.PreviousSecret.
+	PreviousSecretName = "previous"
+
 	// ConnectionIDBytesLength defines a count of random bytes used to generate a
 	// stream/connection ids.
 	ConnectionIDBytesLength = 16
@@ -196,6 +252,59 @@ type IPBlocklist interface {
 	Shutdown()
 }
 
+// IPBanlist bans misbehaving client IPs for some time.
+//
+// Unlike IPBlocklist, it is populated by a proxy itself: each failed
+// client handshake is reported as an offense of a client IP. It is up
+// to implementation to decide which offenses result in a ban and for how
+// long. Connections of banned IPs are closed without reading anything
+// from a socket, the same way as blocklisted ones.
+type IPBanlist interface {
+	// Banned checks if given IP address is banned now.
+	Banned(net.IP) bool
+
+	// Offend registers a failed handshake of a given IP address. If it
+	// results in a new ban, a duration of this ban is returned. Otherwise,
+	// it returns 0.
+	Offend(net.IP, HandshakeFailureReason) time.Duration
+
+	// Shutdown stops a banlist. It is assumed that none will access it
+	// after.
+	Shutdown()
+}
+
+// ASNLookup finds autonomous systems of client IPs. It is used only to
+// enrich [EventIPBlocklisted] events: mtg does not make any decisions
+// based on it. If you want to block autonomous systems, use an
+// implementation of IPBlocklist.
+type ASNLookup interface {
+	// LookupASN returns a number of autonomous system of a given IP
+	// address. If it is unknown, 0 is returned.
+	LookupASN(net.IP) uint
+}
+
+// TrafficQuota limits traffic of the streams authenticated with some
+// secret.
+//
+// Each secret may have a quota on a total traffic it can pass through a
+// proxy and a cap on its throughput. If quota is exhausted, new streams
+// with such secret are routed to a fronting domain, and existing ones are
+// terminated.
+type TrafficQuota interface {
+	// Allowed checks if a new stream can be authenticated with a given
+	// secret.
+	Allowed(secretName string) bool
+
+	// Consume registers traffic of the stream authenticated with a given
+	// secret. It returns a time period a stream has to wait to keep a
+	// throughput under the cap. If quota is exhausted, an error is
+	// returned and the stream is terminated.
+	Consume(secretName string, traffic uint) (time.Duration, error)
+
+	// Shutdown stops a quota. It is assumed that none will access it after.
+	Shutdown()
+}
+
 // Event is a data structure which is populated during mtg request processing
 // lifecycle. Each request popluates many events:
 //  1. Client connected
@@ -213,6 +322,16 @@ type Event interface {
 	Timestamp() time.Time
 }
 
+// SecretNamer is an optional interface of Event. All events of mtg
+// implement it but it is not a part of Event, so events of other
+// producers do not have to.
+type SecretNamer interface {
+	// SecretName returns a name of the secret that was used by a client to
+	// authenticate a stream. If stream is not authenticated or event is not
+	// related to any stream, an empty string is returned.
+	SecretName() string
+}
+
 // EventStream is an abstraction that accepts a set of events produced by mtg.
 // Its main goal is to inject your logging or monitoring system.
 //
@@ -0,0 +1,193 @@
+// Clientlimit package limits concurrent streams and a rate of new
+// connections of a single client IP and of its subnet.
+package clientlimit
+
+import (
+	"errors"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+const (
+	// IPv4 and IPv6 addresses are grouped into subnets of these sizes.
+	SubnetIPv4Bits = 24
+	SubnetIPv6Bits = 64
+
+	// How often should we forget clients which have no active streams
+	// and have restored their rate budget.
+	CleanupEach = time.Minute
+)
+
+var (
+	ErrIPConcurrency     = errors.New("too many streams of ip")
+	ErrSubnetConcurrency = errors.New("too many streams of subnet")
+	ErrIPRate            = errors.New("too many new connections of ip")
+	ErrSubnetRate        = errors.New("too many new connections of subnet")
+)
+
+// Limits defines limits of a client. Zero value disables a limit.
+// Rates are new connections per second. A client can make a burst of
+// connections up to a rate at once.
+type Limits struct {
+	ConcurrentPerIP     uint
+	ConcurrentPerSubnet uint
+	RatePerIP           uint
+	RatePerSubnet       uint
+}
+
+func (l Limits) enabled() bool {
+	return l != Limits{}
+}
+
+// bucket has a number of active streams and a token bucket of new
+// connections.
+type bucket struct {
+	active    uint
+	tokens    float64
+	updatedAt time.Time
+}
+
+func (b *bucket) refill(now time.Time, rate uint) {
+	burst := float64(rate)
+
+	b.tokens = min(burst, b.tokens+now.Sub(b.updatedAt).Seconds()*burst)
+	b.updatedAt = now
+}
+
+// take spends a token of a new connection. Without a rate limit there
+// is nothing to spend, so tokens never go below zero and a bucket can
+// become idle.
+func (b *bucket) take(rate uint) {
+	if rate > 0 {
+		b.tokens--
+	}
+}
+
+func (b *bucket) idle(now time.Time, rate uint) bool {
+	if b.active > 0 {
+		return false
+	}
+
+	b.refill(now, rate)
+
+	return b.tokens >= float64(rate)
+}
+
+type Limiter struct {
+	mutex         sync.Mutex
+	limits        Limits
+	ips           map[netip.Addr]*bucket
+	subnets       map[netip.Prefix]*bucket
+	lastCleanupAt time.Time
+}
+
+func (l *Limiter) SetLimits(limits Limits) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.limits = limits
+}
+
+// Acquire checks if a client can open a new stream. If it can, a stream
+// is counted until release function is called.
+func (l *Limiter) Acquire(addr netip.Addr) (func(), error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !l.limits.enabled() {
+		return func() {}, nil
+	}
+
+	now := time.Now()
+	addr = addr.Unmap()
+
+	l.cleanup(now)
+
+	subnetBits := SubnetIPv6Bits
+	if addr.Is4() {
+		subnetBits = SubnetIPv4Bits
+	}
+
+	// bits are always valid for a family of address
+	subnet, _ := addr.Prefix(subnetBits)
+
+	ipBucket := getBucket(l.ips, addr, now, l.limits.RatePerIP)
+	subnetBucket := getBucket(l.subnets, subnet, now, l.limits.RatePerSubnet)
+
+	switch {
+	case exceeds(ipBucket.active, l.limits.ConcurrentPerIP):
+		return nil, ErrIPConcurrency
+	case exceeds(subnetBucket.active, l.limits.ConcurrentPerSubnet):
+		return nil, ErrSubnetConcurrency
+	case l.limits.RatePerIP > 0 && ipBucket.tokens < 1:
+		return nil, ErrIPRate
+	case l.limits.RatePerSubnet > 0 && subnetBucket.tokens < 1:
+		return nil, ErrSubnetRate
+	}
+
+	ipBucket.active++
+	ipBucket.take(l.limits.RatePerIP)
+	subnetBucket.active++
+	subnetBucket.take(l.limits.RatePerSubnet)
+
+	once := sync.Once{}
+
+	return func() {
+		once.Do(func() {
+			l.mutex.Lock()
+			defer l.mutex.Unlock()
+
+			ipBucket.active--
+			subnetBucket.active--
+		})
+	}, nil
+}
+
+func (l *Limiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanupAt) < CleanupEach {
+		return
+	}
+
+	l.lastCleanupAt = now
+
+	for k, v := range l.ips {
+		if v.idle(now, l.limits.RatePerIP) {
+			delete(l.ips, k)
+		}
+	}
+
+	for k, v := range l.subnets {
+		if v.idle(now, l.limits.RatePerSubnet) {
+			delete(l.subnets, k)
+		}
+	}
+}
+
+func getBucket[K comparable](buckets map[K]*bucket, key K, now time.Time, rate uint) *bucket {
+	value, ok := buckets[key]
+	if !ok {
+		value = &bucket{
+			tokens:    float64(rate),
+			updatedAt: now,
+		}
+		buckets[key] = value
+	}
+
+	value.refill(now, rate)
+
+	return value
+}
+
+func exceeds(active, limit uint) bool {
+	return limit > 0 && active >= limit
+}
+
+func New(limits Limits) *Limiter {
+	return &Limiter{
+		limits:        limits,
+		ips:           map[netip.Addr]*bucket{},
+		subnets:       map[netip.Prefix]*bucket{},
+		lastCleanupAt: time.Now(),
+	}
+}
@@ -0,0 +1,65 @@
+package clientlimit
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LimiterCleanupTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LimiterCleanupTestSuite) TestForgetConcurrencyOnly() {
+	limiter := New(Limits{
+		ConcurrentPerIP:     1,
+		ConcurrentPerSubnet: 1,
+	})
+
+	release, err := limiter.Acquire(netip.MustParseAddr("10.0.0.1"))
+	suite.NoError(err)
+
+	release()
+
+	limiter.cleanup(time.Now().Add(CleanupEach))
+
+	suite.Empty(limiter.ips)
+	suite.Empty(limiter.subnets)
+}
+
+func (suite *LimiterCleanupTestSuite) TestKeepActive() {
+	limiter := New(Limits{
+		ConcurrentPerIP: 1,
+	})
+
+	_, err := limiter.Acquire(netip.MustParseAddr("10.0.0.1"))
+	suite.NoError(err)
+
+	limiter.cleanup(time.Now().Add(CleanupEach))
+
+	suite.Len(limiter.ips, 1)
+	suite.Len(limiter.subnets, 1)
+}
+
+func (suite *LimiterCleanupTestSuite) TestForgetRestoredRate() {
+	limiter := New(Limits{
+		RatePerIP: 2,
+	})
+
+	release, err := limiter.Acquire(netip.MustParseAddr("10.0.0.1"))
+	suite.NoError(err)
+
+	release()
+
+	limiter.cleanup(time.Now().Add(CleanupEach))
+
+	suite.Empty(limiter.ips)
+	suite.Empty(limiter.subnets)
+}
+
+func TestLimiterCleanup(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &LimiterCleanupTestSuite{})
+}
@@ -0,0 +1,148 @@
+package clientlimit_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib/internal/clientlimit"
+	"github.com/stretchr/testify/suite"
+)
+
+type LimiterTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LimiterTestSuite) TestDisabled() {
+	limiter := clientlimit.New(clientlimit.Limits{})
+	addr := netip.MustParseAddr("10.0.0.1")
+
+	for range 100 {
+		release, err := limiter.Acquire(addr)
+		suite.NoError(err)
+		suite.NotNil(release)
+	}
+}
+
+func (suite *LimiterTestSuite) TestConcurrentPerIP() {
+	limiter := clientlimit.New(clientlimit.Limits{
+		ConcurrentPerIP: 2,
+	})
+	addr := netip.MustParseAddr("10.0.0.1")
+
+	release1, err := limiter.Acquire(addr)
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(addr)
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(addr)
+	suite.ErrorIs(err, clientlimit.ErrIPConcurrency)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("10.0.0.2"))
+	suite.NoError(err)
+
+	release1()
+	release1()
+
+	_, err = limiter.Acquire(addr)
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(addr)
+	suite.ErrorIs(err, clientlimit.ErrIPConcurrency)
+}
+
+func (suite *LimiterTestSuite) TestConcurrentPerSubnet() {
+	limiter := clientlimit.New(clientlimit.Limits{
+		ConcurrentPerSubnet: 2,
+	})
+
+	_, err := limiter.Acquire(netip.MustParseAddr("10.0.0.1"))
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("::ffff:10.0.0.2"))
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("10.0.0.3"))
+	suite.ErrorIs(err, clientlimit.ErrSubnetConcurrency)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("10.0.1.1"))
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("2001:db8::1"))
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("2001:db8::ffff"))
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("2001:db8::1:1"))
+	suite.ErrorIs(err, clientlimit.ErrSubnetConcurrency)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("2001:db8:0:1::1"))
+	suite.NoError(err)
+}
+
+func (suite *LimiterTestSuite) TestRatePerIP() {
+	limiter := clientlimit.New(clientlimit.Limits{
+		RatePerIP: 10,
+	})
+	addr := netip.MustParseAddr("10.0.0.1")
+
+	for range 10 {
+		release, err := limiter.Acquire(addr)
+		suite.NoError(err)
+
+		release()
+	}
+
+	_, err := limiter.Acquire(addr)
+	suite.ErrorIs(err, clientlimit.ErrIPRate)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("10.0.0.2"))
+	suite.NoError(err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	_, err = limiter.Acquire(addr)
+	suite.NoError(err)
+}
+
+func (suite *LimiterTestSuite) TestRatePerSubnet() {
+	limiter := clientlimit.New(clientlimit.Limits{
+		RatePerSubnet: 2,
+	})
+
+	_, err := limiter.Acquire(netip.MustParseAddr("10.0.0.1"))
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("10.0.0.2"))
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(netip.MustParseAddr("10.0.0.3"))
+	suite.ErrorIs(err, clientlimit.ErrSubnetRate)
+}
+
+func (suite *LimiterTestSuite) TestSetLimits() {
+	limiter := clientlimit.New(clientlimit.Limits{
+		ConcurrentPerIP: 1,
+	})
+	addr := netip.MustParseAddr("10.0.0.1")
+
+	_, err := limiter.Acquire(addr)
+	suite.NoError(err)
+
+	_, err = limiter.Acquire(addr)
+	suite.ErrorIs(err, clientlimit.ErrIPConcurrency)
+
+	limiter.SetLimits(clientlimit.Limits{
+		ConcurrentPerIP: 2,
+	})
+
+	_, err = limiter.Acquire(addr)
+	suite.NoError(err)
+}
+
+func TestLimiter(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &LimiterTestSuite{})
+}
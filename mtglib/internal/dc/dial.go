@@ -0,0 +1,124 @@
+package dc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/9seconds/mtg/v2/essentials"
+)
+
+type dialResult struct {
+	conn essentials.Conn
+	addr Addr
+	err  error
+}
+
+// Dial races connections to given addresses in a spirit of Happy
+// Eyeballs (RFC 8305). Addresses of different IP families are
+// interleaved, the next attempt starts either after attemptDelay or
+// right after a previous attempt has failed. The first established
+// connection wins, all other attempts are cancelled.
+func Dial(ctx context.Context, dialer Dialer, addrs []Addr, attemptDelay time.Duration) (essentials.Conn, Addr, error) {
+	if len(addrs) == 0 {
+		return nil, Addr{}, ErrNoAddresses
+	}
+
+	addrs = interleave(addrs)
+
+	ctx, cancel := context.WithCancel(ctx)
+	results := make(chan dialResult, len(addrs))
+	errs := make([]error, 0, len(addrs))
+	started := 0
+	pending := 0
+
+	start := func() {
+		addr := addrs[started]
+
+		started++
+		pending++
+
+		go func() {
+			conn, err := dialer.DialContext(ctx, addr.Network, addr.Address)
+			results <- dialResult{conn: conn, addr: addr, err: err}
+		}()
+	}
+
+	start()
+
+	for pending > 0 {
+		var attemptTimer <-chan time.Time
+
+		if started < len(addrs) {
+			attemptTimer = time.After(attemptDelay)
+		}
+
+		select {
+		case <-attemptTimer:
+			start()
+		case res := <-results:
+			pending--
+
+			if res.err == nil {
+				cancel()
+				go closeLosers(results, pending)
+
+				return res.conn, res.addr, nil
+			}
+
+			errs = append(errs, fmt.Errorf("cannot dial to %s: %w", res.addr.Address, res.err))
+
+			if started < len(addrs) {
+				start()
+			}
+		}
+	}
+
+	cancel()
+
+	return nil, Addr{}, errors.Join(errs...)
+}
+
+// closeLosers closes connections which were established after the
+// winner.
+func closeLosers(results <-chan dialResult, pending int) {
+	for range pending {
+		if res := <-results; res.err == nil {
+			res.conn.Close() //nolint: errcheck
+		}
+	}
+}
+
+// interleave alternates IP families keeping an order of addresses within
+// a family. A family of the first address goes first.
+func interleave(addrs []Addr) []Addr {
+	if len(addrs) == 0 {
+		return addrs
+	}
+
+	first := []Addr{}
+	second := []Addr{}
+
+	for _, addr := range addrs {
+		if addr.Network == addrs[0].Network {
+			first = append(first, addr)
+		} else {
+			second = append(second, addr)
+		}
+	}
+
+	rv := make([]Addr, 0, len(addrs))
+
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			rv = append(rv, first[i])
+		}
+
+		if i < len(second) {
+			rv = append(rv, second[i])
+		}
+	}
+
+	return rv
+}
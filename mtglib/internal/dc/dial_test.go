@@ -0,0 +1,119 @@
+package dc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DialTestSuite struct {
+	suite.Suite
+
+	dialer dialerMock
+}
+
+func (s *DialTestSuite) SetupSuite() {
+	s.dialer = dialerMock{
+		delays: map[string]time.Duration{
+			"127.0.0.1:1": time.Hour,
+			"127.0.0.1:2": 0,
+			"[::1]:1":     time.Hour,
+			"[::1]:2":     0,
+		},
+	}
+}
+
+func (s *DialTestSuite) TestNoAddresses() {
+	_, _, err := Dial(context.Background(), s.dialer, nil, time.Millisecond)
+	s.ErrorIs(err, ErrNoAddresses)
+}
+
+func (s *DialTestSuite) TestFirstWins() {
+	conn, addr, err := Dial(context.Background(), s.dialer, []Addr{
+		{Network: "tcp4", Address: "127.0.0.1:2"},
+		{Network: "tcp4", Address: "127.0.0.1:1"},
+	}, time.Hour)
+	s.Require().NoError(err)
+
+	defer conn.Close() //nolint: errcheck
+
+	s.Equal("127.0.0.1:2", addr.Address)
+}
+
+func (s *DialTestSuite) TestStaggeredStart() {
+	started := time.Now()
+
+	conn, addr, err := Dial(context.Background(), s.dialer, []Addr{
+		{Network: "tcp6", Address: "[::1]:1"},
+		{Network: "tcp6", Address: "[::1]:3"},
+		{Network: "tcp4", Address: "127.0.0.1:2"},
+	}, 50*time.Millisecond)
+	s.Require().NoError(err)
+
+	defer conn.Close() //nolint: errcheck
+
+	s.Equal("127.0.0.1:2", addr.Address)
+	s.Less(time.Since(started), time.Second)
+}
+
+func (s *DialTestSuite) TestFailureStartsNextAttempt() {
+	conn, addr, err := Dial(context.Background(), s.dialer, []Addr{
+		{Network: "tcp4", Address: "127.0.0.1:3"},
+		{Network: "tcp4", Address: "127.0.0.1:2"},
+	}, time.Hour)
+	s.Require().NoError(err)
+
+	defer conn.Close() //nolint: errcheck
+
+	s.Equal("127.0.0.1:2", addr.Address)
+}
+
+func (s *DialTestSuite) TestAllFailed() {
+	_, _, err := Dial(context.Background(), s.dialer, []Addr{
+		{Network: "tcp4", Address: "127.0.0.1:3"},
+		{Network: "tcp6", Address: "[::1]:3"},
+	}, time.Hour)
+	s.ErrorContains(err, "127.0.0.1:3")
+	s.ErrorContains(err, "[::1]:3")
+}
+
+func (s *DialTestSuite) TestCancelled() {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := Dial(ctx, s.dialer, []Addr{
+		{Network: "tcp4", Address: "127.0.0.1:1"},
+		{Network: "tcp6", Address: "[::1]:1"},
+	}, time.Millisecond)
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (s *DialTestSuite) TestInterleave() {
+	addrs := interleave([]Addr{
+		{Network: "tcp6", Address: "[::1]:1"},
+		{Network: "tcp6", Address: "[::1]:2"},
+		{Network: "tcp6", Address: "[::1]:3"},
+		{Network: "tcp4", Address: "127.0.0.1:1"},
+		{Network: "tcp4", Address: "127.0.0.1:2"},
+	})
+
+	collected := make([]string, 0, len(addrs))
+	for _, v := range addrs {
+		collected = append(collected, v.Address)
+	}
+
+	s.Equal([]string{
+		"[::1]:1",
+		"127.0.0.1:1",
+		"[::1]:2",
+		"127.0.0.1:2",
+		"[::1]:3",
+	}, collected)
+}
+
+func TestDial(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &DialTestSuite{})
+}
@@ -2,6 +2,8 @@ package dc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net"
 	"time"
 
@@ -30,9 +32,61 @@ const (
 	// How often should we extract hosts from Telegram using help.getConfig
 	// method.
 	OwnConfigUpdateEach = time.Hour
+
+	// How often should we request a secret for middle proxies from
+	// https://core.telegram.org/getProxySecret
+	ProxySecretUpdateEach = time.Hour
+	ProxySecretUpdateURL  = "https://core.telegram.org/getProxySecret"
+
+	// Telegram does not document a size of the proxy secret but middle
+	// proxies reject secrets shorter than that.
+	ProxySecretMinLength = 32
+
+	// Regular DCs have ids below CDNDCMin, CDN DCs start from it. Test
+	// DCs are TestDCOffset + an id of a regular or CDN DC. Negative ids
+	// are media DCs.
+	CDNDCMin     = 200
+	TestDCOffset = 10000
+
+	// A time to wait for a probe of a single address. A probe is a TCP
+	// connect only, nothing is sent.
+	ProbeTimeout = 10 * time.Second
+
+	// A weight of the latest probe in moving averages of RTT and failure
+	// rate.
+	ProbeSmoothing = 0.3
+
+	// Addresses which fail more often are tried after all others.
+	ProbeUnhealthyFailureRate = 0.5
+
+	// Healthy addresses whose RTT differs less than that are considered
+	// equally fast.
+	ProbeRTTGranularity = 10 * time.Millisecond
+
+	// A delay between connection attempts to different addresses of
+	// the same DC. RFC 8305 recommends 250ms.
+	ConnectionAttemptDelay = 250 * time.Millisecond
+
+	// How often should we refill a pool of pre-dialed connections and
+	// recalculate its size.
+	PoolRefillEach = time.Second
+
+	// A weight of the latest refill period in a moving average of demand.
+	PoolSmoothing = 0.3
+
+	// DCs with lower demand are not pooled anymore.
+	PoolMinDemand = 0.01
+
+	// A time to wait for a read from a pooled connection before it is
+	// given away. Closed connections return EOF immediately, alive ones
+	// time out.
+	PoolLivenessTimeout = time.Millisecond
 )
 
+var ErrNoAddresses = errors.New("no addresses to dial")
+
 type Logger interface {
+	Debug(msg string)
 	Info(msg string)
 	WarningError(msg string, err error)
 }
@@ -43,28 +97,44 @@ type Updater interface {
 
 // https://github.com/telegramdesktop/tdesktop/blob/master/Telegram/SourceFiles/mtproto/mtproto_dc_options.cpp#L30
 var defaultDCAddrSet = (func() dcAddrSet {
+	addrSet, err := newDCAddrSet(essentials.TelegramCoreAddresses)
+	if err != nil {
+		panic(err)
+	}
+
+	return addrSet
+})()
+
+// newDCAddrSet classifies addresses by IP family. A hostname is put into
+// both families: it is resolved by a dialer, so tcp4 and tcp6 dials get
+// A and AAAA records respectively.
+func newDCAddrSet(addresses map[int][]string) (dcAddrSet, error) {
 	addrSet := dcAddrSet{
 		v4: make(map[int][]Addr),
 		v6: make(map[int][]Addr),
 	}
 
-	for dcid, ips := range essentials.TelegramCoreAddresses {
-		for _, addr := range ips {
+	for dcid, hosts := range addresses {
+		for _, addr := range hosts {
 			host, _, err := net.SplitHostPort(addr)
 			if err != nil {
-				panic(err)
+				return addrSet, fmt.Errorf("incorrect address %s of DC %d: %w", addr, dcid, err)
 			}
 
-			ip := net.ParseIP(host)
-			if ip == nil {
-				panic(addr)
+			if host == "" {
+				return addrSet, fmt.Errorf("address %s of DC %d has no host", addr, dcid)
 			}
-			if ip.To4() == nil {
+
+			ip := net.ParseIP(host)
+
+			if ip == nil || ip.To4() == nil {
 				addrSet.v6[dcid] = append(addrSet.v6[dcid], Addr{
 					Network: "tcp6",
 					Address: addr,
 				})
-			} else {
+			}
+
+			if ip == nil || ip.To4() != nil {
 				addrSet.v4[dcid] = append(addrSet.v4[dcid], Addr{
 					Network: "tcp4",
 					Address: addr,
@@ -73,5 +143,5 @@ var defaultDCAddrSet = (func() dcAddrSet {
 		}
 	}
 
-	return addrSet
-})()
+	return addrSet, nil
+}
@@ -2,6 +2,11 @@ package dc
 
 import (
 	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/9seconds/mtg/v2/essentials"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -11,6 +16,10 @@ type LoggerMock struct {
 	mock.Mock
 }
 
+func (m *LoggerMock) Debug(msg string) {
+	m.Called(msg)
+}
+
 func (m *LoggerMock) Info(msg string) {
 	m.Called(msg)
 }
@@ -19,6 +28,35 @@ func (m *LoggerMock) WarningError(msg string, err error) {
 	m.Called(msg, err)
 }
 
+// dialerMock dials addresses with given delays. Other addresses are
+// unreachable. Connections to closed addresses are closed by a remote
+// side right after dial.
+type dialerMock struct {
+	delays map[string]time.Duration
+	closed map[string]bool
+}
+
+func (d dialerMock) DialContext(ctx context.Context, _, address string) (essentials.Conn, error) {
+	delay, ok := d.delays[address]
+	if !ok {
+		return nil, errors.New("unreachable")
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err() //nolint: wrapcheck
+	case <-time.After(delay):
+	}
+
+	conn, remote := net.Pipe()
+
+	if d.closed[address] {
+		remote.Close() //nolint: errcheck
+	}
+
+	return essentials.WrapNetConn(conn), nil
+}
+
 type UpdaterTestSuiteBase struct {
 	suite.Suite
 
@@ -32,6 +70,7 @@ func (s *UpdaterTestSuiteBase) SetupTest() {
 
 	s.loggerMock = &LoggerMock{}
 	s.loggerMock.On("Info", mock.AnythingOfType("string"))
+	s.loggerMock.On("Debug", mock.AnythingOfType("string"))
 	s.loggerMock.On("WarningError", mock.AnythingOfType("string"), mock.Anything)
 
 	s.ctx = ctx
@@ -0,0 +1,255 @@
+package dc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/9seconds/mtg/v2/essentials"
+)
+
+type pooledConn struct {
+	conn     essentials.Conn
+	addr     Addr
+	dialedAt time.Time
+}
+
+// alive checks if Telegram has not closed a connection. Telegram sends
+// nothing before a handshake, so a read has to time out. EOF, reset or
+// any data mean that connection is unusable.
+func (p pooledConn) alive() bool {
+	if err := p.conn.SetReadDeadline(time.Now().Add(PoolLivenessTimeout)); err != nil {
+		return false
+	}
+
+	_, err := p.conn.Read(make([]byte, 1))
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		return false
+	}
+
+	return p.conn.SetReadDeadline(time.Time{}) == nil
+}
+
+type poolDemand struct {
+	requests int
+	average  float64
+}
+
+// Pool keeps pre-dialed TCP connections to DCs. Nothing is sent to these
+// connections so they can be used for any handshake.
+//
+// A size of the pool for each DC follows a demand: it is a moving
+// average of requests for connections to this DC within a refill period.
+// DCs which are not requested are not pooled.
+type Pool struct {
+	wg     sync.WaitGroup
+	mutex  sync.Mutex
+	conns  map[int][]pooledConn
+	demand map[int]*poolDemand
+
+	maxSize      int
+	maxIdle      time.Duration
+	dialer       Dialer
+	getAddresses func(int) []Addr
+	logger       Logger
+}
+
+// Get returns a connection to a given DC if pool has any. Connections
+// which are idle for too long or closed by Telegram are dropped.
+func (p *Pool) Get(dc int) (essentials.Conn, Addr, bool) {
+	p.mutex.Lock()
+
+	demand, ok := p.demand[dc]
+	if !ok {
+		demand = &poolDemand{}
+		p.demand[dc] = demand
+	}
+
+	demand.requests++
+
+	p.mutex.Unlock()
+
+	for {
+		pooled, ok := p.take(dc)
+		if !ok {
+			return nil, Addr{}, false
+		}
+
+		if pooled.alive() {
+			return pooled.conn, pooled.addr, true
+		}
+
+		pooled.conn.Close() //nolint: errcheck
+	}
+}
+
+// take pops the freshest connection which is not idle for too long.
+func (p *Pool) take(dc int) (pooledConn, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	conns := p.conns[dc]
+	defer func() {
+		p.conns[dc] = conns
+	}()
+
+	for len(conns) > 0 {
+		// the freshest connection has the best chance to be alive.
+		pooled := conns[len(conns)-1]
+		conns = conns[:len(conns)-1]
+
+		if time.Since(pooled.dialedAt) < p.maxIdle {
+			return pooled, true
+		}
+
+		pooled.conn.Close() //nolint: errcheck
+	}
+
+	return pooledConn{}, false
+}
+
+// Run starts refilling the pool. All pooled connections are closed when
+// the context is done.
+func (p *Pool) Run(ctx context.Context) {
+	p.wg.Go(func() {
+		ticker := time.NewTicker(PoolRefillEach)
+
+		defer func() {
+			ticker.Stop()
+			p.closeAll()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.refill(ctx)
+			}
+		}
+	})
+}
+
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Pool) refill(ctx context.Context) {
+	wg := &sync.WaitGroup{}
+
+	for dc, missing := range p.expire() {
+		addrs := p.getAddresses(dc)
+		if len(addrs) == 0 {
+			continue
+		}
+
+		for range missing {
+			wg.Go(func() {
+				conn, addr, err := Dial(ctx, p.dialer, addrs, ConnectionAttemptDelay)
+				if err != nil {
+					if ctx.Err() == nil {
+						p.logger.WarningError("cannot dial a connection for the pool", err)
+					}
+
+					return
+				}
+
+				p.put(dc, pooledConn{
+					conn:     conn,
+					addr:     addr,
+					dialedAt: time.Now(),
+				})
+			})
+		}
+	}
+
+	wg.Wait()
+}
+
+// expire updates a demand, drops stale connections and returns how many
+// connections are missing for each DC.
+func (p *Pool) expire() map[int]int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	missing := map[int]int{}
+
+	for dc, demand := range p.demand {
+		demand.average = PoolSmoothing*float64(demand.requests) + (1-PoolSmoothing)*demand.average
+		demand.requests = 0
+
+		alive := p.conns[dc][:0]
+
+		for _, pooled := range p.conns[dc] {
+			if time.Since(pooled.dialedAt) < p.maxIdle {
+				alive = append(alive, pooled)
+			} else {
+				pooled.conn.Close() //nolint: errcheck
+			}
+		}
+
+		p.conns[dc] = alive
+
+		if demand.average < PoolMinDemand && len(alive) == 0 {
+			delete(p.demand, dc)
+			delete(p.conns, dc)
+
+			continue
+		}
+
+		size := min(p.maxSize, int(math.Ceil(demand.average)))
+		if size > len(alive) {
+			missing[dc] = size - len(alive)
+		}
+	}
+
+	return missing
+}
+
+func (p *Pool) put(dc int, pooled pooledConn) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, ok := p.demand[dc]; !ok || len(p.conns[dc]) >= p.maxSize {
+		pooled.conn.Close() //nolint: errcheck
+		return
+	}
+
+	p.conns[dc] = append(p.conns[dc], pooled)
+}
+
+func (p *Pool) closeAll() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for dc, conns := range p.conns {
+		for _, pooled := range conns {
+			pooled.conn.Close() //nolint: errcheck
+		}
+
+		delete(p.conns, dc)
+	}
+}
+
+// NewPool creates a new pool of at most maxSize connections for each DC.
+// Connections which are idle longer than maxIdle are dropped.
+// getAddresses defines addresses of DCs to dial.
+func NewPool(logger Logger,
+	dialer Dialer,
+	getAddresses func(int) []Addr,
+	maxSize int,
+	maxIdle time.Duration,
+) *Pool {
+	return &Pool{
+		conns:        map[int][]pooledConn{},
+		demand:       map[int]*poolDemand{},
+		maxSize:      maxSize,
+		maxIdle:      maxIdle,
+		dialer:       dialer,
+		getAddresses: getAddresses,
+		logger:       logger,
+	}
+}
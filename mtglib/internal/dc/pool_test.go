@@ -0,0 +1,145 @@
+package dc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PoolTestSuite struct {
+	UpdaterTestSuiteBase
+
+	pool *Pool
+}
+
+func (s *PoolTestSuite) SetupTest() {
+	s.UpdaterTestSuiteBase.SetupTest()
+
+	dialer := dialerMock{
+		delays: map[string]time.Duration{
+			"127.0.0.1:1": 0,
+		},
+	}
+	getAddresses := func(dc int) []Addr {
+		switch dc {
+		case 2:
+			return []Addr{{Network: "tcp4", Address: "127.0.0.1:1"}}
+		case 4:
+			return []Addr{{Network: "tcp4", Address: "127.0.0.1:2"}}
+		}
+
+		return nil
+	}
+
+	s.pool = NewPool(s.loggerMock, dialer, getAddresses, 3, time.Minute)
+}
+
+func (s *PoolTestSuite) TearDownTest() {
+	s.UpdaterTestSuiteBase.TearDownTest()
+	s.pool.Wait()
+	s.pool.closeAll()
+}
+
+func (s *PoolTestSuite) TestMissThenHit() {
+	_, _, ok := s.pool.Get(2)
+	s.False(ok)
+
+	s.pool.refill(s.ctx)
+
+	conn, addr, ok := s.pool.Get(2)
+	s.Require().True(ok)
+
+	defer conn.Close() //nolint: errcheck
+
+	s.Equal("127.0.0.1:1", addr.Address)
+
+	_, _, ok = s.pool.Get(2)
+	s.False(ok)
+}
+
+func (s *PoolTestSuite) TestSizeFollowsDemand() {
+	for range 2 {
+		s.pool.Get(2)
+	}
+
+	s.pool.refill(s.ctx)
+	s.Len(s.pool.conns[2], 1)
+
+	for range 100 {
+		s.pool.Get(2)
+	}
+
+	s.pool.refill(s.ctx)
+	s.Len(s.pool.conns[2], 3)
+}
+
+func (s *PoolTestSuite) TestUnknownDC() {
+	s.pool.Get(3)
+	s.pool.refill(s.ctx)
+
+	s.Empty(s.pool.conns[3])
+}
+
+func (s *PoolTestSuite) TestDemandDecays() {
+	s.pool.Get(4)
+
+	for range 20 {
+		s.pool.refill(s.ctx)
+	}
+
+	s.NotContains(s.pool.demand, 4)
+}
+
+func (s *PoolTestSuite) TestExpire() {
+	s.pool.maxIdle = 10 * time.Millisecond
+
+	s.pool.Get(2)
+	s.pool.refill(s.ctx)
+	s.Len(s.pool.conns[2], 1)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, _, ok := s.pool.Get(2)
+	s.False(ok)
+}
+
+func (s *PoolTestSuite) TestDropClosed() {
+	s.pool.dialer = dialerMock{
+		delays: map[string]time.Duration{"127.0.0.1:1": 0},
+		closed: map[string]bool{"127.0.0.1:1": true},
+	}
+
+	for range 10 {
+		s.pool.Get(2)
+	}
+
+	s.pool.refill(s.ctx)
+	s.Len(s.pool.conns[2], 3)
+
+	_, _, ok := s.pool.Get(2)
+	s.False(ok)
+	s.Empty(s.pool.conns[2])
+}
+
+func (s *PoolTestSuite) TestRun() {
+	s.pool.Get(2)
+	s.pool.Run(s.ctx)
+
+	s.Eventually(func() bool {
+		s.pool.mutex.Lock()
+		defer s.pool.mutex.Unlock()
+
+		return len(s.pool.conns[2]) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s.ctxCancel()
+	s.pool.Wait()
+
+	s.Empty(s.pool.conns)
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &PoolTestSuite{})
+}
@@ -0,0 +1,172 @@
+package dc
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/9seconds/mtg/v2/essentials"
+)
+
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (essentials.Conn, error)
+}
+
+// ProbeResult is a health of the address after a probe.
+type ProbeResult struct {
+	DC      int
+	Address string
+
+	// RTT is a smoothed time of establishing TCP connection. It is zero
+	// if address has never been reached.
+	RTT time.Duration
+
+	// FailureRate is a smoothed rate of failed probes, from 0 to 1.
+	FailureRate float64
+
+	// Forgotten is set if the address does not belong to the DC anymore
+	// and is not probed. RTT and FailureRate are zero then.
+	Forgotten bool
+}
+
+type addrHealth struct {
+	rtt         time.Duration
+	failureRate float64
+}
+
+type probeSample struct {
+	rtt    time.Duration
+	failed bool
+}
+
+func (a addrHealth) healthy() bool {
+	return a.failureRate < ProbeUnhealthyFailureRate
+}
+
+// update applies exponential moving average to a previous state. The
+// first probe is taken as is.
+func (a addrHealth) update(sample probeSample, known bool) addrHealth {
+	failure := 0.0
+	if sample.failed {
+		failure = 1.0
+	}
+
+	if !known {
+		a.failureRate = failure
+	} else {
+		a.failureRate = ProbeSmoothing*failure + (1-ProbeSmoothing)*a.failureRate
+	}
+
+	switch {
+	case sample.failed:
+	case a.rtt == 0:
+		a.rtt = sample.rtt
+	default:
+		a.rtt = time.Duration(ProbeSmoothing*float64(sample.rtt) + (1-ProbeSmoothing)*float64(a.rtt))
+	}
+
+	return a
+}
+
+// Prober periodically dials every known Telegram address and collects
+// connection time and failure rate. This data is used by Telegram to
+// sort addresses so blackholed ones are tried last.
+type Prober struct {
+	updater
+
+	tg     *Telegram
+	dialer Dialer
+
+	// previous targets, only a goroutine of Run touches them.
+	previous []dcAddress
+}
+
+// Run starts probing. callback is called for each DC address after each
+// round, and once more for each address which was forgotten since a
+// previous round.
+func (p *Prober) Run(ctx context.Context, callback func(ProbeResult)) {
+	p.run(ctx, func() error {
+		targets := p.tg.probeTargets()
+		samples := make(map[string]probeSample, len(targets.networks))
+		mutex := &sync.Mutex{}
+		wg := &sync.WaitGroup{}
+
+		for address, network := range targets.networks {
+			wg.Go(func() {
+				sample := p.probe(ctx, network, address)
+
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				samples[address] = sample
+			})
+		}
+
+		wg.Wait()
+
+		// samples of interrupted dials say nothing about addresses.
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		results := p.tg.applySamples(samples)
+
+		for _, dcAddr := range targets.dcs {
+			health := results[dcAddr.address]
+
+			callback(ProbeResult{
+				DC:          dcAddr.dc,
+				Address:     dcAddr.address,
+				RTT:         health.rtt,
+				FailureRate: health.failureRate,
+			})
+		}
+
+		for _, dcAddr := range p.previous {
+			if !slices.Contains(targets.dcs, dcAddr) {
+				callback(ProbeResult{
+					DC:        dcAddr.dc,
+					Address:   dcAddr.address,
+					Forgotten: true,
+				})
+			}
+		}
+
+		p.previous = targets.dcs
+
+		return nil
+	})
+}
+
+func (p *Prober) probe(ctx context.Context, network, address string) probeSample {
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+
+	started := time.Now()
+
+	conn, err := p.dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return probeSample{failed: true}
+	}
+
+	rtt := time.Since(started)
+
+	conn.Close() //nolint: errcheck
+
+	return probeSample{rtt: rtt}
+}
+
+// NewProber creates a new Prober which probes addresses each period.
+func NewProber(tg *Telegram, logger Logger, dialer Dialer, period time.Duration) *Prober {
+	return &Prober{
+		updater: updater{
+			logger: logger,
+			period: period,
+		},
+		tg:     tg,
+		dialer: dialer,
+	}
+}
@@ -0,0 +1,162 @@
+package dc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ProberTestSuite struct {
+	UpdaterTestSuiteBase
+
+	tg *Telegram
+	p  *Prober
+}
+
+func (s *ProberTestSuite) SetupTest() {
+	s.UpdaterTestSuiteBase.SetupTest()
+
+	tg, err := New("only-ipv4", false, map[int][]string{
+		2: {"127.0.0.1:1", "127.0.0.1:2", "127.0.0.1:3"},
+	}, true)
+	s.Require().NoError(err)
+
+	s.tg = tg
+	s.p = NewProber(tg, s.loggerMock, dialerMock{
+		delays: map[string]time.Duration{
+			"127.0.0.1:2": 100 * time.Millisecond,
+			"127.0.0.1:3": 0,
+		},
+	}, 50*time.Millisecond)
+}
+
+func (s *ProberTestSuite) TearDownTest() {
+	s.UpdaterTestSuiteBase.TearDownTest()
+	s.p.Wait()
+}
+
+func (s *ProberTestSuite) TestOrderByHealth() {
+	s.Equal(3, len(s.tg.GetAddresses(2)))
+
+	lock := &sync.Mutex{}
+	results := map[string]ProbeResult{}
+
+	s.p.Run(s.ctx, func(result ProbeResult) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if result.DC == 2 {
+			results[result.Address] = result
+		}
+	})
+
+	s.Eventually(func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return len(results) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	s.Equal(1.0, results["127.0.0.1:1"].FailureRate)
+	s.Zero(results["127.0.0.1:1"].RTT)
+	s.Zero(results["127.0.0.1:2"].FailureRate)
+	s.GreaterOrEqual(results["127.0.0.1:2"].RTT, 100*time.Millisecond)
+	s.Zero(results["127.0.0.1:3"].FailureRate)
+
+	for range 10 {
+		addrs := s.tg.GetAddresses(2)
+
+		s.Len(addrs, 3)
+		s.Equal("127.0.0.1:3", addrs[0].Address)
+		s.Equal("127.0.0.1:2", addrs[1].Address)
+		s.Equal("127.0.0.1:1", addrs[2].Address)
+	}
+}
+
+func (s *ProberTestSuite) TestForgotten() {
+	lock := &sync.Mutex{}
+	forgotten := map[string]bool{}
+	rounds := 0
+
+	s.p.Run(s.ctx, func(result ProbeResult) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if result.Forgotten {
+			forgotten[result.Address] = true
+		}
+
+		if result.Address == "127.0.0.1:1" && !result.Forgotten {
+			rounds++
+		}
+	})
+
+	s.Eventually(func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return rounds > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s.tg.lock.Lock()
+	s.tg.view.overrides.v4[2] = s.tg.view.overrides.v4[2][1:]
+	s.tg.lock.Unlock()
+
+	s.Eventually(func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return forgotten["127.0.0.1:1"]
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	s.Len(forgotten, 1)
+}
+
+func (s *ProberTestSuite) TestUnknownAddressesBeforeFailing() {
+	s.tg.applySamples(map[string]probeSample{
+		"127.0.0.1:1": {failed: true},
+		"127.0.0.1:3": {rtt: time.Millisecond},
+	})
+
+	addrs := s.tg.GetAddresses(2)
+
+	s.Len(addrs, 3)
+	s.Equal("127.0.0.1:3", addrs[0].Address)
+	s.Equal("127.0.0.1:2", addrs[1].Address)
+	s.Equal("127.0.0.1:1", addrs[2].Address)
+}
+
+func (s *ProberTestSuite) TestSmoothing() {
+	health := addrHealth{}.update(probeSample{rtt: 100 * time.Millisecond}, false)
+
+	s.Equal(100*time.Millisecond, health.rtt)
+	s.Zero(health.failureRate)
+	s.True(health.healthy())
+
+	health = health.update(probeSample{failed: true}, true)
+
+	s.Equal(100*time.Millisecond, health.rtt)
+	s.InDelta(ProbeSmoothing, health.failureRate, 0.001)
+	s.True(health.healthy())
+
+	health = health.update(probeSample{failed: true}, true)
+
+	s.False(health.healthy())
+
+	health = health.update(probeSample{rtt: 200 * time.Millisecond}, true)
+
+	s.Equal(130*time.Millisecond, health.rtt)
+}
+
+func TestProber(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &ProberTestSuite{})
+}
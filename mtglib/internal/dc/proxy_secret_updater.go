@@ -0,0 +1,66 @@
+package dc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type ProxySecretUpdater struct {
+	updater
+
+	http *http.Client
+	tg   *Telegram
+}
+
+func (p *ProxySecretUpdater) Run(ctx context.Context, url string) {
+	p.run(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			panic(err)
+		}
+
+		resp, err := p.http.Do(req)
+		if err != nil {
+			if resp != nil {
+				io.Copy(io.Discard, resp.Body) //nolint: errcheck
+				resp.Body.Close()              //nolint: errcheck
+			}
+			return fmt.Errorf("cannot fetch url %s: %w", url, err)
+		}
+
+		defer resp.Body.Close() //nolint: errcheck
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("unexpected status code from %s: %d", url, resp.StatusCode)
+		}
+
+		secret, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("cannot read response body from %s: %w", url, err)
+		}
+
+		if len(secret) < ProxySecretMinLength {
+			return fmt.Errorf("proxy secret from %s is too short: %d bytes", url, len(secret))
+		}
+
+		p.tg.lock.Lock()
+		defer p.tg.lock.Unlock()
+
+		p.tg.proxySecret = secret
+
+		return nil
+	})
+}
+
+func NewProxySecretUpdater(tg *Telegram, logger Logger, client *http.Client) *ProxySecretUpdater {
+	return &ProxySecretUpdater{
+		updater: updater{
+			logger: logger,
+			period: ProxySecretUpdateEach,
+		},
+		http: client,
+		tg:   tg,
+	}
+}
@@ -0,0 +1,91 @@
+package dc
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProxySecretUpdaterTestSuite struct {
+	UpdaterTestSuiteBase
+
+	u               *ProxySecretUpdater
+	lock            sync.Mutex
+	srv             *httptest.Server
+	responseHandler func(w http.ResponseWriter)
+}
+
+func (s *ProxySecretUpdaterTestSuite) SetupSuite() {
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		s.responseHandler(w)
+		s.lock.Unlock()
+	}))
+}
+
+func (s *ProxySecretUpdaterTestSuite) TearDownSuite() {
+	s.srv.Close()
+}
+
+func (s *ProxySecretUpdaterTestSuite) SetupTest() {
+	s.UpdaterTestSuiteBase.SetupTest()
+
+	tg, err := New("prefer-ipv4", false, nil, false)
+	require.NoError(s.T(), err)
+
+	s.u = NewProxySecretUpdater(tg, s.loggerMock, s.srv.Client())
+}
+
+func (s *ProxySecretUpdaterTestSuite) Test502StatusCode() {
+	s.responseHandler = func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write(bytes.Repeat([]byte{1}, 128)) //nolint: errcheck
+	}
+	s.u.Run(s.ctx, s.srv.URL)
+
+	time.Sleep(100 * time.Millisecond)
+	s.ctxCancel()
+	s.u.Wait()
+
+	s.Nil(s.u.tg.GetProxySecret())
+}
+
+func (s *ProxySecretUpdaterTestSuite) TestTooShort() {
+	s.responseHandler = func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte{1, 2, 3}) //nolint: errcheck
+	}
+	s.u.Run(s.ctx, s.srv.URL)
+
+	time.Sleep(100 * time.Millisecond)
+	s.ctxCancel()
+	s.u.Wait()
+
+	s.Nil(s.u.tg.GetProxySecret())
+}
+
+func (s *ProxySecretUpdaterTestSuite) TestOk() {
+	secret := bytes.Repeat([]byte{1, 2, 3, 4}, 32)
+
+	s.responseHandler = func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(secret) //nolint: errcheck
+	}
+	s.u.Run(s.ctx, s.srv.URL)
+
+	time.Sleep(100 * time.Millisecond)
+	s.ctxCancel()
+	s.u.Wait()
+
+	s.Equal(secret, s.u.tg.GetProxySecret())
+}
+
+func TestProxySecretUpdater(t *testing.T) {
+	suite.Run(t, &ProxySecretUpdaterTestSuite{})
+}
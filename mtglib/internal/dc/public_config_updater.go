@@ -5,12 +5,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"regexp"
 	"strconv"
 )
 
-var publicConfigRe = regexp.MustCompile(`^\s*proxy_for\s+(\d+)\s+(\S+?)?;\s*$`)
+var publicConfigRe = regexp.MustCompile(`^\s*proxy_for\s+(-?\d+)\s+(\S+?)?;\s*$`)
 
 type PublicConfigUpdater struct {
 	updater
@@ -41,6 +42,7 @@ func (p *PublicConfigUpdater) Run(ctx context.Context, url, network string) {
 
 		scanner := bufio.NewScanner(resp.Body)
 		addrs := map[int][]Addr{}
+		found := 0
 
 		for scanner.Scan() {
 			matches := publicConfigRe.FindStringSubmatch(scanner.Text())
@@ -49,25 +51,30 @@ func (p *PublicConfigUpdater) Run(ctx context.Context, url, network string) {
 			}
 
 			dc, err := strconv.Atoi(matches[1])
-			if err != nil {
+			if err != nil || dc == 0 {
 				continue
 			}
 
-			switch dc {
-			// this is a list of DC we currently support. Other are ignored.
-			case 203: // CDN DC
-				p.logger.Info(fmt.Sprintf("found %s address for DC %d", matches[2], dc))
-				addrs[dc] = append(addrs[dc], Addr{
-					Network: network,
-					Address: matches[2],
-				})
+			if _, port, err := net.SplitHostPort(matches[2]); err != nil || port == "" {
+				continue
 			}
+
+			// Negative DCs are media DCs, DCs > 10000 are test ones. All
+			// of them are taken as is. Addresses of regular DCs are
+			// middle proxies, CDN DCs are announced with own addresses.
+			found++
+			addrs[dc] = append(addrs[dc], Addr{
+				Network: network,
+				Address: matches[2],
+			})
 		}
 
 		if err := scanner.Err(); err != nil {
 			return fmt.Errorf("cannot read response body from %s: %w", url, err)
 		}
 
+		p.logger.Debug(fmt.Sprintf("found %d addresses of %d DCs in %s", found, len(addrs), url))
+
 		p.tg.lock.Lock()
 		defer p.tg.lock.Unlock()
 
@@ -35,7 +35,7 @@ func (s *PublicConfigUpdaterTestSuite) TearDownSuite() {
 func (s *PublicConfigUpdaterTestSuite) SetupTest() {
 	s.UpdaterTestSuiteBase.SetupTest()
 
-	tg, err := New("prefer-ipv4")
+	tg, err := New("prefer-ipv4", false, nil, false)
 	require.NoError(s.T(), err)
 
 	s.u = NewPublicConfigUpdater(tg, s.loggerMock, s.srv.Client())
@@ -70,7 +70,8 @@ func (s *PublicConfigUpdaterTestSuite) TestEmptyFile() {
 func (s *PublicConfigUpdaterTestSuite) TestGarbage() {
 	result := `
 proxy_for -1 -1;
-proxy_for 100 100.10.0.0:3333;
+proxy_for 100 100.10.0.0;
+proxy_for 0 100.10.0.0:3333;
 lala 0 0
 `
 
@@ -103,9 +104,34 @@ proxy_for -100 101.10.0.0:3333;
 	s.ctxCancel()
 	s.u.Wait()
 
-	s.Len(s.u.tg.view.publicConfigs.v4, 1)
+	s.Len(s.u.tg.view.publicConfigs.v4, 2)
 	s.Len(s.u.tg.view.publicConfigs.v4[203], 1)
 	s.Equal("100.10.0.0:3333", s.u.tg.view.publicConfigs.v4[203][0].Address)
+	s.Len(s.u.tg.view.publicConfigs.v4[-100], 1)
+	s.Equal("101.10.0.0:3333", s.u.tg.view.publicConfigs.v4[-100][0].Address)
+}
+
+func (s *PublicConfigUpdaterTestSuite) TestMiddleProxies() {
+	result := `
+proxy_for 1 149.154.175.50:8888;
+proxy_for 1 149.154.175.51:8888;
+proxy_for 2 149.154.161.144:8888;
+`
+
+	s.responseHandler = func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(result)) //nolint: errcheck
+	}
+	s.u.Run(s.ctx, s.srv.URL, "tcp4")
+
+	time.Sleep(100 * time.Millisecond)
+	s.ctxCancel()
+	s.u.Wait()
+
+	s.Len(s.u.tg.GetMiddleProxyAddresses(1), 2)
+	s.Equal([]Addr{{Network: "tcp4", Address: "149.154.161.144:8888"}}, s.u.tg.GetMiddleProxyAddresses(2))
+	s.Empty(s.u.tg.GetMiddleProxyAddresses(3))
+	s.Len(s.u.tg.GetMiddleProxyAddresses(-1), 2)
 }
 
 func TestPublicConfigUpdater(t *testing.T) {
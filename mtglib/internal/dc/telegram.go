@@ -1,34 +1,192 @@
 package dc
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 )
 
 type Telegram struct {
-	lock     sync.RWMutex
-	view     dcView
-	preferIP preferIP
+	lock        sync.RWMutex
+	view        dcView
+	preferIP    preferIP
+	proxySecret []byte
+	health      map[string]addrHealth
 }
 
+type dcAddress struct {
+	dc      int
+	address string
+}
+
+type probeTargets struct {
+	// address -> network
+	networks map[string]string
+	dcs      []dcAddress
+}
+
+// GetAddresses returns addresses of a given DC. Negative DCs are media
+// ones: if Telegram has not announced any address for them, addresses of
+// a corresponding positive DC are used.
+//
+// Within an IP family, healthy addresses go first, fastest first. Then
+// addresses that were not probed yet and then failing ones.
 func (t *Telegram) GetAddresses(dc int) []Addr {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
 
+	addrs := t.withPreference(t.view.getV4(dc), t.view.getV6(dc))
+	if len(addrs) == 0 && dc < 0 {
+		addrs = t.withPreference(t.view.getV4(-dc), t.view.getV6(-dc))
+	}
+
+	return addrs
+}
+
+// GetMiddleProxyAddresses returns addresses of middle proxies of a given
+// DC. All addresses from a public config are used as middle proxies,
+// GetAddresses returns only addresses of CDN DCs from it.
+func (t *Telegram) GetMiddleProxyAddresses(dc int) []Addr {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	addrs := t.withPreference(t.view.getMiddleProxyV4(dc), t.view.getMiddleProxyV6(dc))
+	if len(addrs) == 0 && dc < 0 {
+		addrs = t.withPreference(t.view.getMiddleProxyV4(-dc), t.view.getMiddleProxyV6(-dc))
+	}
+
+	return addrs
+}
+
+// GetProxySecret returns a secret which is used to talk to middle
+// proxies. It is nil until it is fetched.
+func (t *Telegram) GetProxySecret() []byte {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.proxySecret
+}
+
+func (t *Telegram) withPreference(v4, v6 []Addr) []Addr {
+	t.sortByHealth(v4)
+	t.sortByHealth(v6)
+
 	switch t.preferIP {
 	case preferIPOnlyIPv4:
-		return t.view.getV4(dc)
+		return v4
 	case preferIPOnlyIPv6:
-		return t.view.getV6(dc)
+		return v6
 	case preferIPPreferIPv4:
-		return append(t.view.getV4(dc), t.view.getV6(dc)...)
+		return append(v4, v6...)
 	}
 
-	return append(t.view.getV6(dc), t.view.getV4(dc)...)
+	return append(v6, v4...)
 }
 
-func New(ipPreference string) (*Telegram, error) {
+// sortByHealth puts healthy addresses first, fastest first. Addresses with
+// the same rank keep their original random order, so load is spread
+// between equally fast addresses, and between all addresses if nothing
+// was probed yet.
+func (t *Telegram) sortByHealth(addrs []Addr) {
+	rank := func(addr Addr) (int, int64) {
+		health, ok := t.health[addr.Address]
+
+		switch {
+		case !ok:
+			return 1, 0
+		case health.healthy():
+			return 0, int64(health.rtt / ProbeRTTGranularity)
+		}
+
+		return 2, int64(health.failureRate * 100) //nolint: mnd
+	}
+
+	slices.SortStableFunc(addrs, func(a, b Addr) int {
+		classA, valueA := rank(a)
+		classB, valueB := rank(b)
+
+		return cmp.Or(cmp.Compare(classA, classB), cmp.Compare(valueA, valueB))
+	})
+}
+
+// probeTargets returns addresses of DCs and middle proxies. An address
+// is probed once even if it belongs to many DCs or is both an address of
+// a DC and a middle proxy.
+func (t *Telegram) probeTargets() probeTargets {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	targets := probeTargets{
+		networks: map[string]string{},
+	}
+
+	add := func(sets []dcAddrSet, getV4, getV6 func(int) []Addr) {
+		dcs := map[int]bool{}
+
+		for _, set := range sets {
+			for dc := range set.v4 {
+				dcs[dc] = true
+			}
+
+			for dc := range set.v6 {
+				dcs[dc] = true
+			}
+		}
+
+		for _, dc := range slices.Sorted(maps.Keys(dcs)) {
+			for _, addr := range t.withPreference(getV4(dc), getV6(dc)) {
+				// hostnames are in both IP families, a preferred one is
+				// probed.
+				if _, ok := targets.networks[addr.Address]; !ok {
+					targets.networks[addr.Address] = addr.Network
+				}
+
+				dcAddr := dcAddress{dc: dc, address: addr.Address}
+				if !slices.Contains(targets.dcs, dcAddr) {
+					targets.dcs = append(targets.dcs, dcAddr)
+				}
+			}
+		}
+	}
+
+	add([]dcAddrSet{defaultDCAddrSet, t.view.overrides, t.view.publicConfigs}, t.view.getV4, t.view.getV6)
+	add([]dcAddrSet{t.view.publicConfigs}, t.view.getMiddleProxyV4, t.view.getMiddleProxyV6)
+
+	return targets
+}
+
+// applySamples updates health of probed addresses. Addresses which were
+// not probed are not known anymore and forgotten.
+func (t *Telegram) applySamples(samples map[string]probeSample) map[string]addrHealth {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	health := make(map[string]addrHealth, len(samples))
+
+	for address, sample := range samples {
+		previous, ok := t.health[address]
+		health[address] = previous.update(sample, ok)
+	}
+
+	t.health = health
+
+	return maps.Clone(health)
+}
+
+// New creates a new Telegram instance. If publicConfigsFirst is set,
+// addresses from a public config take precedence over built-in ones.
+//
+// overrides is a mapping of DC to a list of ip:port addresses. They
+// either replace hardcoded addresses of the DC (if replaceOverridden is
+// set) or are tried before them.
+func New(ipPreference string,
+	publicConfigsFirst bool,
+	overrides map[int][]string,
+	replaceOverridden bool,
+) (*Telegram, error) {
 	var pref preferIP
 
 	switch strings.ToLower(ipPreference) {
@@ -44,7 +202,17 @@ func New(ipPreference string) (*Telegram, error) {
 		return nil, fmt.Errorf("unknown ip preference %s", ipPreference)
 	}
 
+	overridesSet, err := newDCAddrSet(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect DC overrides: %w", err)
+	}
+
 	return &Telegram{
 		preferIP: pref,
+		view: dcView{
+			publicConfigsFirst: publicConfigsFirst,
+			overrides:          overridesSet,
+			replaceOverridden:  replaceOverridden,
+		},
 	}, nil
 }
@@ -0,0 +1,117 @@
+package dc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TelegramTestSuite struct {
+	suite.Suite
+
+	tg *Telegram
+}
+
+func (suite *TelegramTestSuite) SetupTest() {
+	tg, err := New("only-ipv4", false, nil, false)
+	suite.Require().NoError(err)
+
+	tg.view.publicConfigs.v4 = map[int][]Addr{
+		-4: {
+			{Network: "tcp4", Address: "149.154.165.111:8888"},
+		},
+		2: {
+			{Network: "tcp4", Address: "149.154.161.144:8888"},
+		},
+		207: {
+			{Network: "tcp4", Address: "91.105.192.107:443"},
+		},
+	}
+
+	suite.tg = tg
+}
+
+func (suite *TelegramTestSuite) TestCDNDCFromPublicConfig() {
+	suite.Equal([]Addr{
+		{Network: "tcp4", Address: "91.105.192.107:443"},
+	}, suite.tg.GetAddresses(207))
+}
+
+func (suite *TelegramTestSuite) TestMediaDC() {
+	suite.ElementsMatch([]Addr{
+		{Network: "tcp4", Address: "149.154.165.111:8888"},
+	}, suite.tg.GetMiddleProxyAddresses(-4))
+}
+
+func (suite *TelegramTestSuite) TestMiddleProxiesAreNotDialedDirectly() {
+	for _, addr := range suite.tg.GetAddresses(2) {
+		suite.NotEqual("149.154.161.144:8888", addr.Address)
+	}
+
+	suite.ElementsMatch(suite.tg.GetAddresses(4), suite.tg.GetAddresses(-4))
+}
+
+func (suite *TelegramTestSuite) TestMediaDCFallback() {
+	suite.ElementsMatch(suite.tg.GetAddresses(2), suite.tg.GetAddresses(-2))
+	suite.Len(suite.tg.GetAddresses(-2), 2)
+}
+
+func (suite *TelegramTestSuite) TestMiddleProxies() {
+	suite.ElementsMatch([]Addr{
+		{Network: "tcp4", Address: "149.154.161.144:8888"},
+	}, suite.tg.GetMiddleProxyAddresses(-2))
+	suite.Empty(suite.tg.GetMiddleProxyAddresses(1))
+}
+
+func (suite *TelegramTestSuite) TestOverridesExtend() {
+	tg, err := New("only-ipv4", false, map[int][]string{
+		2:     {"127.0.0.1:4430"},
+		10002: {"127.0.0.2:4430"},
+	}, false)
+	suite.Require().NoError(err)
+
+	addrs := tg.GetAddresses(2)
+	suite.Len(addrs, 3)
+	suite.Equal("127.0.0.1:4430", addrs[0].Address)
+
+	suite.Equal([]Addr{{Network: "tcp4", Address: "127.0.0.2:4430"}}, tg.GetAddresses(10002))
+}
+
+func (suite *TelegramTestSuite) TestOverridesReplace() {
+	tg, err := New("prefer-ipv4", false, map[int][]string{
+		2: {"127.0.0.1:4430"},
+	}, true)
+	suite.Require().NoError(err)
+
+	suite.Equal([]Addr{{Network: "tcp4", Address: "127.0.0.1:4430"}}, tg.GetAddresses(2))
+	suite.Len(tg.GetAddresses(1), 2)
+}
+
+func (suite *TelegramTestSuite) TestOverridesHostname() {
+	tg, err := New("prefer-ipv4", false, map[int][]string{
+		2: {"localhost:443"},
+	}, true)
+	suite.Require().NoError(err)
+
+	suite.Equal([]Addr{
+		{Network: "tcp4", Address: "localhost:443"},
+		{Network: "tcp6", Address: "localhost:443"},
+	}, tg.GetAddresses(2))
+}
+
+func (suite *TelegramTestSuite) TestIncorrectOverrides() {
+	_, err := New("prefer-ipv4", false, map[int][]string{
+		2: {"localhost"},
+	}, false)
+	suite.Error(err)
+}
+
+func (suite *TelegramTestSuite) TestUnknownPreference() {
+	_, err := New("ipv4", false, nil, false)
+	suite.Error(err)
+}
+
+func TestTelegram(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &TelegramTestSuite{})
+}
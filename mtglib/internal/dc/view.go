@@ -1,23 +1,98 @@
 package dc
 
+// dcView merges addresses of DCs which mtg knows from different sources:
+// hardcoded ones, overrides from a config and ones from a public config.
+//
+// A public config announces middle proxies for regular DCs, their media
+// and test counterparts. Middle proxies speak another protocol, so they
+// are never dialed directly. CDN DCs are announced with their own
+// addresses, these ones are mixed with built-in addresses.
 type dcView struct {
-	publicConfigs dcAddrSet
+	publicConfigs      dcAddrSet
+	publicConfigsFirst bool
+	overrides          dcAddrSet
+	replaceOverridden  bool
 }
 
 func (d dcView) getV4(dc int) []Addr {
-	var addrs []Addr
+	return d.merge(dc,
+		d.builtin(dc, d.overrides.getV4, defaultDCAddrSet.getV4),
+		d.publicConfigs.getV4)
+}
 
-	addrs = append(addrs, defaultDCAddrSet.getV4(dc)...)
-	addrs = append(addrs, d.publicConfigs.getV4(dc)...)
+func (d dcView) getV6(dc int) []Addr {
+	return d.merge(dc,
+		d.builtin(dc, d.overrides.getV6, defaultDCAddrSet.getV6),
+		d.publicConfigs.getV6)
+}
 
-	return addrs
+func (d dcView) getMiddleProxyV4(dc int) []Addr {
+	return d.publicConfigs.getV4(dc)
 }
 
-func (d dcView) getV6(dc int) []Addr {
-	var addrs []Addr
+func (d dcView) getMiddleProxyV6(dc int) []Addr {
+	return d.publicConfigs.getV6(dc)
+}
+
+// builtin returns addresses which mtg knows without asking Telegram.
+// Overrides either replace hardcoded addresses of the DC (of both IP
+// families) or are tried before them.
+func (d dcView) builtin(dc int, overrides, defaults func(int) []Addr) []Addr {
+	addrs := overrides(dc)
 
-	addrs = append(addrs, defaultDCAddrSet.getV6(dc)...)
-	addrs = append(addrs, d.publicConfigs.getV6(dc)...)
+	if d.replaceOverridden && d.overridden(dc) {
+		return addrs
+	}
+
+	return append(addrs, defaults(dc)...)
+}
+
+func (d dcView) overridden(dc int) bool {
+	return len(d.overrides.v4[dc]) > 0 || len(d.overrides.v6[dc]) > 0
+}
+
+// merge puts addresses from a public config either before or after
+// built-in ones. Public config often duplicates built-in addresses, such
+// duplicates are dropped.
+//
+// Replaced DCs use only addresses from overrides.
+func (d dcView) merge(dc int, builtin []Addr, public func(int) []Addr) []Addr {
+	if isMiddleProxyDC(dc) || (d.replaceOverridden && d.overridden(dc)) {
+		return builtin
+	}
+
+	first, second := builtin, public(dc)
+	if d.publicConfigsFirst {
+		first, second = second, first
+	}
+
+	addrs := make([]Addr, 0, len(first)+len(second))
+	seen := make(map[string]bool, len(first)+len(second))
+
+	for _, list := range [][]Addr{first, second} {
+		for _, addr := range list {
+			if !seen[addr.Address] {
+				seen[addr.Address] = true
+				addrs = append(addrs, addr)
+			}
+		}
+	}
 
 	return addrs
 }
+
+// isMiddleProxyDC tells if a public config announces middle proxies of
+// a given DC instead of its own addresses. These are regular DCs, their
+// media (negative) and test (TestDCOffset + id) counterparts. Any other
+// DC is a CDN one.
+func isMiddleProxyDC(dc int) bool {
+	if dc < 0 {
+		dc = -dc
+	}
+
+	if dc > TestDCOffset {
+		dc -= TestDCOffset
+	}
+
+	return dc < CDNDCMin
+}
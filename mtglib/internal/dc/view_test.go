@@ -34,11 +34,87 @@ func (suite *ViewTestSuite) SetupSuite() {
 	}
 }
 
+func (suite *ViewTestSuite) TestMiddleProxiesAreNotMixed() {
+	suite.Empty(suite.view.getV4(111))
+	suite.Equal([]Addr{{Network: "tcp4", Address: "127.0.0.1:443"}}, suite.view.getMiddleProxyV4(111))
+	suite.Equal([]Addr{{Network: "tcp4", Address: "127.0.0.2:443"}}, suite.view.getMiddleProxyV4(203))
+	suite.Equal([]Addr{{Network: "tcp6", Address: "xxx"}}, suite.view.getMiddleProxyV6(203))
+}
+
+func (suite *ViewTestSuite) TestOrder() {
+	view := suite.view
+
+	addrs := view.getV4(203)
+	suite.Equal("91.105.192.100:443", addrs[0].Address)
+	suite.Equal("127.0.0.2:443", addrs[1].Address)
+
+	view.publicConfigsFirst = true
+
+	addrs = view.getV4(203)
+	suite.Equal("127.0.0.2:443", addrs[0].Address)
+	suite.Equal("91.105.192.100:443", addrs[1].Address)
+}
+
+func (suite *ViewTestSuite) TestDuplicates() {
+	view := dcView{
+		publicConfigs: dcAddrSet{
+			v4: map[int][]Addr{
+				203: {
+					{Network: "tcp4", Address: "91.105.192.100:443"},
+					{Network: "tcp4", Address: "91.105.192.101:443"},
+				},
+			},
+		},
+	}
+
+	suite.ElementsMatch([]Addr{
+		{Network: "tcp4", Address: "91.105.192.100:443"},
+		{Network: "tcp4", Address: "91.105.192.101:443"},
+	}, view.getV4(203))
+}
+
+func (suite *ViewTestSuite) TestReplaced() {
+	view := suite.view
+	view.overrides = dcAddrSet{
+		v4: map[int][]Addr{
+			203: {
+				{Network: "tcp4", Address: "127.0.0.3:443"},
+			},
+		},
+	}
+
+	suite.Len(view.getV4(203), 3)
+
+	view.replaceOverridden = true
+
+	suite.Equal([]Addr{{Network: "tcp4", Address: "127.0.0.3:443"}}, view.getV4(203))
+	suite.Empty(view.getV6(203))
+}
+
+func (suite *ViewTestSuite) TestIsMiddleProxyDC() {
+	testData := map[int]bool{
+		1:      true,
+		5:      true,
+		-2:     true,
+		10002:  true,
+		-10004: true,
+		199:    true,
+		200:    false,
+		203:    false,
+		-203:   false,
+		10203:  false,
+	}
+
+	for dc, expected := range testData {
+		suite.T().Run(fmt.Sprintf("dc%d", dc), func(t *testing.T) {
+			assert.Equal(t, expected, isMiddleProxyDC(dc))
+		})
+	}
+}
+
 func (suite *ViewTestSuite) TestGetV4() {
 	testData := map[int][]Addr{
-		111: {
-			{Network: "tcp4", Address: "127.0.0.1:443"},
-		},
+		111: {},
 		203: {
 			{Network: "tcp4", Address: "127.0.0.2:443"},
 			{Network: "tcp4", Address: "91.105.192.100:443"},
@@ -25,11 +25,55 @@ type NoiseParams struct {
 	Jitter int
 }
 
+// State is a snapshot of what ganger has learned so far.
+type State struct {
+	URLs      []string
+	DRS       bool
+	Durations int
+	CertSizes int
+
+	// Calibrated is true if parameters of Weibull distribution are
+	// calculated from measured durations instead of predefined ones.
+	Calibrated bool
+	K          float64
+	Lambda     float64
+
+	Noise NoiseParams
+}
+
 type scoutRaidResult struct {
 	durations []time.Duration
 	certSizes []int
 }
 
+// scoutGeneration is a scout with own context and channel of raid
+// results. When scout is replaced, raids of a previous generation are
+// canceled and stats calculated from its measurements are dropped.
+type scoutGeneration struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	scout     Scout
+	collected chan scoutRaidResult
+}
+
+func newScoutGeneration(ctx context.Context, scout Scout) *scoutGeneration {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &scoutGeneration{
+		ctx:       ctx,
+		ctxCancel: cancel,
+		scout:     scout,
+		collected: make(chan scoutRaidResult),
+	}
+}
+
+// scoutStats are stats calculated from measurements of a given
+// generation of scout.
+type scoutStats struct {
+	stats      Stats
+	generation *scoutGeneration
+}
+
 type gangerConnRequest struct {
 	ret     chan<- Conn
 	payload essentials.Conn
@@ -52,8 +96,11 @@ type Ganger struct {
 	certSizes []int
 
 	noiseParams atomic.Pointer[NoiseParams]
+	state       atomic.Pointer[State]
+	calibrated  bool
 
 	connRequests chan gangerConnRequest
+	scoutUpdates chan Scout
 }
 
 func (g *Ganger) Shutdown() {
@@ -67,6 +114,25 @@ func (g *Ganger) Run() {
 	})
 }
 
+// SetURLs replaces URLs which are used by scout raids. Collected
+// measurements are dropped, a new raid starts immediately.
+func (g *Ganger) SetURLs(urls []string) {
+	scout := NewScout(g.scout.network, urls)
+
+	select {
+	case <-g.ctx.Done():
+	case g.scoutUpdates <- scout:
+	}
+}
+
+// State returns a snapshot of collected measurements.
+func (g *Ganger) State() State {
+	state := *g.state.Load()
+	state.Noise = g.NoiseParams()
+
+	return state
+}
+
 // NoiseParams returns the current cert-size-based noise parameters.
 // Returns zero-value NoiseParams if not yet measured (caller should use fallback).
 func (g *Ganger) NoiseParams() NoiseParams {
@@ -110,14 +176,16 @@ func (g *Ganger) run() {
 		}
 	}()
 
-	scoutCollectedChan := make(chan scoutRaidResult)
-	currentScoutCollectedChan := scoutCollectedChan
+	updatedStatsChan := make(chan scoutStats)
 
-	updatedStatsChan := make(chan Stats)
+	generation := newScoutGeneration(g.ctx, g.scout)
+	currentScoutCollectedChan := generation.collected
 
-	g.wg.Go(func() {
-		g.runScoutRaid(scoutCollectedChan)
-	})
+	defer func() {
+		generation.ctxCancel()
+	}()
+
+	g.startScoutRaid(generation)
 
 	for {
 		select {
@@ -146,29 +214,65 @@ func (g *Ganger) run() {
 			}
 
 			durations := g.durations
+			statsGeneration := generation
 			currentScoutCollectedChan = nil
 			g.wg.Go(func() {
 				select {
 				case <-g.ctx.Done():
-				case updatedStatsChan <- NewStats(durations, g.drs):
+				case updatedStatsChan <- scoutStats{
+					stats:      NewStats(durations, g.drs),
+					generation: statsGeneration,
+				}:
 				}
 			})
 		case stats := <-updatedStatsChan:
-			g.stats = stats
-			currentScoutCollectedChan = scoutCollectedChan
+			// a scout was replaced while these stats were calculated.
+			// Results of a new scout are already read.
+			if stats.generation != generation {
+				continue
+			}
+
+			g.stats = stats.stats
+			g.calibrated = true
+			currentScoutCollectedChan = generation.collected
 		case <-scoutTicker.C:
-			g.wg.Go(func() {
-				g.runScoutRaid(scoutCollectedChan)
-			})
+			g.startScoutRaid(generation)
+		case scout := <-g.scoutUpdates:
+			g.durations = nil
+			g.certSizes = nil
+
+			generation.ctxCancel()
+			generation = newScoutGeneration(g.ctx, scout)
+			currentScoutCollectedChan = generation.collected
+
+			scoutTicker.Reset(g.scoutRaidEach)
+			g.startScoutRaid(generation)
 		case req := <-g.connRequests:
 			select {
 			case <-g.ctx.Done():
 			case req.ret <- NewConn(g.ctx, req.payload, g.stats):
 			}
+
+			continue
 		}
+
+		g.publishState(generation.scout)
 	}
 }
 
+// publishState makes a snapshot of fields which are owned by run loop.
+func (g *Ganger) publishState(scout Scout) {
+	g.state.Store(&State{
+		URLs:       scout.urls,
+		DRS:        g.drs,
+		Durations:  len(g.durations),
+		CertSizes:  len(g.certSizes),
+		Calibrated: g.calibrated,
+		K:          g.stats.k,
+		Lambda:     g.stats.lambda,
+	})
+}
+
 func (g *Ganger) updateNoiseParams() {
 	if len(g.certSizes) == 0 {
 		return
@@ -206,12 +310,22 @@ func (g *Ganger) updateNoiseParams() {
 	))
 }
 
-func (g *Ganger) runScoutRaid(rvChan chan<- scoutRaidResult) {
+func (g *Ganger) startScoutRaid(generation *scoutGeneration) {
+	g.wg.Go(func() {
+		g.runScoutRaid(generation.ctx, generation.scout, generation.collected)
+	})
+}
+
+func (g *Ganger) runScoutRaid(ctx context.Context, scout Scout, rvChan chan<- scoutRaidResult) {
 	var result scoutRaidResult
 
 	for range g.scoutRaidRepeats {
-		learned, err := g.scout.Learn(g.ctx)
-		if err != nil {
+		learned, err := scout.Learn(ctx)
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
 			g.logger.WarningError("cannot learn", err)
 			continue
 		}
@@ -224,8 +338,7 @@ func (g *Ganger) runScoutRaid(rvChan chan<- scoutRaidResult) {
 	}
 
 	select {
-	case <-g.ctx.Done():
-		return
+	case <-ctx.Done():
 	case rvChan <- result:
 	}
 }
@@ -249,7 +362,7 @@ func NewGanger(
 		scoutRepeats = DoppelGangerScoutRepeats
 	}
 
-	return &Ganger{
+	ganger := &Ganger{
 		ctx:              ctx,
 		ctxCancel:        cancel,
 		logger:           logger,
@@ -263,5 +376,10 @@ func NewGanger(
 		},
 		scout:        NewScout(network, urls),
 		connRequests: make(chan gangerConnRequest),
+		scoutUpdates: make(chan Scout),
 	}
+
+	ganger.publishState(ganger.scout)
+
+	return ganger
 }
@@ -2,6 +2,7 @@ package doppel
 
 import (
 	"bytes"
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -64,6 +65,68 @@ func (suite *GangerTestSuite) TestNewConnWhileRunning() {
 	conn.Stop()
 }
 
+func (suite *GangerTestSuite) TestSetURLs() {
+	suite.g.SetURLs(suite.urls)
+
+	connMock := &testlib.EssentialsConnMock{}
+	connMock.
+		On("Write", mock.AnythingOfType("[]uint8")).
+		Return(0, nil).
+		Maybe()
+	connMock.On("Close").
+		Return(nil).
+		Maybe()
+
+	conn, err := suite.g.NewConn(connMock)
+	suite.NoError(err)
+
+	conn.Stop()
+}
+
+func (suite *GangerTestSuite) TestSetURLsAfterShutdown() {
+	suite.g.Shutdown()
+	suite.g.SetURLs(suite.urls)
+}
+
+func (suite *GangerTestSuite) TestScoutRaidIsCanceled() {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	done := make(chan struct{})
+
+	go func() {
+		// nobody reads results: a raid of a replaced scout
+		suite.g.runScoutRaid(ctx, NewScout(suite.network, suite.urls), make(chan scoutRaidResult))
+		close(done)
+	}()
+
+	time.Sleep(500 * time.Millisecond)
+	cancel()
+
+	suite.Eventually(func() bool {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (suite *GangerTestSuite) TestState() {
+	state := suite.g.State()
+
+	suite.Equal(suite.urls, state.URLs)
+	suite.True(state.DRS)
+	suite.InDelta(StatsDefaultK, state.K, 0.0001)
+	suite.InDelta(StatsDefaultLambda, state.Lambda, 0.0001)
+
+	urls := []string{"https://example.com"}
+	suite.g.SetURLs(urls)
+
+	suite.Eventually(func() bool {
+		return len(suite.g.State().URLs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func (suite *GangerTestSuite) TestNewConnWriteProducesTLSRecords() {
 	var (
 		mu  sync.Mutex
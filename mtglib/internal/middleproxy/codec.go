@@ -0,0 +1,157 @@
+package middleproxy
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// codec reads and writes RPC frames. A structure of the frame is
+// following:
+//
+//   - 4 bytes of total frame length, little endian
+//   - 4 bytes of sequence number, little endian signed integer
+//   - payload
+//   - 4 bytes of CRC32 of all previous bytes
+//
+// Initially frames are sent in plain text. After nonce exchange, codec
+// is switched to AES-256-CBC: each write is padded to the block size
+// with 4-byte padding words.
+//
+// Reading and writing are independent and can be done concurrently.
+type codec struct {
+	reader    io.Reader
+	writer    io.Writer
+	encrypter cipher.BlockMode
+	readSeq   int32
+	writeSeq  int32
+}
+
+func (c *codec) encrypt(writeKey, writeIV, readKey, readIV []byte) {
+	writeBlock, err := aes.NewCipher(writeKey)
+	if err != nil {
+		panic(err)
+	}
+
+	readBlock, err := aes.NewCipher(readKey)
+	if err != nil {
+		panic(err)
+	}
+
+	c.encrypter = cipher.NewCBCEncrypter(writeBlock, writeIV)
+	c.reader = &cbcReader{
+		reader:    c.reader,
+		decrypter: cipher.NewCBCDecrypter(readBlock, readIV),
+	}
+}
+
+func (c *codec) writeFrame(payload []byte) error {
+	size := frameHeaderLength + len(payload) + frameChecksumLength
+	buf := make([]byte, 0, size+aes.BlockSize)
+
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(size))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(c.writeSeq))
+	buf = append(buf, payload...)
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+
+	if c.encrypter != nil {
+		for len(buf)%aes.BlockSize != 0 {
+			buf = binary.LittleEndian.AppendUint32(buf, framePaddingLength)
+		}
+
+		c.encrypter.CryptBlocks(buf, buf)
+	}
+
+	c.writeSeq++
+
+	if _, err := c.writer.Write(buf); err != nil {
+		return fmt.Errorf("cannot write a frame: %w", err)
+	}
+
+	return nil
+}
+
+func (c *codec) readFrame() ([]byte, error) {
+	var size uint32
+
+	header := [4]byte{}
+
+	for {
+		if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+			return nil, fmt.Errorf("cannot read frame length: %w", err)
+		}
+
+		size = binary.LittleEndian.Uint32(header[:])
+		if size != framePaddingLength {
+			break
+		}
+	}
+
+	if size < frameHeaderLength+frameChecksumLength || size > frameMaxLength || size%4 != 0 {
+		return nil, fmt.Errorf("incorrect frame length %d: %w", size, errFrameInvalid)
+	}
+
+	buf := make([]byte, size)
+	copy(buf, header[:])
+
+	if _, err := io.ReadFull(c.reader, buf[len(header):]); err != nil {
+		return nil, fmt.Errorf("cannot read a frame: %w", err)
+	}
+
+	checksumOffset := len(buf) - frameChecksumLength
+	if crc32.ChecksumIEEE(buf[:checksumOffset]) != binary.LittleEndian.Uint32(buf[checksumOffset:]) {
+		return nil, errFrameChecksum
+	}
+
+	if seq := int32(binary.LittleEndian.Uint32(buf[4:])); seq != c.readSeq {
+		return nil, fmt.Errorf("expected %d, got %d: %w", c.readSeq, seq, errFrameSequence)
+	}
+
+	c.readSeq++
+
+	return buf[frameHeaderLength:checksumOffset], nil
+}
+
+func newCodec(rw io.ReadWriter) *codec {
+	return &codec{
+		reader:   rw,
+		writer:   rw,
+		readSeq:  initialSequenceNumber,
+		writeSeq: initialSequenceNumber,
+	}
+}
+
+type cbcReader struct {
+	reader    io.Reader
+	decrypter cipher.BlockMode
+	buf       [4096]byte
+	pending   []byte
+}
+
+func (c *cbcReader) Read(p []byte) (int, error) {
+	if len(c.pending) == 0 {
+		n, err := io.ReadAtLeast(c.reader, c.buf[:], aes.BlockSize)
+		if err != nil {
+			return 0, err //nolint: wrapcheck
+		}
+
+		if rest := n % aes.BlockSize; rest != 0 {
+			if _, err := io.ReadFull(c.reader, c.buf[n:n+aes.BlockSize-rest]); err != nil {
+				return 0, err //nolint: wrapcheck
+			}
+
+			n += aes.BlockSize - rest
+		}
+
+		c.decrypter.CryptBlocks(c.buf[:n], c.buf[:n])
+		c.pending = c.buf[:n]
+	}
+
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+
+	return n, nil
+}
@@ -0,0 +1,93 @@
+package middleproxy
+
+import (
+	"bytes"
+	"crypto/aes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CodecTestSuite struct {
+	suite.Suite
+
+	buf    *bytes.Buffer
+	writer *codec
+	reader *codec
+}
+
+func (s *CodecTestSuite) SetupTest() {
+	s.buf = &bytes.Buffer{}
+	s.writer = newCodec(s.buf)
+	s.reader = newCodec(s.buf)
+}
+
+func (s *CodecTestSuite) encrypt() {
+	key := bytes.Repeat([]byte{1}, 32)
+	iv := bytes.Repeat([]byte{2}, 16)
+
+	s.writer.encrypt(key, iv, key, iv)
+	s.reader.encrypt(key, iv, key, iv)
+}
+
+func (s *CodecTestSuite) TestPlain() {
+	s.NoError(s.writer.writeFrame([]byte{1, 2, 3, 4}))
+	s.NoError(s.writer.writeFrame([]byte{5, 6, 7, 8, 9, 10, 11, 12}))
+	s.Equal(12+4+12+8, s.buf.Len())
+
+	frame, err := s.reader.readFrame()
+	s.NoError(err)
+	s.Equal([]byte{1, 2, 3, 4}, frame)
+
+	frame, err = s.reader.readFrame()
+	s.NoError(err)
+	s.Equal([]byte{5, 6, 7, 8, 9, 10, 11, 12}, frame)
+}
+
+func (s *CodecTestSuite) TestEncrypted() {
+	s.encrypt()
+
+	payload := bytes.Repeat([]byte{0xaa}, 40)
+
+	s.NoError(s.writer.writeFrame(payload))
+	s.NoError(s.writer.writeFrame(payload[:4]))
+	s.Zero(s.buf.Len() % aes.BlockSize)
+	s.NotContains(s.buf.String(), string(payload[:16]))
+
+	frame, err := s.reader.readFrame()
+	s.NoError(err)
+	s.Equal(payload, frame)
+
+	frame, err = s.reader.readFrame()
+	s.NoError(err)
+	s.Equal(payload[:4], frame)
+}
+
+func (s *CodecTestSuite) TestChecksum() {
+	s.NoError(s.writer.writeFrame([]byte{1, 2, 3, 4}))
+	s.buf.Bytes()[9] ^= 0xff
+
+	_, err := s.reader.readFrame()
+	s.ErrorIs(err, errFrameChecksum)
+}
+
+func (s *CodecTestSuite) TestSequence() {
+	s.writer.writeSeq = 10
+
+	s.NoError(s.writer.writeFrame([]byte{1, 2, 3, 4}))
+
+	_, err := s.reader.readFrame()
+	s.ErrorIs(err, errFrameSequence)
+}
+
+func (s *CodecTestSuite) TestIncorrectLength() {
+	s.buf.Write([]byte{7, 0, 0, 0, 0, 0, 0, 0})
+
+	_, err := s.reader.readFrame()
+	s.ErrorIs(err, errFrameInvalid)
+}
+
+func TestCodec(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &CodecTestSuite{})
+}
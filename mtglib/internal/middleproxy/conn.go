@@ -0,0 +1,322 @@
+package middleproxy
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/9seconds/mtg/v2/essentials"
+)
+
+// Opts defines parameters of a connection to a middle proxy.
+type Opts struct {
+	// Secret is a secret of middle proxies. It could be fetched from
+	// https://core.telegram.org/getProxySecret
+	Secret []byte
+
+	// AdTag is an advertisement tag. It is optional.
+	AdTag []byte
+
+	// ClientAddr is an address of a client.
+	ClientAddr net.Addr
+
+	// ProxyAddr is an address of mtg a client is connected to.
+	ProxyAddr net.Addr
+
+	// PublicIPv4 and PublicIPv6 are used instead of a local address of a
+	// connection to a middle proxy. Middle proxy uses an address it sees
+	// to derive encryption keys, so these have to be set if mtg is behind
+	// NAT.
+	PublicIPv4 net.IP
+	PublicIPv6 net.IP
+}
+
+type conn struct {
+	essentials.Conn
+
+	codec   *codec
+	header  []byte
+	flags   uint32
+	writeTo []byte
+	readTo  []byte
+}
+
+// Read returns answers of a middle proxy framed as padded intermediate
+// packets.
+func (c *conn) Read(p []byte) (int, error) {
+	for len(c.readTo) == 0 {
+		frame, err := c.codec.readFrame()
+		if err != nil {
+			return 0, err
+		}
+
+		if len(frame) < 4 { //nolint: mnd
+			return 0, errFrameInvalid
+		}
+
+		switch binary.LittleEndian.Uint32(frame) {
+		case rpcProxyAns:
+			// type, flags, connection id, data
+			if len(frame) < 16 { //nolint: mnd
+				return 0, errFrameInvalid
+			}
+
+			data := frame[16:]
+			c.readTo = binary.LittleEndian.AppendUint32(make([]byte, 0, 4+len(data)), uint32(len(data)))
+			c.readTo = append(c.readTo, data...)
+		case rpcSimpleAck:
+			// type, connection id, confirm
+			if len(frame) < 16 { //nolint: mnd
+				return 0, errFrameInvalid
+			}
+
+			c.readTo = frame[12:16]
+		case rpcCloseExt:
+			return 0, io.EOF
+		}
+	}
+
+	n := copy(p, c.readTo)
+	c.readTo = c.readTo[n:]
+
+	return n, nil
+}
+
+// Write accepts padded intermediate packets of a client and sends each of
+// them as RPC_PROXY_REQ. Incomplete packets are buffered.
+func (c *conn) Write(p []byte) (int, error) {
+	c.writeTo = append(c.writeTo, p...)
+	consumed := 0
+
+	defer func() {
+		c.writeTo = append(c.writeTo[:0], c.writeTo[consumed:]...)
+	}()
+
+	for len(c.writeTo)-consumed >= 4 { //nolint: mnd
+		buf := c.writeTo[consumed:]
+		size := binary.LittleEndian.Uint32(buf)
+		flags := c.flags
+
+		if size&clientPacketQuickAck != 0 {
+			size &^= clientPacketQuickAck
+			flags |= flagQuickAck
+		}
+
+		if size > clientPacketMaxLength {
+			return 0, errPacketTooLarge
+		}
+
+		if uint32(len(buf)-4) < size {
+			break
+		}
+
+		packet := buf[4 : 4+size]
+
+		if len(packet) >= 8 && bytes.Equal(packet[:8], make([]byte, 8)) {
+			flags |= flagNotEncrypted
+		}
+
+		payload := make([]byte, 0, 8+len(c.header)+len(packet))
+		payload = binary.LittleEndian.AppendUint32(payload, rpcProxyReq)
+		payload = binary.LittleEndian.AppendUint32(payload, flags)
+		payload = append(payload, c.header...)
+		payload = append(payload, packet...)
+
+		if err := c.codec.writeFrame(payload); err != nil {
+			return 0, err
+		}
+
+		consumed += 4 + int(size)
+	}
+
+	return len(p), nil
+}
+
+// NewConn performs a handshake with a middle proxy and returns a
+// connection which works with padded intermediate packets of a client.
+func NewConn(tgConn essentials.Conn, opts Opts) (essentials.Conn, error) {
+	if len(opts.Secret) < 4 { //nolint: mnd
+		return nil, ErrProxySecretInvalid
+	}
+
+	if len(opts.AdTag) != 0 && len(opts.AdTag) != AdTagLength {
+		return nil, ErrAdTagInvalid
+	}
+
+	cdc := newCodec(tgConn)
+
+	if err := handshake(cdc, tgConn, opts); err != nil {
+		return nil, err
+	}
+
+	connID := make([]byte, 8) //nolint: mnd
+	rand.Read(connID)         //nolint: errcheck
+
+	header := make([]byte, 0, 8+20+20+24)
+	header = append(header, connID...)
+	header = appendIPPort(header, opts.ClientAddr)
+	header = appendIPPort(header, opts.ProxyAddr)
+
+	flags := flagMagic | flagExtMode2 | flagIntermediate | flagPad
+
+	if len(opts.AdTag) > 0 {
+		flags |= flagHasAdTag
+
+		// extra section: its size, TL_PROXY_TAG and tag as a TL string
+		// padded to 4 bytes.
+		header = binary.LittleEndian.AppendUint32(header, 24) //nolint: mnd
+		header = binary.LittleEndian.AppendUint32(header, tlProxyTag)
+		header = append(header, byte(len(opts.AdTag)))
+		header = append(header, opts.AdTag...)
+		header = append(header, 0, 0, 0)
+	}
+
+	return &conn{
+		Conn:   tgConn,
+		codec:  cdc,
+		header: header,
+		flags:  flags,
+	}, nil
+}
+
+func handshake(cdc *codec, tgConn essentials.Conn, opts Opts) error {
+	clientIP, clientPort := splitAddr(tgConn.LocalAddr())
+	serverIP, serverPort := splitAddr(tgConn.RemoteAddr())
+
+	switch {
+	case clientIP.To4() != nil && opts.PublicIPv4 != nil:
+		clientIP = opts.PublicIPv4
+	case clientIP.To4() == nil && opts.PublicIPv6 != nil:
+		clientIP = opts.PublicIPv6
+	}
+
+	nonce := make([]byte, 16) //nolint: mnd
+	rand.Read(nonce)          //nolint: errcheck
+
+	keySelector := opts.Secret[:4]
+	clientTime := uint32(time.Now().Unix())
+
+	request := make([]byte, 0, 32) //nolint: mnd
+	request = binary.LittleEndian.AppendUint32(request, rpcNonce)
+	request = append(request, keySelector...)
+	request = binary.LittleEndian.AppendUint32(request, rpcCryptoAES)
+	request = binary.LittleEndian.AppendUint32(request, clientTime)
+	request = append(request, nonce...)
+
+	if err := cdc.writeFrame(request); err != nil {
+		return fmt.Errorf("cannot send nonce: %w", err)
+	}
+
+	answer, err := cdc.readFrame()
+	if err != nil {
+		return fmt.Errorf("cannot read nonce: %w", err)
+	}
+
+	switch {
+	case len(answer) < 32: //nolint: mnd
+		return fmt.Errorf("nonce is too short: %w", errHandshakeInvalid)
+	case binary.LittleEndian.Uint32(answer) != rpcNonce:
+		return fmt.Errorf("unexpected nonce type: %w", errHandshakeInvalid)
+	case !bytes.Equal(answer[4:8], keySelector):
+		return fmt.Errorf("unexpected key selector: %w", errHandshakeInvalid)
+	case binary.LittleEndian.Uint32(answer[8:]) != rpcCryptoAES:
+		return fmt.Errorf("unexpected crypto schema: %w", errHandshakeInvalid)
+	}
+
+	params := keyParams{
+		nonceServer: answer[16:32],
+		nonceClient: nonce,
+		clientTime:  clientTime,
+		serverIP:    serverIP,
+		serverPort:  serverPort,
+		clientIP:    clientIP,
+		clientPort:  clientPort,
+		secret:      opts.Secret,
+	}
+
+	writeKey, writeIV := deriveKeys(params, purposeClient)
+	readKey, readIV := deriveKeys(params, purposeServer)
+
+	cdc.encrypt(writeKey, writeIV, readKey, readIV)
+
+	pid := makeProcessID(clientIP, clientPort)
+
+	request = request[:0]
+	request = binary.LittleEndian.AppendUint32(request, rpcHandshake)
+	request = binary.LittleEndian.AppendUint32(request, 0)
+	request = append(request, pid...)
+	request = append(request, pid...)
+
+	if err := cdc.writeFrame(request); err != nil {
+		return fmt.Errorf("cannot send handshake: %w", err)
+	}
+
+	answer, err = cdc.readFrame()
+	if err != nil {
+		return fmt.Errorf("cannot read handshake: %w", err)
+	}
+
+	switch {
+	case len(answer) >= 4 && binary.LittleEndian.Uint32(answer) == rpcHandshakeError:
+		return fmt.Errorf("middle proxy has rejected handshake: %w", errHandshakeInvalid)
+	case len(answer) < 32: //nolint: mnd
+		return fmt.Errorf("handshake is too short: %w", errHandshakeInvalid)
+	case binary.LittleEndian.Uint32(answer) != rpcHandshake:
+		return fmt.Errorf("unexpected handshake type: %w", errHandshakeInvalid)
+	case !bytes.Equal(answer[20:32], pid):
+		return fmt.Errorf("unexpected peer process id: %w", errHandshakeInvalid)
+	}
+
+	return nil
+}
+
+// process id is 4 bytes of IPv4 address, 2 bytes of port, 2 bytes of
+// pid and 4 bytes of start time.
+func makeProcessID(ip net.IP, port uint16) []byte {
+	buf := make([]byte, 0, 12) //nolint: mnd
+	buf = appendIPv4(buf, ip.To4(), ip.To4() == nil)
+	buf = binary.LittleEndian.AppendUint16(buf, port)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(os.Getpid()))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(time.Now().Unix()))
+
+	return buf
+}
+
+// IP is encoded as 16 bytes of IPv6 (IPv4 is mapped), port is 4 bytes
+// little endian.
+func appendIPPort(buf []byte, addr net.Addr) []byte {
+	ip, port := splitAddr(addr)
+
+	if ip16 := ip.To16(); ip16 != nil {
+		buf = append(buf, ip16...)
+	} else {
+		buf = append(buf, make([]byte, net.IPv6len)...)
+	}
+
+	return binary.LittleEndian.AppendUint32(buf, uint32(port))
+}
+
+func splitAddr(addr net.Addr) (net.IP, uint16) {
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.IP, uint16(tcpAddr.Port)
+	}
+
+	if addr == nil {
+		return nil, 0
+	}
+
+	host, portStr, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil, 0
+	}
+
+	port, _ := strconv.ParseUint(portStr, 10, 16)
+
+	return net.ParseIP(host), uint16(port)
+}
@@ -0,0 +1,249 @@
+package middleproxy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/9seconds/mtg/v2/essentials"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeMiddleProxy implements a server side of the middle proxy protocol.
+// It answers each RPC_PROXY_REQ with RPC_PROXY_ANS with the same packet.
+type fakeMiddleProxy struct {
+	listener net.Listener
+	secret   []byte
+	requests chan []byte
+	errors   chan error
+}
+
+func (f *fakeMiddleProxy) serve() {
+	conn, err := f.listener.Accept()
+	if err != nil {
+		f.errors <- err
+		return
+	}
+
+	defer conn.Close() //nolint: errcheck
+
+	f.errors <- f.handle(conn)
+}
+
+func (f *fakeMiddleProxy) handle(conn net.Conn) error {
+	cdc := newCodec(conn)
+
+	request, err := cdc.readFrame()
+	if err != nil {
+		return err
+	}
+
+	if binary.LittleEndian.Uint32(request) != rpcNonce || !bytes.Equal(request[4:8], f.secret[:4]) {
+		return errors.New("unexpected nonce")
+	}
+
+	nonce := bytes.Repeat([]byte{0x42}, 16)
+	answer := append([]byte{}, request[:16]...)
+	answer = append(answer, nonce...)
+
+	if err := cdc.writeFrame(answer); err != nil {
+		return err
+	}
+
+	clientAddr := conn.RemoteAddr().(*net.TCPAddr) //nolint: forcetypeassert
+	serverAddr := conn.LocalAddr().(*net.TCPAddr)  //nolint: forcetypeassert
+	params := keyParams{
+		nonceServer: nonce,
+		nonceClient: request[16:32],
+		clientTime:  binary.LittleEndian.Uint32(request[12:]),
+		serverIP:    serverAddr.IP,
+		serverPort:  uint16(serverAddr.Port),
+		clientIP:    clientAddr.IP,
+		clientPort:  uint16(clientAddr.Port),
+		secret:      f.secret,
+	}
+
+	writeKey, writeIV := deriveKeys(params, purposeServer)
+	readKey, readIV := deriveKeys(params, purposeClient)
+
+	cdc.encrypt(writeKey, writeIV, readKey, readIV)
+
+	request, err = cdc.readFrame()
+	if err != nil {
+		return err
+	}
+
+	if binary.LittleEndian.Uint32(request) != rpcHandshake {
+		return errors.New("unexpected handshake")
+	}
+
+	answer = binary.LittleEndian.AppendUint32(nil, rpcHandshake)
+	answer = binary.LittleEndian.AppendUint32(answer, 0)
+	answer = append(answer, bytes.Repeat([]byte{1}, 12)...)
+	answer = append(answer, request[8:20]...)
+
+	if err := cdc.writeFrame(answer); err != nil {
+		return err
+	}
+
+	for {
+		request, err := cdc.readFrame()
+		if err != nil {
+			return nil //nolint: nilerr
+		}
+
+		f.requests <- request
+
+		// type, flags, connection id, client address, proxy address
+		headerSize := 4 + 4 + 8 + 20 + 20
+		if binary.LittleEndian.Uint32(request[4:])&flagHasAdTag != 0 {
+			headerSize += 4 + 24
+		}
+
+		answer := binary.LittleEndian.AppendUint32(nil, rpcProxyAns)
+		answer = binary.LittleEndian.AppendUint32(answer, 0)
+		answer = append(answer, request[8:16]...)
+		answer = append(answer, request[headerSize:]...)
+
+		if err := cdc.writeFrame(answer); err != nil {
+			return err
+		}
+
+		answer = binary.LittleEndian.AppendUint32(nil, rpcSimpleAck)
+		answer = append(answer, request[8:16]...)
+		answer = append(answer, 1, 2, 3, 4)
+
+		if err := cdc.writeFrame(answer); err != nil {
+			return err
+		}
+	}
+}
+
+type ConnTestSuite struct {
+	suite.Suite
+
+	proxy *fakeMiddleProxy
+	conn  net.Conn
+	opts  Opts
+}
+
+func (s *ConnTestSuite) SetupTest() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+
+	s.proxy = &fakeMiddleProxy{
+		listener: listener,
+		secret:   bytes.Repeat([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 16),
+		requests: make(chan []byte, 10),
+		errors:   make(chan error, 1),
+	}
+
+	go s.proxy.serve()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	s.Require().NoError(err)
+
+	s.conn = conn
+	s.opts = Opts{
+		Secret:     s.proxy.secret,
+		AdTag:      bytes.Repeat([]byte{0xad}, AdTagLength),
+		ClientAddr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000},
+		ProxyAddr:  &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 443},
+	}
+}
+
+func (s *ConnTestSuite) TearDownTest() {
+	s.conn.Close()           //nolint: errcheck
+	s.proxy.listener.Close() //nolint: errcheck
+}
+
+func (s *ConnTestSuite) TestRelay() {
+	conn, err := NewConn(essentials.WrapNetConn(s.conn), s.opts)
+	s.Require().NoError(err)
+
+	packet := bytes.Repeat([]byte{0xff}, 40)
+	data := binary.LittleEndian.AppendUint32(nil, uint32(len(packet))|clientPacketQuickAck)
+	data = append(data, packet...)
+
+	// a packet is split between writes
+	_, err = conn.Write(data[:10])
+	s.NoError(err)
+	_, err = conn.Write(data[10:])
+	s.NoError(err)
+
+	request := <-s.proxy.requests
+	flags := binary.LittleEndian.Uint32(request[4:])
+
+	s.Equal(rpcProxyReq, binary.LittleEndian.Uint32(request))
+	s.NotZero(flags & flagHasAdTag)
+	s.NotZero(flags & flagQuickAck)
+	s.NotZero(flags & flagIntermediate)
+	s.Zero(flags & flagNotEncrypted)
+	s.Equal(net.ParseIP("10.0.0.1").To16(), net.IP(request[16:32]))
+	s.EqualValues(5000, binary.LittleEndian.Uint32(request[32:]))
+	s.Equal(net.ParseIP("10.0.0.2").To16(), net.IP(request[36:52]))
+	s.EqualValues(443, binary.LittleEndian.Uint32(request[52:]))
+	s.EqualValues(24, binary.LittleEndian.Uint32(request[56:]))
+	s.Equal(tlProxyTag, binary.LittleEndian.Uint32(request[60:]))
+	s.Equal(s.opts.AdTag, request[65:81])
+	s.Equal(packet, request[84:])
+
+	answer := make([]byte, 4+len(packet)+4)
+	_, err = io.ReadFull(conn, answer)
+	s.NoError(err)
+
+	s.EqualValues(len(packet), binary.LittleEndian.Uint32(answer))
+	s.Equal(packet, answer[4:4+len(packet)])
+	s.Equal([]byte{1, 2, 3, 4}, answer[4+len(packet):])
+}
+
+func (s *ConnTestSuite) TestNoAdTag() {
+	s.opts.AdTag = nil
+
+	conn, err := NewConn(essentials.WrapNetConn(s.conn), s.opts)
+	s.Require().NoError(err)
+
+	packet := make([]byte, 16)
+	data := binary.LittleEndian.AppendUint32(nil, uint32(len(packet)))
+
+	_, err = conn.Write(append(data, packet...))
+	s.NoError(err)
+
+	request := <-s.proxy.requests
+	flags := binary.LittleEndian.Uint32(request[4:])
+
+	s.Zero(flags & flagHasAdTag)
+	s.Zero(flags & flagQuickAck)
+	s.NotZero(flags & flagNotEncrypted)
+	s.Equal(packet, request[56:])
+}
+
+func (s *ConnTestSuite) TestWrongSecret() {
+	s.opts.Secret = bytes.Repeat([]byte{1, 2, 3, 4, 5, 6, 7, 9}, 16)
+
+	_, err := NewConn(essentials.WrapNetConn(s.conn), s.opts)
+	s.Error(err)
+	s.Error(<-s.proxy.errors)
+}
+
+func (s *ConnTestSuite) TestIncorrectAdTag() {
+	s.opts.AdTag = []byte{1, 2, 3}
+
+	_, err := NewConn(essentials.WrapNetConn(s.conn), s.opts)
+	s.ErrorIs(err, ErrAdTagInvalid)
+}
+
+func (s *ConnTestSuite) TestIncorrectSecret() {
+	s.opts.Secret = []byte{1}
+
+	_, err := NewConn(essentials.WrapNetConn(s.conn), s.opts)
+	s.ErrorIs(err, ErrProxySecretInvalid)
+}
+
+func TestConn(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &ConnTestSuite{})
+}
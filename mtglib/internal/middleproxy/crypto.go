@@ -0,0 +1,77 @@
+package middleproxy
+
+import (
+	"crypto/md5"  //nolint: gosec
+	"crypto/sha1" //nolint: gosec
+	"encoding/binary"
+	"net"
+)
+
+const (
+	purposeClient = "CLIENT"
+	purposeServer = "SERVER"
+)
+
+// keyParams are parameters of a key derivation. Client and server
+// addresses are those of a TCP connection between mtg and middle proxy.
+type keyParams struct {
+	nonceServer []byte
+	nonceClient []byte
+	clientTime  uint32
+	serverIP    net.IP
+	serverPort  uint16
+	clientIP    net.IP
+	clientPort  uint16
+	secret      []byte
+}
+
+// deriveKeys calculates AES-256-CBC key and IV. Purpose is CLIENT for
+// the traffic from client to server and SERVER for the opposite
+// direction.
+//
+// https://github.com/TelegramMessenger/MTProxy/blob/master/net/net-crypto-aes.c
+func deriveKeys(params keyParams, purpose string) ([]byte, []byte) {
+	buf := make([]byte, 0, 128+len(params.secret))
+
+	serverIPv4 := params.serverIP.To4()
+	clientIPv4 := params.clientIP.To4()
+	isIPv6 := serverIPv4 == nil || clientIPv4 == nil
+
+	buf = append(buf, params.nonceServer...)
+	buf = append(buf, params.nonceClient...)
+	buf = binary.LittleEndian.AppendUint32(buf, params.clientTime)
+	buf = appendIPv4(buf, serverIPv4, isIPv6)
+	buf = binary.LittleEndian.AppendUint16(buf, params.clientPort)
+	buf = append(buf, purpose...)
+	buf = appendIPv4(buf, clientIPv4, isIPv6)
+	buf = binary.LittleEndian.AppendUint16(buf, params.serverPort)
+	buf = append(buf, params.secret...)
+	buf = append(buf, params.nonceServer...)
+
+	if isIPv6 {
+		buf = append(buf, params.clientIP.To16()...)
+		buf = append(buf, params.serverIP.To16()...)
+	}
+
+	buf = append(buf, params.nonceClient...)
+
+	md5Sum := md5.Sum(buf[1:]) //nolint: gosec
+	sha1Sum := sha1.Sum(buf)   //nolint: gosec
+	iv := md5.Sum(buf[2:])     //nolint: gosec
+
+	key := make([]byte, 0, 32)
+	key = append(key, md5Sum[:12]...)
+	key = append(key, sha1Sum[:]...)
+
+	return key, iv[:]
+}
+
+// IPv4 addresses are stored as integers in host (little endian) order.
+// IPv6 connections use zeroes here.
+func appendIPv4(buf []byte, ip net.IP, isIPv6 bool) []byte {
+	if isIPv6 {
+		return append(buf, 0, 0, 0, 0)
+	}
+
+	return binary.LittleEndian.AppendUint32(buf, binary.BigEndian.Uint32(ip))
+}
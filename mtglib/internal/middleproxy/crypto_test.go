@@ -0,0 +1,79 @@
+package middleproxy
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CryptoTestSuite struct {
+	suite.Suite
+
+	params keyParams
+}
+
+func (s *CryptoTestSuite) SetupTest() {
+	s.params = keyParams{
+		nonceServer: bytes.Repeat([]byte{1}, 16),
+		nonceClient: bytes.Repeat([]byte{2}, 16),
+		clientTime:  1700000000,
+		serverIP:    net.ParseIP("149.154.175.50"),
+		serverPort:  8888,
+		clientIP:    net.ParseIP("10.0.0.1"),
+		clientPort:  34567,
+		secret:      bytes.Repeat([]byte{3}, 128),
+	}
+}
+
+func (s *CryptoTestSuite) TestLengths() {
+	key, iv := deriveKeys(s.params, purposeClient)
+
+	s.Len(key, 32)
+	s.Len(iv, 16)
+}
+
+func (s *CryptoTestSuite) TestDeterministic() {
+	key1, iv1 := deriveKeys(s.params, purposeClient)
+	key2, iv2 := deriveKeys(s.params, purposeClient)
+
+	s.Equal(key1, key2)
+	s.Equal(iv1, iv2)
+}
+
+func (s *CryptoTestSuite) TestPurposes() {
+	clientKey, clientIV := deriveKeys(s.params, purposeClient)
+	serverKey, serverIV := deriveKeys(s.params, purposeServer)
+
+	s.NotEqual(clientKey, serverKey)
+	s.NotEqual(clientIV, serverIV)
+}
+
+func (s *CryptoTestSuite) TestAddressesMatter() {
+	key1, _ := deriveKeys(s.params, purposeClient)
+
+	s.params.clientPort++
+	key2, _ := deriveKeys(s.params, purposeClient)
+
+	s.NotEqual(key1, key2)
+}
+
+func (s *CryptoTestSuite) TestIPv6() {
+	key1, _ := deriveKeys(s.params, purposeClient)
+
+	s.params.serverIP = net.ParseIP("2001:b28:f23d:f001::d")
+	s.params.clientIP = net.ParseIP("2001:db8::1")
+	key2, _ := deriveKeys(s.params, purposeClient)
+
+	s.params.clientIP = net.ParseIP("2001:db8::2")
+	key3, _ := deriveKeys(s.params, purposeClient)
+
+	s.NotEqual(key1, key2)
+	s.NotEqual(key2, key3)
+}
+
+func TestCrypto(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &CryptoTestSuite{})
+}
@@ -0,0 +1,81 @@
+// Package middleproxy implements a client side of the RPC protocol which is
+// spoken by Telegram middle proxies.
+//
+// Middle proxies are the only way to attach an advertisement tag to a
+// connection: a client packet is wrapped into RPC_PROXY_REQ with this
+// tag and sent to a middle proxy instead of a DC. Each client gets its
+// own connection to a middle proxy.
+//
+// A reference implementation is https://github.com/TelegramMessenger/MTProxy
+package middleproxy
+
+import "errors"
+
+const (
+	// AdTagLength is a length of the advertisement tag in bytes.
+	AdTagLength = 16
+
+	// a size of the frame header: length and sequence number.
+	frameHeaderLength = 8
+
+	// a size of the CRC32 checksum in the end of the frame.
+	frameChecksumLength = 4
+
+	// frames bigger than that are considered as a protocol violation.
+	frameMaxLength = 1 << 24
+
+	// middle proxies pad encrypted frames with 4-byte words of this value.
+	framePaddingLength = 4
+
+	// client packets bigger than that are considered as a protocol
+	// violation.
+	clientPacketMaxLength = 1 << 24
+
+	clientPacketQuickAck = 0x80000000
+
+	// the first sequence number of each direction. -2 is for nonce,
+	// -1 is for handshake.
+	initialSequenceNumber = -2
+)
+
+// RPC types.
+const (
+	rpcNonce          uint32 = 0x7acb87aa
+	rpcHandshake      uint32 = 0x7682eef5
+	rpcHandshakeError uint32 = 0x6a27beda
+	rpcProxyReq       uint32 = 0x36cef1ee
+	rpcProxyAns       uint32 = 0x4403da0d
+	rpcSimpleAck      uint32 = 0x3bac409b
+	rpcCloseExt       uint32 = 0x5eb634a2
+
+	rpcCryptoAES uint32 = 1
+
+	tlProxyTag uint32 = 0xdb1e26ae
+)
+
+// Flags of RPC_PROXY_REQ.
+const (
+	flagNotEncrypted uint32 = 0x2
+	flagHasAdTag     uint32 = 0x8
+	flagMagic        uint32 = 0x1000
+	flagExtMode2     uint32 = 0x20000
+	flagPad          uint32 = 0x8000000
+	flagIntermediate uint32 = 0x20000000
+	flagQuickAck     uint32 = 0x80000000
+)
+
+var (
+	// ErrProxySecretInvalid is returned if a secret of middle proxies is
+	// too short.
+	ErrProxySecretInvalid = errors.New("proxy secret is invalid")
+
+	// ErrAdTagInvalid is returned if advertisement tag has incorrect
+	// length.
+	ErrAdTagInvalid = errors.New("ad tag is invalid")
+
+	errFrameInvalid     = errors.New("frame is invalid")
+	errFrameChecksum    = errors.New("frame checksum mismatch")
+	errFrameSequence    = errors.New("unexpected frame sequence number")
+	errHandshakeInvalid = errors.New("unexpected handshake answer")
+	errPacketTooLarge   = errors.New("client packet is too large")
+)
@@ -57,14 +57,11 @@ func (h *handshakeFrame) dcSlice() []byte {
 	return h.data[hfOffsetDC : hfOffsetDC+2]
 }
 
+// dc returns a requested DC. Negative values are media DCs, they are
+// returned as is.
 func (h *handshakeFrame) dc() int {
-	idx := int16(binary.LittleEndian.Uint16(h.dcSlice()))
-
-	switch {
-	case idx > 0:
+	if idx := int16(binary.LittleEndian.Uint16(h.dcSlice())); idx != 0 {
 		return int(idx)
-	case idx < 0:
-		return -int(idx)
 	}
 
 	return defaultDC
@@ -1,6 +1,7 @@
 package obfuscation
 
 import (
+	"encoding/binary"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -52,6 +53,19 @@ func (h *HandshakeFrameTestSuite) TestDC() {
 	h.Equal(15933, h.frame.dc())
 }
 
+func (h *HandshakeFrameTestSuite) TestMediaDC() {
+	fr := handshakeFrame{}
+	binary.LittleEndian.PutUint16(fr.dcSlice(), uint16(0xfffe))
+
+	h.Equal(-2, fr.dc())
+}
+
+func (h *HandshakeFrameTestSuite) TestDefaultDC() {
+	fr := handshakeFrame{}
+
+	h.Equal(defaultDC, fr.dc())
+}
+
 func (h *HandshakeFrameTestSuite) TestRevert() {
 	fr := h.frame
 	fr.revert()
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"hash"
 	"io"
@@ -13,6 +14,10 @@ import (
 	"github.com/9seconds/mtg/v2/essentials"
 )
 
+// ErrUnsupportedConnectionType is returned if a client has requested a
+// connection type other than padded intermediate one.
+var ErrUnsupportedConnectionType = errors.New("unsupported connection type")
+
 type Obfuscator struct {
 	Secret []byte
 }
@@ -34,7 +39,7 @@ func (o Obfuscator) ReadHandshake(r essentials.Conn) (int, essentials.Conn, erro
 	recvCipher.XORKeyStream(frame.data[:], frame.data[:])
 
 	if val := frame.connectionType(); subtle.ConstantTimeCompare(val, hfConnectionType[:]) != 1 {
-		return 0, nil, fmt.Errorf("unsupported connection type: %s", hex.EncodeToString(val))
+		return 0, nil, fmt.Errorf("%w: %s", ErrUnsupportedConnectionType, hex.EncodeToString(val))
 	}
 
 	cn := conn{
@@ -88,6 +88,22 @@ func (s *ObfuscatorTestSuite) TestSnapshot() {
 	}
 }
 
+func (s *ObfuscatorTestSuite) TestUnsupportedConnectionType() {
+	obfs := obfuscation.Obfuscator{
+		Secret: s.secret.Key[:],
+	}
+
+	connMock := &testlib.EssentialsConnMock{}
+	connMock.
+		On("Read", mock.AnythingOfType("[]uint8")).
+		Return(64, nil)
+
+	_, _, err := obfs.ReadHandshake(connMock)
+	s.ErrorIs(err, obfuscation.ErrUnsupportedConnectionType)
+
+	connMock.AssertExpectations(s.T())
+}
+
 func TestObfuscator(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, &ObfuscatorTestSuite{})
@@ -40,6 +40,10 @@ type ClientHello struct {
 	Random      [RandomLen]byte
 	SessionID   []byte
 	CipherSuite uint16
+
+	// Timestamp is a time when client has created this message,
+	// according to its clock.
+	Timestamp time.Time
 }
 
 func ReadClientHello(
@@ -48,6 +52,20 @@ func ReadClientHello(
 	hostname string,
 	tolerateTimeSkewness time.Duration,
 ) (*ClientHello, error) {
+	hello, _, err := ReadClientHelloMulti(conn, [][]byte{secret}, hostname, tolerateTimeSkewness)
+
+	return hello, err
+}
+
+// ReadClientHelloMulti is the same as ReadClientHello but it verifies a
+// client hello against a list of secrets. Secrets are tried in the given
+// order, an index of the first matching secret is returned.
+func ReadClientHelloMulti(
+	conn net.Conn,
+	secrets [][]byte,
+	hostname string,
+	tolerateTimeSkewness time.Duration,
+) (*ClientHello, int, error) {
 	// This is how FakeTLS is organized:
 	//  1. We create sha256 HMAC with a given secret
 	//  2. We dump there a whole TLS frame except of the fact that random
@@ -59,48 +77,58 @@ func ReadClientHello(
 	//     this message was created.
 	clientHelloCopy, handshakeReader, err := parseClientHello(conn)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read client hello: %w", err)
+		return nil, 0, fmt.Errorf("cannot read client hello: %w", err)
 	}
 
 	hello, err := parseHandshake(handshakeReader)
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse handshake: %w", err)
+		return nil, 0, fmt.Errorf("cannot parse handshake: %w", err)
 	}
 
 	sniHostnames, err := parseSNI(handshakeReader)
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse SNI: %w", err)
+		return nil, 0, fmt.Errorf("cannot parse SNI: %w", err)
 	}
 
 	if !slices.Contains(sniHostnames, hostname) {
-		return nil, fmt.Errorf("cannot find %s in %v", hostname, sniHostnames)
+		return nil, 0, fmt.Errorf("%w: cannot find %s in %v", ErrSNIMismatch, hostname, sniHostnames)
 	}
 
-	digest := hmac.New(sha256.New, secret)
-	// we write a copy of the handshake with client random all nullified.
-	digest.Write(clientHelloCopy.Next(RandomOffset))
-	clientHelloCopy.Next(RandomLen)
-	digest.Write(emptyRandom[:])
-	digest.Write(clientHelloCopy.Bytes())
+	// we compute a digest of the handshake with client random all nullified.
+	payload := clientHelloCopy.Bytes()
+	payloadPrefix := payload[:RandomOffset]
+	payloadSuffix := payload[RandomOffset+RandomLen:]
 
-	computed := digest.Sum(nil)
+	for idx, secret := range secrets {
+		digest := hmac.New(sha256.New, secret)
+		digest.Write(payloadPrefix)
+		digest.Write(emptyRandom[:])
+		digest.Write(payloadSuffix)
 
-	for i := range RandomLen {
-		computed[i] ^= hello.Random[i]
-	}
+		computed := digest.Sum(nil)
 
-	if subtle.ConstantTimeCompare(emptyRandom[:RandomLen-4], computed[:RandomLen-4]) != 1 {
-		return nil, ErrBadDigest
-	}
+		for i := range RandomLen {
+			computed[i] ^= hello.Random[i]
+		}
+
+		if subtle.ConstantTimeCompare(emptyRandom[:RandomLen-4], computed[:RandomLen-4]) != 1 {
+			continue
+		}
 
-	timestamp := int64(binary.LittleEndian.Uint32(computed[RandomLen-4:]))
-	createdAt := time.Unix(timestamp, 0)
+		timestamp := int64(binary.LittleEndian.Uint32(computed[RandomLen-4:]))
+		hello.Timestamp = time.Unix(timestamp, 0)
 
-	if tdiff := time.Since(createdAt).Abs(); tdiff > tolerateTimeSkewness {
-		return nil, fmt.Errorf("timestamp %q is too old %s", createdAt, tdiff)
+		if skew := time.Until(hello.Timestamp); skew.Abs() > tolerateTimeSkewness {
+			return nil, 0, &TimeSkewError{
+				Timestamp: hello.Timestamp,
+				Skew:      skew,
+			}
+		}
+
+		return hello, idx, nil
 	}
 
-	return hello, nil
+	return nil, 0, ErrBadDigest
 }
 
 func parseHandshake(r io.Reader) (*ClientHello, error) {
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/9seconds/mtg/v2/mtglib"
 	"github.com/9seconds/mtg/v2/mtglib/internal/tls/fake"
@@ -105,6 +106,7 @@ func (suite *ParseClientHelloSnapshotTestSuite) TestSnapshotOk() {
 			assert.Equal(t, snapshot.GetRandom(), hello.Random[:])
 			assert.Equal(t, snapshot.GetSessionID(), hello.SessionID)
 			assert.Equal(t, snapshot.GetCipherSuite(), hello.CipherSuite)
+			assert.EqualValues(t, snapshot.Time, hello.Timestamp.Unix())
 		})
 	}
 }
@@ -141,6 +143,97 @@ func (suite *ParseClientHelloSnapshotTestSuite) TestSnapshotBad() {
 	}
 }
 
+func (suite *ParseClientHelloSnapshotTestSuite) TestSnapshotMultiSecrets() {
+	files, err := os.ReadDir("testdata")
+	require.NoError(suite.T(), err)
+
+	otherSecret := mtglib.GenerateSecret(suite.secret.Host)
+
+	for _, v := range files {
+		if !strings.HasPrefix(v.Name(), "client-hello-ok") {
+			continue
+		}
+
+		path := filepath.Join("testdata", v.Name())
+
+		suite.T().Run(v.Name(), func(t *testing.T) {
+			fileData, err := os.ReadFile(path)
+			assert.NoError(t, err)
+
+			snapshot := &clientHelloSnapshot{}
+			assert.NoError(t, json.Unmarshal(fileData, snapshot))
+
+			connMock := suite.makeConn(snapshot.GetFull())
+			defer connMock.AssertExpectations(t)
+
+			hello, idx, err := fake.ReadClientHelloMulti(
+				connMock,
+				[][]byte{otherSecret.Key[:], suite.secret.Key[:]},
+				suite.secret.Host,
+				TolerateTime,
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, idx)
+			assert.Equal(t, snapshot.GetSessionID(), hello.SessionID)
+
+			connMock = suite.makeConn(snapshot.GetFull())
+			defer connMock.AssertExpectations(t)
+
+			_, _, err = fake.ReadClientHelloMulti(
+				connMock,
+				[][]byte{otherSecret.Key[:]},
+				suite.secret.Host,
+				TolerateTime,
+			)
+			assert.ErrorIs(t, err, fake.ErrBadDigest)
+		})
+	}
+}
+
+func (suite *ParseClientHelloSnapshotTestSuite) readOkSnapshot() *clientHelloSnapshot {
+	files, err := filepath.Glob(filepath.Join("testdata", "client-hello-ok*"))
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(files)
+
+	fileData, err := os.ReadFile(files[0])
+	suite.Require().NoError(err)
+
+	snapshot := &clientHelloSnapshot{}
+	suite.Require().NoError(json.Unmarshal(fileData, snapshot))
+
+	return snapshot
+}
+
+func (suite *ParseClientHelloSnapshotTestSuite) TestSNIMismatch() {
+	snapshot := suite.readOkSnapshot()
+
+	_, err := fake.ReadClientHello(
+		suite.makeConn(snapshot.GetFull()),
+		suite.secret.Key[:],
+		"example.com",
+		TolerateTime,
+	)
+	suite.ErrorIs(err, fake.ErrSNIMismatch)
+}
+
+func (suite *ParseClientHelloSnapshotTestSuite) TestTimeSkew() {
+	snapshot := suite.readOkSnapshot()
+
+	_, err := fake.ReadClientHello(
+		suite.makeConn(snapshot.GetFull()),
+		suite.secret.Key[:],
+		suite.secret.Host,
+		time.Second,
+	)
+	suite.ErrorIs(err, fake.ErrTimeSkew)
+
+	skewErr := &fake.TimeSkewError{}
+	suite.Require().ErrorAs(err, &skewErr)
+	suite.Negative(skewErr.Skew)
+	suite.Equal(int64(snapshot.Time), skewErr.Timestamp.Unix())
+}
+
 func TestParseClientHelloSnapshot(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, &ParseClientHelloSnapshotTestSuite{})
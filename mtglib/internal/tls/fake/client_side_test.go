@@ -82,6 +82,7 @@ func (suite *ParseClientHello_TLSHeaderTestSuite) TestUnknownRecord() {
 
 	_, err := fake.ReadClientHello(suite.connMock, suite.secret.Key[:], suite.secret.Host, TolerateTime)
 	suite.ErrorContains(err, "unexpected record type 0xa")
+	suite.ErrorIs(err, fake.ErrNotTLS)
 }
 
 func (suite *ParseClientHello_TLSHeaderTestSuite) TestUnknownProtocolVersion() {
@@ -2,6 +2,37 @@ package fake
 
 import (
 	"errors"
+	"fmt"
+	"time"
 )
 
-var ErrBadDigest = errors.New("incorrect client random")
+var (
+	ErrBadDigest = errors.New("incorrect client random")
+
+	// ErrNotTLS is returned if a client has sent something which is not
+	// a TLS record at all.
+	ErrNotTLS = errors.New("not a TLS record")
+
+	// ErrSNIMismatch is returned if client hello has no expected
+	// hostname in SNI.
+	ErrSNIMismatch = errors.New("SNI mismatch")
+
+	// ErrTimeSkew is returned if a timestamp of client hello is out of
+	// tolerated time skewness.
+	ErrTimeSkew = errors.New("time skew")
+)
+
+// TimeSkewError is returned if client hello is signed by a known secret
+// but its timestamp is out of tolerated time skewness.
+type TimeSkewError struct {
+	Timestamp time.Time
+	Skew      time.Duration
+}
+
+func (t *TimeSkewError) Error() string {
+	return fmt.Sprintf("%v: timestamp %q is too old %s", ErrTimeSkew, t.Timestamp, t.Skew.Abs())
+}
+
+func (t *TimeSkewError) Is(target error) bool {
+	return target == ErrTimeSkew //nolint: errorlint
+}
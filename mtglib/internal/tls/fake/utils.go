@@ -108,6 +108,11 @@ func (f *fragmentedHandshakeReader) parseNextFragment() error {
 	}
 
 	if header[0] != tls.TypeHandshake {
+		// the very first record tells if a client talks TLS at all
+		if f.readFragments == 0 {
+			return fmt.Errorf("%w: unexpected record type %#x", ErrNotTLS, header[0])
+		}
+
 		return fmt.Errorf("unexpected record type %#x", header[0])
 	}
 
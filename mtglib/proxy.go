@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
-	"strconv"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/9seconds/mtg/v2/essentials"
+	"github.com/9seconds/mtg/v2/mtglib/internal/clientlimit"
 	"github.com/9seconds/mtg/v2/mtglib/internal/dc"
 	"github.com/9seconds/mtg/v2/mtglib/internal/doppel"
+	"github.com/9seconds/mtg/v2/mtglib/internal/middleproxy"
 	"github.com/9seconds/mtg/v2/mtglib/internal/obfuscation"
 	"github.com/9seconds/mtg/v2/mtglib/internal/relay"
 	"github.com/9seconds/mtg/v2/mtglib/internal/tls"
@@ -24,25 +28,27 @@ type Proxy struct {
 	ctx             context.Context
 	ctxCancel       context.CancelFunc
 	streamWaitGroup sync.WaitGroup
+	reloadMutex     sync.Mutex
+	streams         sync.Map
+
+	settings             atomic.Pointer[proxySettings]
+	workerPool           *ants.PoolWithFunc
+	clientLimiter        *clientlimit.Limiter
+	limitedFrontingSlots chan struct{}
+	telegram             *dc.Telegram
+	configUpdater        *dc.PublicConfigUpdater
+	secretUpdater        *dc.ProxySecretUpdater
+	prober               *dc.Prober
+	warmPool             *dc.Pool
+	middleProxy          *middleproxy.Opts
+	doppelGanger         *doppel.Ganger
+	doppelGangerURLs     []string
 
-	allowFallbackOnUnknownDC    bool
-	tolerateTimeSkewness        time.Duration
-	idleTimeout                 time.Duration
-	handshakeTimeout            time.Duration
-	domainFrontingPort          int
-	domainFrontingIP            string
-	domainFrontingProxyProtocol bool
-	workerPool                  *ants.PoolWithFunc
-	telegram                    *dc.Telegram
-	configUpdater               *dc.PublicConfigUpdater
-	doppelGanger                *doppel.Ganger
-	clientObfuscatror           obfuscation.Obfuscator
-
-	secret          Secret
 	network         Network
 	antiReplayCache AntiReplayCache
-	blocklist       IPBlocklist
-	allowlist       IPBlocklist
+	ipBanlist       IPBanlist
+	asnLookup       ASNLookup
+	trafficQuota    TrafficQuota
 	eventStream     EventStream
 	logger          Logger
 }
@@ -50,24 +56,60 @@ type Proxy struct {
 // DomainFrontingAddress returns a host:port pair for a fronting domain.
 // If DomainFrontingIP is set, it is used instead of resolving the hostname.
 func (p *Proxy) DomainFrontingAddress() string {
-	host := p.secret.Host
-	if p.domainFrontingIP != "" {
-		host = p.domainFrontingIP
+	return p.settings.Load().domainFrontingAddress()
+}
+
+// CloseStream closes an active stream with a given id. It returns false
+// if there is no such stream.
+func (p *Proxy) CloseStream(streamID string) bool {
+	cancel, ok := p.streams.Load(streamID)
+	if !ok {
+		return false
 	}
 
-	return net.JoinHostPort(host, strconv.Itoa(p.domainFrontingPort))
+	p.logger.BindStr("stream-id", streamID).Info("stream is closed on request")
+	cancel.(context.CancelFunc)() //nolint: forcetypeassert
+
+	return true
+}
+
+// DoppelGangerState returns a snapshot of what doppelganger has learned
+// about a fronting domain.
+func (p *Proxy) DoppelGangerState() DoppelGangerState {
+	state := p.doppelGanger.State()
+
+	return DoppelGangerState{
+		URLs:               slices.Clone(state.URLs),
+		DRS:                state.DRS,
+		CollectedDurations: state.Durations,
+		CollectedCertSizes: state.CertSizes,
+		Calibrated:         state.Calibrated,
+		WeibullK:           state.K,
+		WeibullLambda:      state.Lambda,
+		NoiseMean:          state.Noise.Mean,
+		NoiseJitter:        state.Noise.Jitter,
+	}
 }
 
 // ServeConn serves a connection. We do not check IP blocklist and concurrency
 // limit here.
 func (p *Proxy) ServeConn(conn essentials.Conn) {
+	p.serveConn(conn, false)
+}
+
+func (p *Proxy) serveConn(conn essentials.Conn, domainFrontingOnly bool) {
 	p.streamWaitGroup.Add(1)
 	defer p.streamWaitGroup.Done()
 
 	ctx := newStreamContext(p.ctx, p.logger, conn)
 	defer ctx.Close()
 
-	if err := ctx.clientConn.SetDeadline(time.Now().Add(p.handshakeTimeout)); err != nil {
+	p.streams.Store(ctx.streamID, ctx.ctxCancel)
+	defer p.streams.Delete(ctx.streamID)
+
+	ctx.settings = p.settings.Load()
+
+	if err := ctx.clientConn.SetDeadline(time.Now().Add(ctx.settings.handshakeTimeout)); err != nil {
 		ctx.logger.WarningError("cannot set handshake timeout", err)
 		return
 	}
@@ -77,18 +119,31 @@ func (p *Proxy) ServeConn(conn essentials.Conn) {
 	})
 	defer stop()
 
+	startedAt := time.Now()
+
 	p.eventStream.Send(ctx, NewEventStart(ctx.streamID, ctx.ClientIP()))
 	ctx.logger.Info("Stream has been started")
 
 	defer func() {
-		p.eventStream.Send(ctx, NewEventFinish(ctx.streamID))
+		evt := NewEventFinish(ctx.streamID)
+		evt.secretName = ctx.secretName
+		evt.Duration = time.Since(startedAt)
+
+		p.eventStream.Send(ctx, evt)
 		ctx.logger.Info("Stream has been finished")
 	}()
 
+	if domainFrontingOnly {
+		p.doDomainFronting(ctx, newConnRewind(ctx.clientConn))
+		return
+	}
+
 	if !p.doFakeTLSHandshake(ctx) {
 		return
 	}
 
+	fakeTLSDuration := time.Since(startedAt)
+
 	clientConn, err := p.doppelGanger.NewConn(ctx.clientConn)
 	if err != nil {
 		ctx.logger.InfoError("cannot wrap into doppelganger connection", err)
@@ -97,12 +152,20 @@ func (p *Proxy) ServeConn(conn essentials.Conn) {
 	defer clientConn.Stop()
 
 	ctx.clientConn = clientConn
+	obfuscatedStartedAt := time.Now()
 
 	if err := p.doObfuscatedHandshake(ctx); err != nil {
 		ctx.logger.InfoError("obfuscated handshake is failed", err)
+		p.sendHandshakeFailed(ctx, getHandshakeFailureReason(err))
+
 		return
 	}
 
+	handshakeEvt := NewEventHandshakeSucceeded(ctx.streamID, fakeTLSDuration, time.Since(obfuscatedStartedAt))
+	handshakeEvt.secretName = ctx.secretName
+
+	p.eventStream.Send(ctx, handshakeEvt)
+
 	if err := ctx.clientConn.SetDeadline(time.Time{}); err != nil {
 		ctx.logger.WarningError("cannot set deadline", err)
 		return
@@ -113,7 +176,12 @@ func (p *Proxy) ServeConn(conn essentials.Conn) {
 		return
 	}
 
-	tracker := newIdleTracker(p.idleTimeout)
+	stopExpiry := ctx.watchSecretExpiry()
+	defer stopExpiry()
+
+	go watchTrafficQuota(ctx, p.trafficQuota, trafficQuotaCheckEach)
+
+	tracker := newIdleTracker(ctx.settings.idleTimeout)
 
 	relay.Relay(
 		ctx,
@@ -141,24 +209,68 @@ func (p *Proxy) Serve(listener net.Listener) error {
 
 		ipAddr := conn.RemoteAddr().(*net.TCPAddr).IP //nolint: forcetypeassert
 		logger := p.logger.BindStr("ip", ipAddr.String())
+		settings := p.settings.Load()
 
-		if !p.allowlist.Contains(ipAddr) {
+		if !settings.allowlist.Contains(ipAddr) {
 			conn.Close() //nolint: errcheck
 			logger.Info("ip was rejected by allowlist")
-			p.eventStream.Send(p.ctx, NewEventIPAllowlisted(ipAddr))
+			p.sendIPBlocklisted(NewEventIPAllowlisted(ipAddr))
+
+			continue
+		}
+
+		if p.ipBanlist.Banned(ipAddr) {
+			conn.Close() //nolint: errcheck
+			logger.Info("ip is banned")
+			p.sendIPBlocklisted(NewEventIPBanlisted(ipAddr))
 
 			continue
 		}
 
-		if p.blocklist.Contains(ipAddr) {
+		if settings.blocklist.Contains(ipAddr) {
 			conn.Close() //nolint: errcheck
 			logger.Info("ip was blacklisted")
-			p.eventStream.Send(p.ctx, NewEventIPBlocklisted(ipAddr))
+			p.sendIPBlocklisted(NewEventIPBlocklisted(ipAddr))
 
 			continue
 		}
 
-		err = p.workerPool.Invoke(conn)
+		accepted := acceptedConn{
+			conn:    conn,
+			release: func() {},
+		}
+
+		if release, err := p.clientLimiter.Acquire(ipToAddr(ipAddr)); err == nil {
+			accepted.release = release
+		} else {
+			reason := getRateLimitReason(err)
+
+			logger.BindStr("reason", string(reason)).Info("client has exceeded its limits")
+			p.eventStream.Send(p.ctx, NewEventRateLimited(ipAddr, reason))
+
+			if !settings.clientLimitsDomainFronting {
+				conn.Close() //nolint: errcheck
+
+				continue
+			}
+
+			select {
+			case p.limitedFrontingSlots <- struct{}{}:
+				accepted.release = func() { <-p.limitedFrontingSlots }
+			default:
+				conn.Close() //nolint: errcheck
+				logger.Info("too many limited clients are routed to fronting domain")
+
+				continue
+			}
+
+			accepted.domainFrontingOnly = true
+		}
+
+		err = p.workerPool.Invoke(accepted)
+		if err != nil {
+			accepted.release()
+		}
 
 		switch {
 		case err == nil:
@@ -179,38 +291,137 @@ func (p *Proxy) Shutdown() {
 	p.streamWaitGroup.Wait()
 	p.workerPool.Release()
 	p.configUpdater.Wait()
+	p.secretUpdater.Wait()
+	p.prober.Wait()
+
+	if p.warmPool != nil {
+		p.warmPool.Wait()
+	}
 	p.doppelGanger.Shutdown()
 
-	p.allowlist.Shutdown()
-	p.blocklist.Shutdown()
+	// reload can swap settings concurrently, we need to get the last ones.
+	p.reloadMutex.Lock()
+	settings := p.settings.Load()
+	p.reloadMutex.Unlock()
+
+	settings.allowlist.Shutdown()
+	settings.blocklist.Shutdown()
+	p.ipBanlist.Shutdown()
+	p.trafficQuota.Shutdown()
+}
+
+// Reload applies new settings to a running proxy. Neither listener nor
+// active streams are interrupted: streams keep settings they were started
+// with, new settings are used by new streams only.
+//
+// Reload applies secrets, IP lists, timeouts, domain fronting settings,
+// concurrency, client limits and doppelganger URLs. All other settings
+// are ignored, they can be changed only with a new proxy. IP lists which
+// were replaced are shut down.
+func (p *Proxy) Reload(opts ProxyOpts) error {
+	if err := opts.valid(); err != nil {
+		return fmt.Errorf("invalid settings: %w", err)
+	}
+
+	p.reloadMutex.Lock()
+	defer p.reloadMutex.Unlock()
+
+	select {
+	case <-p.ctx.Done():
+		return ErrProxyClosed
+	default:
+	}
+
+	previous := p.settings.Swap(newProxySettings(opts))
+
+	p.workerPool.Tune(opts.getConcurrency())
+	p.clientLimiter.SetLimits(opts.ClientLimits.toInternal())
+
+	if !slices.Equal(p.doppelGangerURLs, opts.DoppelGangerURLs) {
+		p.doppelGangerURLs = slices.Clone(opts.DoppelGangerURLs)
+		p.doppelGanger.SetURLs(p.doppelGangerURLs)
+	}
+
+	if previous.allowlist != opts.IPAllowlist {
+		previous.allowlist.Shutdown()
+	}
+
+	if previous.blocklist != opts.IPBlocklist {
+		previous.blocklist.Shutdown()
+	}
+
+	return nil
 }
 
 func (p *Proxy) doFakeTLSHandshake(ctx *streamContext) bool {
 	rewind := newConnRewind(ctx.clientConn)
 
-	clientHello, err := fake.ReadClientHello(
+	settings := ctx.settings
+
+	clientHello, secretIdx, err := fake.ReadClientHelloMulti(
 		rewind,
-		p.secret.Key[:],
-		p.secret.Host,
-		p.tolerateTimeSkewness,
+		settings.secretKeys,
+		settings.secrets[0].secret.Host,
+		settings.tolerateTimeSkewness,
 	)
 	if err != nil {
+		if skewErr := (*fake.TimeSkewError)(nil); errors.As(err, &skewErr) {
+			p.eventStream.Send(p.ctx, NewEventClockSkew(ctx.streamID, skewErr.Skew, false))
+		}
+
 		p.logger.InfoError("cannot read client hello", err)
+		p.sendHandshakeFailed(ctx, getHandshakeFailureReason(err))
 		p.doDomainFronting(ctx, rewind)
 		return false
 	}
 
+	if secret := settings.secrets[secretIdx]; !secret.secret.ActiveAt(time.Now()) {
+		p.logger.BindStr("secret", secret.name).Info("secret is outside of its validity window")
+		p.sendHandshakeFailed(ctx, HandshakeFailureSecretInactive)
+		p.doDomainFronting(ctx, rewind)
+		return false
+	}
+
+	ctx.secretName = settings.secrets[secretIdx].name
+	ctx.secretKey = settings.secretKeys[secretIdx]
+	ctx.secretExpiry = settings.secrets[secretIdx].secret.NotAfter
+	ctx.logger = ctx.logger.BindStr("secret", ctx.secretName)
+
+	skewEvt := NewEventClockSkew(ctx.streamID, time.Until(clientHello.Timestamp), true)
+	skewEvt.secretName = ctx.secretName
+
+	p.eventStream.Send(p.ctx, skewEvt)
+
 	if p.antiReplayCache.SeenBefore(clientHello.SessionID) {
+		evt := NewEventReplayAttack(ctx.streamID)
+		evt.secretName = ctx.secretName
+
 		p.logger.Warning("replay attack has been detected!")
-		p.eventStream.Send(p.ctx, NewEventReplayAttack(ctx.streamID))
+		p.eventStream.Send(p.ctx, evt)
+		p.sendHandshakeFailed(ctx, HandshakeFailureReplay)
+		p.doDomainFronting(ctx, rewind)
+		return false
+	}
+
+	if !p.trafficQuota.Allowed(ctx.secretName) {
+		ctx.logger.Info("traffic quota of the secret is exhausted")
+		p.sendHandshakeFailed(ctx, HandshakeFailureQuotaExceeded)
 		p.doDomainFronting(ctx, rewind)
 		return false
 	}
 
+	if ctx.secretName == PreviousSecretName {
+		evt := NewEventPreviousSecretUsed(ctx.streamID)
+		evt.secretName = ctx.secretName
+
+		ctx.logger.Info("client has used a previous secret")
+		p.eventStream.Send(p.ctx, evt)
+	}
+
 	gangerNoise := p.doppelGanger.NoiseParams()
 	noiseParams := fake.NoiseParams{Mean: gangerNoise.Mean, Jitter: gangerNoise.Jitter}
 
-	if err := fake.SendServerHello(ctx.clientConn, p.secret.Key[:], clientHello, noiseParams); err != nil {
+	if err := fake.SendServerHello(ctx.clientConn, ctx.secretKey, clientHello, noiseParams); err != nil {
 		p.logger.InfoError("cannot send welcome packet", err)
 		return false
 	}
@@ -220,9 +431,37 @@ func (p *Proxy) doFakeTLSHandshake(ctx *streamContext) bool {
 	return true
 }
 
+func (p *Proxy) sendIPBlocklisted(evt EventIPBlocklisted) {
+	evt.ASN = p.asnLookup.LookupASN(evt.RemoteIP)
+
+	p.eventStream.Send(p.ctx, evt)
+}
+
+func (p *Proxy) sendHandshakeFailed(ctx *streamContext, reason HandshakeFailureReason) {
+	evt := NewEventHandshakeFailed(ctx.streamID, ctx.ClientIP(), reason)
+	evt.secretName = ctx.secretName
+
+	p.eventStream.Send(p.ctx, evt)
+
+	if banFor := p.ipBanlist.Offend(evt.RemoteIP, reason); banFor > 0 {
+		ctx.logger.BindStr("ban_for", banFor.String()).Info("ip has been banned")
+		p.eventStream.Send(p.ctx, NewEventIPBanned(evt.RemoteIP, reason, banFor))
+	}
+}
+
 func (p *Proxy) doObfuscatedHandshake(ctx *streamContext) error {
-	dc, conn, err := p.clientObfuscatror.ReadHandshake(ctx.clientConn)
+	obfuscator := obfuscation.Obfuscator{
+		Secret: ctx.secretKey,
+	}
+
+	dc, conn, err := obfuscator.ReadHandshake(ctx.clientConn)
 	if err != nil {
+		// a handshake frame is not parsed, it is truncated only if
+		// client has gone.
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			err = fmt.Errorf("%w: %w", errClientClosed, err)
+		}
+
 		return fmt.Errorf("cannot process client handshake: %w", err)
 	}
 
@@ -235,46 +474,58 @@ func (p *Proxy) doObfuscatedHandshake(ctx *streamContext) error {
 
 func (p *Proxy) doTelegramCall(ctx *streamContext) error {
 	dcid := ctx.dc
+	getAddresses := p.telegram.GetAddresses
+
+	if p.middleProxy != nil {
+		if p.telegram.GetProxySecret() == nil {
+			return errors.New("secret of middle proxies is not fetched yet")
+		}
+
+		getAddresses = p.telegram.GetMiddleProxyAddresses
+	}
 
-	addresses := p.telegram.GetAddresses(dcid)
-	if len(addresses) == 0 && p.allowFallbackOnUnknownDC {
+	addresses := getAddresses(dcid)
+	if len(addresses) == 0 && ctx.settings.allowFallbackOnUnknownDC {
 		ctx.logger = ctx.logger.BindInt("original_dc", dcid)
 		ctx.logger.Warning("unknown DC, fallbacks")
 		ctx.dc = dc.DefaultDC
-		addresses = p.telegram.GetAddresses(dc.DefaultDC)
+		addresses = getAddresses(dc.DefaultDC)
 	}
 
-	var (
-		conn      essentials.Conn
-		err       error
-		foundAddr dc.Addr
-	)
-
-	for _, addr := range addresses {
-		conn, err = p.network.Dial(addr.Network, addr.Address)
-		if err == nil {
-			foundAddr = addr
-			break
-		}
+	if len(addresses) == 0 {
+		return fmt.Errorf("no available addresses for DC %d", ctx.dc)
 	}
+
+	conn, foundAddr, dialDuration, err := p.dialTelegram(ctx, addresses)
 	if err != nil {
 		return fmt.Errorf("no addresses to call: %w", err)
 	}
-	if conn == nil {
-		return fmt.Errorf("no available addresses for DC %d", ctx.dc)
+
+	var tgConn essentials.Conn
+
+	if p.middleProxy != nil {
+		tgConn, err = p.doMiddleProxyHandshake(ctx, conn)
+	} else {
+		tgConn, err = foundAddr.Obfuscator.SendHandshake(conn, ctx.dc)
 	}
 
-	tgConn, err := foundAddr.Obfuscator.SendHandshake(conn, ctx.dc)
 	if err != nil {
 		conn.Close() // nolint: errcheck
 		return fmt.Errorf("cannot perform server handshake: %w", err)
 	}
 
-	ctx.telegramConn = connTraffic{
-		Conn:     tgConn,
-		streamID: ctx.streamID,
-		stream:   p.eventStream,
-		ctx:      ctx,
+	ctx.telegramConn = connTrafficQuota{
+		Conn: connTraffic{
+			Conn:       tgConn,
+			streamID:   ctx.streamID,
+			secretName: ctx.secretName,
+			stream:     p.eventStream,
+			ctx:        ctx,
+		},
+		secretName: ctx.secretName,
+		quota:      p.trafficQuota,
+		ctx:        ctx,
+		ctxCancel:  ctx.ctxCancel,
 	}
 
 	telegramHost, _, err := net.SplitHostPort(foundAddr.Address)
@@ -284,21 +535,91 @@ func (p *Proxy) doTelegramCall(ctx *streamContext) error {
 		return fmt.Errorf("cannot parse telegram address %s: %w", foundAddr.Address, err)
 	}
 
-	p.eventStream.Send(ctx,
-		NewEventConnectedToDC(ctx.streamID,
-			net.ParseIP(telegramHost),
-			ctx.dc),
-	)
+	evt := NewEventConnectedToDC(ctx.streamID, net.ParseIP(telegramHost), ctx.dc)
+	evt.secretName = ctx.secretName
+	evt.DialDuration = dialDuration
+
+	p.eventStream.Send(ctx, evt)
 
 	return nil
 }
 
+// dialTelegram takes a pre-dialed connection from the warm pool if it is
+// possible. Otherwise, it dials a new one. It returns a connection to
+// Telegram and a time spent on dialing which is zero for pooled
+// connections.
+func (p *Proxy) dialTelegram(
+	ctx *streamContext,
+	addresses []dc.Addr,
+) (essentials.Conn, dc.Addr, time.Duration, error) {
+	if p.warmPool != nil {
+		conn, addr, ok := p.warmPool.Get(ctx.dc)
+
+		evt := NewEventWarmPool(ctx.streamID, ctx.dc, ok)
+		evt.secretName = ctx.secretName
+
+		p.eventStream.Send(ctx, evt)
+
+		if ok {
+			return conn, addr, 0, nil
+		}
+	}
+
+	startedAt := time.Now()
+	conn, addr, err := dc.Dial(ctx, p.network, addresses, dc.ConnectionAttemptDelay)
+
+	return conn, addr, time.Since(startedAt), err //nolint: wrapcheck
+}
+
+func (p *Proxy) doMiddleProxyHandshake(ctx *streamContext, conn essentials.Conn) (essentials.Conn, error) {
+	opts := *p.middleProxy
+	opts.Secret = p.telegram.GetProxySecret()
+	opts.ClientAddr = ctx.clientConn.RemoteAddr()
+	opts.ProxyAddr = ctx.clientConn.LocalAddr()
+
+	if err := conn.SetDeadline(time.Now().Add(ctx.settings.handshakeTimeout)); err != nil {
+		return nil, fmt.Errorf("cannot set handshake timeout: %w", err)
+	}
+
+	tgConn, err := middleproxy.NewConn(conn, opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot perform middle proxy handshake: %w", err)
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, fmt.Errorf("cannot reset deadline: %w", err)
+	}
+
+	return tgConn, nil
+}
+
+func (p *Proxy) sendProbeResult(result dc.ProbeResult) {
+	host, _, err := net.SplitHostPort(result.Address)
+	if err != nil {
+		return
+	}
+
+	// overrides can be hostnames, they are not reported.
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return
+	}
+
+	evt := NewEventTelegramProbe(ip, result.DC, result.RTT, result.FailureRate)
+	evt.Forgotten = result.Forgotten
+
+	p.eventStream.Send(p.ctx, evt)
+}
+
 func (p *Proxy) doDomainFronting(ctx *streamContext, conn *connRewind) {
-	p.eventStream.Send(p.ctx, NewEventDomainFronting(ctx.streamID))
+	evt := NewEventDomainFronting(ctx.streamID)
+	evt.secretName = ctx.secretName
+
+	p.eventStream.Send(p.ctx, evt)
 	conn.Rewind()
 
 	nativeDialer := p.network.NativeDialer()
-	fConn, err := nativeDialer.DialContext(ctx, "tcp", p.DomainFrontingAddress())
+	fConn, err := nativeDialer.DialContext(ctx, "tcp", ctx.settings.domainFrontingAddress())
 	if err != nil {
 		p.logger.WarningError("cannot dial to the fronting domain", err)
 
@@ -307,18 +628,19 @@ func (p *Proxy) doDomainFronting(ctx *streamContext, conn *connRewind) {
 
 	frontConn := essentials.WrapNetConn(fConn)
 
-	if p.domainFrontingProxyProtocol {
+	if ctx.settings.domainFrontingProxyProtocol {
 		frontConn = newConnProxyProtocol(ctx.clientConn, frontConn)
 	}
 
 	frontConn = connTraffic{
-		Conn:     frontConn,
-		ctx:      ctx,
-		streamID: ctx.streamID,
-		stream:   p.eventStream,
+		Conn:       frontConn,
+		ctx:        ctx,
+		streamID:   ctx.streamID,
+		secretName: ctx.secretName,
+		stream:     p.eventStream,
 	}
 
-	tracker := newIdleTracker(p.idleTimeout)
+	tracker := newIdleTracker(ctx.settings.idleTimeout)
 
 	relay.Relay(
 		ctx,
@@ -334,7 +656,12 @@ func NewProxy(opts ProxyOpts) (*Proxy, error) {
 		return nil, fmt.Errorf("invalid settings: %w", err)
 	}
 
-	tg, err := dc.New(opts.getPreferIP())
+	tg, err := dc.New(
+		opts.getPreferIP(),
+		opts.AutoUpdateFirst,
+		opts.DCOverrides,
+		opts.ReplaceOverriddenDCs,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("cannot build telegram dc fetcher: %w", err)
 	}
@@ -342,24 +669,20 @@ func NewProxy(opts ProxyOpts) (*Proxy, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	logger := opts.getLogger("proxy")
 	updatersLogger := logger.Named("telegram-updaters")
+	httpClient := opts.Network.MakeHTTPClient(nil)
 
 	proxy := &Proxy{
-		ctx:                      ctx,
-		ctxCancel:                cancel,
-		secret:                   opts.Secret,
-		network:                  opts.Network,
-		antiReplayCache:          opts.AntiReplayCache,
-		blocklist:                opts.IPBlocklist,
-		allowlist:                opts.IPAllowlist,
-		eventStream:              opts.EventStream,
-		logger:                   logger,
-		domainFrontingPort:       opts.getDomainFrontingPort(),
-		domainFrontingIP:         opts.DomainFrontingIP,
-		tolerateTimeSkewness:     opts.getTolerateTimeSkewness(),
-		idleTimeout:              opts.getIdleTimeout(),
-		handshakeTimeout:         opts.getHandshakeTimeout(),
-		allowFallbackOnUnknownDC: opts.AllowFallbackOnUnknownDC,
-		telegram:                 tg,
+		ctx:              ctx,
+		ctxCancel:        cancel,
+		network:          opts.Network,
+		antiReplayCache:  opts.AntiReplayCache,
+		ipBanlist:        opts.IPBanlist,
+		asnLookup:        opts.getASNLookup(),
+		trafficQuota:     opts.TrafficQuota,
+		eventStream:      opts.EventStream,
+		logger:           logger,
+		telegram:         tg,
+		doppelGangerURLs: slices.Clone(opts.DoppelGangerURLs),
 		doppelGanger: doppel.NewGanger(
 			ctx,
 			opts.Network,
@@ -372,24 +695,66 @@ func NewProxy(opts ProxyOpts) (*Proxy, error) {
 		configUpdater: dc.NewPublicConfigUpdater(
 			tg,
 			updatersLogger.Named("public-config"),
-			opts.Network.MakeHTTPClient(nil),
+			httpClient,
 		),
-		clientObfuscatror: obfuscation.Obfuscator{
-			Secret: opts.Secret.Key[:],
-		},
-		domainFrontingProxyProtocol: opts.DomainFrontingProxyProtocol,
+		secretUpdater: dc.NewProxySecretUpdater(
+			tg,
+			updatersLogger.Named("proxy-secret"),
+			httpClient,
+		),
+		prober: dc.NewProber(
+			tg,
+			updatersLogger.Named("prober"),
+			opts.Network,
+			opts.DCProbeEach,
+		),
+		middleProxy:          opts.getMiddleProxy(),
+		clientLimiter:        clientlimit.New(opts.ClientLimits.toInternal()),
+		limitedFrontingSlots: make(chan struct{}, LimitedFrontingConcurrency),
 	}
 
+	proxy.settings.Store(newProxySettings(opts))
 	proxy.doppelGanger.Run()
 
-	if opts.AutoUpdate {
+	// middle proxies are known only from a public config.
+	if opts.AutoUpdate || opts.UseMiddleProxy {
 		proxy.configUpdater.Run(ctx, dc.PublicConfigUpdateURLv4, "tcp4")
 		proxy.configUpdater.Run(ctx, dc.PublicConfigUpdateURLv6, "tcp6")
 	}
 
+	if opts.UseMiddleProxy {
+		proxy.secretUpdater.Run(ctx, dc.ProxySecretUpdateURL)
+	}
+
+	if opts.DCProbeEach > 0 {
+		proxy.prober.Run(ctx, proxy.sendProbeResult)
+	}
+
+	if opts.WarmPoolSize > 0 {
+		getAddresses := tg.GetAddresses
+		if opts.UseMiddleProxy {
+			getAddresses = tg.GetMiddleProxyAddresses
+		}
+
+		proxy.warmPool = dc.NewPool(
+			updatersLogger.Named("warm-pool"),
+			opts.Network,
+			getAddresses,
+			int(opts.WarmPoolSize),
+			opts.getWarmPoolMaxIdle(),
+		)
+		proxy.warmPool.Run(ctx)
+	}
+
 	pool, err := ants.NewPoolWithFunc(opts.getConcurrency(),
 		func(arg any) {
-			proxy.ServeConn(arg.(essentials.Conn)) //nolint: forcetypeassert
+			accepted := arg.(acceptedConn) //nolint: forcetypeassert
+			defer accepted.release()
+
+			proxy.serveConn(
+				accepted.conn.(essentials.Conn), //nolint: forcetypeassert
+				accepted.domainFrontingOnly,
+			)
 		},
 		ants.WithLogger(opts.getLogger("ants")),
 		ants.WithNonblocking(true))
@@ -1,17 +1,43 @@
 package mtglib
 
-import "time"
+import (
+	"maps"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib/internal/middleproxy"
+)
 
 // ProxyOpts is a structure with settings to mtg proxy.
 //
 // This is not required per se, but this is to shorten function signature and
 // give an ability to conveniently provide default values.
 type ProxyOpts struct {
-	// Secret defines a secret which should be used by a proxy.
+	// Secret defines a secret which should be used by a proxy. It is
+	// accepted under DefaultSecretName.
 	//
-	// This is a mandatory setting.
+	// This is a mandatory setting if Secrets are not defined.
 	Secret Secret
 
+	// Secrets defines a set of named secrets which should be accepted by a
+	// proxy. Each client can use any of them, and the name of the matched
+	// secret is bound to a stream: its logger and all its events.
+	//
+	// All secrets must have the same hostname: this is a fronting domain
+	// of the proxy.
+	//
+	// This is a mandatory setting if Secret is not defined.
+	Secrets map[string]Secret
+
+	// PreviousSecret is a secret which was replaced by Secret. It is
+	// accepted until its NotAfter moment, so clients have some time to
+	// update their links. Each handshake with this secret produces
+	// EventPreviousSecretUsed.
+	//
+	// This is an optional setting. It can be used only with Secret.
+	PreviousSecret Secret
+
 	// Network defines a network instance which should be used for all network
 	// communications made by proxies.
 	//
@@ -33,7 +59,34 @@ type ProxyOpts struct {
 	// This is an optional setting, ignored by default (no restrictions).
 	IPAllowlist IPBlocklist
 
-	// EventStream defines an instance of event stream.
+	// IPBanlist defines a list of IPs which are banned for misbehaving.
+	// It is checked before IPBlocklist.
+	//
+	// This is a mandatory setting.
+	IPBanlist IPBanlist
+
+	// ASNLookup finds autonomous systems of rejected client IPs. They are
+	// reported in [EventIPBlocklisted] events.
+	//
+	// This is an optional setting, autonomous systems are not reported by
+	// default.
+	ASNLookup ASNLookup
+
+	// ClientLimits defines limits of concurrent streams and new
+	// connections of a single client IP and of its subnet.
+	//
+	// This is an optional setting, nothing is limited by default.
+	ClientLimits ClientLimits
+
+	// TrafficQuota defines limits on traffic of streams authenticated with
+	// some secret.
+	//
+	// This is a mandatory setting.
+	TrafficQuota TrafficQuota
+
+	// EventStream defines an instance of event stream. A stream of
+	// events.NewEventStream coalesces traffic events if observers are
+	// slow, so relaying of traffic is not waiting for them.
 	//
 	// This ia a mandatory setting.
 	EventStream EventStream
@@ -91,12 +144,21 @@ type ProxyOpts struct {
 	// This is an optional setting.
 	PreferIP string
 
-	// AutoUpdate defines if it is required to auto update proxy list from
-	// Telegram instead of relying on a hardcoded list.
+	// AutoUpdate defines if addresses of DCs should be fetched from
+	// Telegram in addition to a hardcoded list. Telegram announces
+	// middle proxies of regular DCs, they are used only in middle proxy
+	// mode. Addresses of CDN DCs are used for direct connections too.
 	//
 	// This is an optional setting.
 	AutoUpdate bool
 
+	// AutoUpdateFirst defines if addresses of DCs fetched from Telegram
+	// should be tried before hardcoded ones. By default they are tried
+	// after.
+	//
+	// This is an optional setting. It makes sense only with AutoUpdate.
+	AutoUpdateFirst bool
+
 	// DomainFrontingPort is a port we use to connect to a fronting domain.
 	//
 	// This is required because secret does not specify a port. It specifies a
@@ -143,12 +205,21 @@ type ProxyOpts struct {
 	// OBSOLETE and DEPRECATED. Ignored.
 	UseTestDCs bool
 
-	// DCOverrides defines a set of IP addresses that should be used
-	// with a higher priority to those that are calculated somehow by mtg.
+	// DCOverrides defines a set of host:port addresses of DCs that should
+	// be used with a higher priority to those that are calculated somehow
+	// by mtg. Hosts can be IP addresses or hostnames.
 	//
-	// OBSOLETE and DEPRECATED. Ignored.
+	// This is an optional setting.
 	DCOverrides map[int][]string
 
+	// ReplaceOverriddenDCs defines if DCOverrides should replace hardcoded
+	// addresses of the same DCs instead of being tried before them. This
+	// is useful if some Telegram ranges are unreachable or if you want to
+	// route traffic to test DCs.
+	//
+	// This is an optional setting.
+	ReplaceOverriddenDCs bool
+
 	// DoppelGangerURLs is a list of URLs that should be crawled by
 	// mtg to calculate parameters for statistical distribution of a
 	// traffic for fronting domains. If nothing is given, then predefined
@@ -166,6 +237,51 @@ type ProxyOpts struct {
 
 	// DoppelGangerDRS defines if TLS Dynamic Record Sizing is active.
 	DoppelGangerDRS bool
+
+	// UseMiddleProxy defines if proxy should connect to Telegram via
+	// middle proxies instead of direct connections to DCs. Addresses of
+	// middle proxies are taken from https://core.telegram.org/getProxyConfig
+	// and their secret from https://core.telegram.org/getProxySecret, both
+	// are updated periodically.
+	//
+	// This is an optional setting.
+	UseMiddleProxy bool
+
+	// AdTag is an advertisement tag given by @MTProxybot. It is attached
+	// to each client connection, so Telegram can show a sponsored channel.
+	//
+	// This is an optional setting. It requires UseMiddleProxy.
+	AdTag []byte
+
+	// PublicIPv4 and PublicIPv6 are public IP addresses of the proxy.
+	// Middle proxies use an address of mtg they see to derive encryption
+	// keys, so these have to be set if mtg is behind NAT.
+	//
+	// This is an optional setting. It makes sense only with
+	// UseMiddleProxy.
+	PublicIPv4 net.IP
+	PublicIPv6 net.IP
+
+	// WarmPoolSize defines a max count of pre-dialed connections to each
+	// DC. Such connections save a TCP handshake for client streams.
+	// An actual size of the pool follows a demand for each DC.
+	//
+	// This is an optional setting. 0 disables the pool.
+	WarmPoolSize uint
+
+	// WarmPoolMaxIdle defines a time period after which pre-dialed
+	// connection is dropped if nobody has used it.
+	//
+	// This is an optional setting.
+	WarmPoolMaxIdle time.Duration
+
+	// DCProbeEach defines how often mtg dials all known addresses of
+	// Telegram to measure their health. Healthy and fast addresses are
+	// tried first.
+	//
+	// This is an optional setting. 0 disables probes, addresses are tried
+	// in a random order then.
+	DCProbeEach time.Duration
 }
 
 func (p ProxyOpts) valid() error {
@@ -178,17 +294,76 @@ func (p ProxyOpts) valid() error {
 		return ErrIPBlocklistIsNotDefined
 	case p.IPAllowlist == nil:
 		return ErrIPAllowlistIsNotDefined
+	case p.IPBanlist == nil:
+		return ErrIPBanlistIsNotDefined
+	case p.TrafficQuota == nil:
+		return ErrTrafficQuotaIsNotDefined
 	case p.EventStream == nil:
 		return ErrEventStreamIsNotDefined
 	case p.Logger == nil:
 		return ErrLoggerIsNotDefined
-	case !p.Secret.Valid():
+	case p.Secret != (Secret{}) && !p.Secret.Valid():
+		return ErrSecretInvalid
+	case p.PreviousSecret != (Secret{}) && (!p.PreviousSecret.Valid() || !p.Secret.Valid()):
 		return ErrSecretInvalid
+	case len(p.AdTag) > 0 && (len(p.AdTag) != middleproxy.AdTagLength || !p.UseMiddleProxy):
+		return ErrAdTagInvalid
+	}
+
+	if _, ok := p.Secrets[DefaultSecretName]; ok && p.Secret.Valid() {
+		return ErrSecretNameInvalid
+	}
+
+	if _, ok := p.Secrets[PreviousSecretName]; ok && p.PreviousSecret.Valid() {
+		return ErrSecretNameInvalid
+	}
+
+	secrets := p.getSecrets()
+	if len(secrets) == 0 {
+		return ErrSecretInvalid
+	}
+
+	for _, v := range secrets {
+		switch {
+		case v.name == "":
+			return ErrSecretNameInvalid
+		case !v.secret.Valid():
+			return ErrSecretInvalid
+		case v.secret.Host != secrets[0].secret.Host:
+			return ErrSecretHostMismatch
+		}
 	}
 
 	return nil
 }
 
+func (p ProxyOpts) getSecrets() []namedSecret {
+	secrets := make([]namedSecret, 0, len(p.Secrets)+2)
+
+	if p.Secret.Valid() {
+		secrets = append(secrets, namedSecret{
+			name:   DefaultSecretName,
+			secret: p.Secret,
+		})
+	}
+
+	if p.PreviousSecret.Valid() {
+		secrets = append(secrets, namedSecret{
+			name:   PreviousSecretName,
+			secret: p.PreviousSecret,
+		})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(p.Secrets)) {
+		secrets = append(secrets, namedSecret{
+			name:   name,
+			secret: p.Secrets[name],
+		})
+	}
+
+	return secrets
+}
+
 func (p ProxyOpts) getConcurrency() int {
 	if p.Concurrency == 0 {
 		return DefaultConcurrency
@@ -237,6 +412,34 @@ func (p ProxyOpts) getIdleTimeout() time.Duration {
 	return p.IdleTimeout
 }
 
+func (p ProxyOpts) getWarmPoolMaxIdle() time.Duration {
+	if p.WarmPoolMaxIdle == 0 {
+		return DefaultWarmPoolMaxIdle
+	}
+
+	return p.WarmPoolMaxIdle
+}
+
+func (p ProxyOpts) getASNLookup() ASNLookup {
+	if p.ASNLookup == nil {
+		return noopASNLookup{}
+	}
+
+	return p.ASNLookup
+}
+
+func (p ProxyOpts) getMiddleProxy() *middleproxy.Opts {
+	if !p.UseMiddleProxy {
+		return nil
+	}
+
+	return &middleproxy.Opts{
+		AdTag:      slices.Clone(p.AdTag),
+		PublicIPv4: p.PublicIPv4,
+		PublicIPv6: p.PublicIPv6,
+	}
+}
+
 func (p ProxyOpts) getLogger(name string) Logger {
 	return p.Logger.Named(name)
 }
@@ -0,0 +1,61 @@
+package mtglib
+
+import (
+	"net"
+	"strconv"
+	"time"
+)
+
+// proxySettings is a part of the proxy configuration which can be replaced
+// on a running proxy. Each stream takes a snapshot of these settings on
+// start and keeps it until the end.
+type proxySettings struct {
+	secrets    []namedSecret
+	secretKeys [][]byte
+
+	allowFallbackOnUnknownDC    bool
+	tolerateTimeSkewness        time.Duration
+	idleTimeout                 time.Duration
+	handshakeTimeout            time.Duration
+	domainFrontingPort          int
+	domainFrontingIP            string
+	domainFrontingProxyProtocol bool
+
+	blocklist IPBlocklist
+	allowlist IPBlocklist
+
+	clientLimitsDomainFronting bool
+}
+
+func (s *proxySettings) domainFrontingAddress() string {
+	host := s.secrets[0].secret.Host
+	if s.domainFrontingIP != "" {
+		host = s.domainFrontingIP
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(s.domainFrontingPort))
+}
+
+func newProxySettings(opts ProxyOpts) *proxySettings {
+	secrets := opts.getSecrets()
+	secretKeys := make([][]byte, len(secrets))
+
+	for i := range secrets {
+		secretKeys[i] = secrets[i].secret.Key[:]
+	}
+
+	return &proxySettings{
+		secrets:                     secrets,
+		secretKeys:                  secretKeys,
+		allowFallbackOnUnknownDC:    opts.AllowFallbackOnUnknownDC,
+		tolerateTimeSkewness:        opts.getTolerateTimeSkewness(),
+		idleTimeout:                 opts.getIdleTimeout(),
+		handshakeTimeout:            opts.getHandshakeTimeout(),
+		domainFrontingPort:          opts.getDomainFrontingPort(),
+		domainFrontingIP:            opts.DomainFrontingIP,
+		domainFrontingProxyProtocol: opts.DomainFrontingProxyProtocol,
+		blocklist:                   opts.IPBlocklist,
+		allowlist:                   opts.IPAllowlist,
+		clientLimitsDomainFronting:  opts.ClientLimits.DomainFronting,
+	}
+}
@@ -3,20 +3,26 @@ package mtglib_test
 import (
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/9seconds/mtg/v2/antireplay"
+	"github.com/9seconds/mtg/v2/banlist"
 	"github.com/9seconds/mtg/v2/events"
 	"github.com/9seconds/mtg/v2/ipblocklist"
 	"github.com/9seconds/mtg/v2/ipblocklist/files"
 	"github.com/9seconds/mtg/v2/logger"
 	"github.com/9seconds/mtg/v2/mtglib"
 	"github.com/9seconds/mtg/v2/network"
+	"github.com/9seconds/mtg/v2/quota"
 	"github.com/stretchr/testify/suite"
 	"github.com/yl2chen/cidranger"
 )
@@ -66,6 +72,8 @@ func (suite *ProxyTestSuite) SetupSuite() {
 		AntiReplayCache: antireplay.NewNoop(),
 		IPBlocklist:     ipblocklist.NewNoop(),
 		IPAllowlist:     allowlist,
+		IPBanlist:       banlist.NewNoop(),
+		TrafficQuota:    quota.NewNoop(),
 		EventStream:     events.NewNoopStream(),
 		Logger:          logger.NewNoopLogger(),
 		UseTestDCs:      true,
@@ -102,6 +110,80 @@ func (suite *ProxyTestSuite) TestCannotInitNoSecret() {
 	suite.Error(err)
 }
 
+func (suite *ProxyTestSuite) TestInitOnlySecrets() {
+	opts := *suite.opts
+	opts.Secret = mtglib.Secret{}
+	opts.Secrets = map[string]mtglib.Secret{
+		"team1": mtglib.GenerateSecret("httpbin.org"),
+		"team2": mtglib.GenerateSecret("httpbin.org"),
+	}
+
+	proxy, err := mtglib.NewProxy(opts)
+	suite.NoError(err)
+
+	proxy.Shutdown()
+}
+
+func (suite *ProxyTestSuite) TestCannotInitSecretsHostMismatch() {
+	opts := *suite.opts
+	opts.Secrets = map[string]mtglib.Secret{
+		"team1": mtglib.GenerateSecret("example.com"),
+	}
+
+	_, err := mtglib.NewProxy(opts)
+	suite.ErrorIs(err, mtglib.ErrSecretHostMismatch)
+}
+
+func (suite *ProxyTestSuite) TestCannotInitSecretsDefaultName() {
+	opts := *suite.opts
+	opts.Secrets = map[string]mtglib.Secret{
+		mtglib.DefaultSecretName: mtglib.GenerateSecret("httpbin.org"),
+	}
+
+	_, err := mtglib.NewProxy(opts)
+	suite.ErrorIs(err, mtglib.ErrSecretNameInvalid)
+}
+
+func (suite *ProxyTestSuite) TestInitPreviousSecret() {
+	opts := *suite.opts
+	opts.PreviousSecret = mtglib.GenerateSecret("httpbin.org")
+	opts.PreviousSecret.NotAfter = time.Now().Add(time.Hour)
+
+	proxy, err := mtglib.NewProxy(opts)
+	suite.NoError(err)
+	proxy.Shutdown()
+}
+
+func (suite *ProxyTestSuite) TestCannotInitPreviousSecretOnly() {
+	opts := *suite.opts
+	opts.Secret = mtglib.Secret{}
+	opts.Secrets = map[string]mtglib.Secret{
+		"team1": mtglib.GenerateSecret("httpbin.org"),
+	}
+	opts.PreviousSecret = mtglib.GenerateSecret("httpbin.org")
+
+	_, err := mtglib.NewProxy(opts)
+	suite.ErrorIs(err, mtglib.ErrSecretInvalid)
+}
+
+func (suite *ProxyTestSuite) TestCannotInitPreviousSecretHostMismatch() {
+	opts := *suite.opts
+	opts.PreviousSecret = mtglib.GenerateSecret("example.com")
+
+	_, err := mtglib.NewProxy(opts)
+	suite.ErrorIs(err, mtglib.ErrSecretHostMismatch)
+}
+
+func (suite *ProxyTestSuite) TestCannotInitSecretsInvalid() {
+	opts := *suite.opts
+	opts.Secrets = map[string]mtglib.Secret{
+		"team1": {Host: "httpbin.org"},
+	}
+
+	_, err := mtglib.NewProxy(opts)
+	suite.ErrorIs(err, mtglib.ErrSecretInvalid)
+}
+
 func (suite *ProxyTestSuite) TestCannotInitNoNetwork() {
 	opts := *suite.opts
 	opts.Network = nil
@@ -134,6 +216,22 @@ func (suite *ProxyTestSuite) TestCannotInitNoIPAllowlist() {
 	suite.Error(err)
 }
 
+func (suite *ProxyTestSuite) TestCannotInitNoIPBanlist() {
+	opts := *suite.opts
+	opts.IPBanlist = nil
+
+	_, err := mtglib.NewProxy(opts)
+	suite.ErrorIs(err, mtglib.ErrIPBanlistIsNotDefined)
+}
+
+func (suite *ProxyTestSuite) TestCannotInitNoTrafficQuota() {
+	opts := *suite.opts
+	opts.TrafficQuota = nil
+
+	_, err := mtglib.NewProxy(opts)
+	suite.ErrorIs(err, mtglib.ErrTrafficQuotaIsNotDefined)
+}
+
 func (suite *ProxyTestSuite) TestCannotInitNoEventStream() {
 	opts := *suite.opts
 	opts.EventStream = nil
@@ -158,10 +256,203 @@ func (suite *ProxyTestSuite) TestCannotInitIncorrectPreferIP() {
 	suite.Error(err)
 }
 
+func (suite *ProxyTestSuite) TestInitMiddleProxy() {
+	opts := *suite.opts
+	opts.UseMiddleProxy = true
+	opts.AdTag = []byte("0123456789abcdef")
+
+	proxy, err := mtglib.NewProxy(opts)
+	suite.NoError(err)
+	proxy.Shutdown()
+}
+
+func (suite *ProxyTestSuite) TestCannotInitAdTagWithoutMiddleProxy() {
+	opts := *suite.opts
+	opts.AdTag = []byte("0123456789abcdef")
+
+	_, err := mtglib.NewProxy(opts)
+	suite.ErrorIs(err, mtglib.ErrAdTagInvalid)
+}
+
+func (suite *ProxyTestSuite) TestCannotInitIncorrectAdTag() {
+	opts := *suite.opts
+	opts.UseMiddleProxy = true
+	opts.AdTag = []byte("0123")
+
+	_, err := mtglib.NewProxy(opts)
+	suite.ErrorIs(err, mtglib.ErrAdTagInvalid)
+}
+
+func (suite *ProxyTestSuite) TestInitDCOverrides() {
+	opts := *suite.opts
+	opts.DCOverrides = map[int][]string{
+		2:     {"127.0.0.1:4430"},
+		10002: {"[::1]:4430"},
+	}
+	opts.ReplaceOverriddenDCs = true
+
+	proxy, err := mtglib.NewProxy(opts)
+	suite.NoError(err)
+	proxy.Shutdown()
+}
+
+func (suite *ProxyTestSuite) TestCannotInitIncorrectDCOverrides() {
+	opts := *suite.opts
+	opts.DCOverrides = map[int][]string{
+		2: {"127.0.0.1"},
+	}
+
+	_, err := mtglib.NewProxy(opts)
+	suite.Error(err)
+}
+
+func (suite *ProxyTestSuite) TestInitWarmPool() {
+	opts := *suite.opts
+	opts.WarmPoolSize = 4
+	opts.WarmPoolMaxIdle = time.Second
+
+	proxy, err := mtglib.NewProxy(opts)
+	suite.NoError(err)
+	proxy.Shutdown()
+}
+
+func (suite *ProxyTestSuite) TestCloseStreamUnknown() {
+	suite.False(suite.p.CloseStream("unknown"))
+}
+
+func (suite *ProxyTestSuite) TestDoppelGangerState() {
+	state := suite.p.DoppelGangerState()
+
+	suite.Empty(state.URLs)
+	suite.False(state.Calibrated)
+	suite.NotZero(state.WeibullK)
+}
+
 func (suite *ProxyTestSuite) TestDomainFrontingAddress() {
 	suite.Equal("httpbin.org:443", suite.p.DomainFrontingAddress())
 }
 
+func (suite *ProxyTestSuite) TestReload() {
+	proxy, err := mtglib.NewProxy(*suite.opts)
+	suite.Require().NoError(err)
+
+	defer proxy.Shutdown()
+
+	opts := *suite.opts
+	opts.DomainFrontingPort = 8443
+	opts.Concurrency = 10
+	opts.Secrets = map[string]mtglib.Secret{
+		"team1": mtglib.GenerateSecret("httpbin.org"),
+	}
+
+	suite.NoError(proxy.Reload(opts))
+	suite.Equal("httpbin.org:8443", proxy.DomainFrontingAddress())
+}
+
+func (suite *ProxyTestSuite) TestReloadInvalid() {
+	proxy, err := mtglib.NewProxy(*suite.opts)
+	suite.Require().NoError(err)
+
+	defer proxy.Shutdown()
+
+	opts := *suite.opts
+	opts.DomainFrontingPort = 8443
+	opts.Secrets = map[string]mtglib.Secret{
+		"team1": mtglib.GenerateSecret("example.com"),
+	}
+
+	suite.ErrorIs(proxy.Reload(opts), mtglib.ErrSecretHostMismatch)
+	suite.Equal("httpbin.org:443", proxy.DomainFrontingAddress())
+}
+
+func (suite *ProxyTestSuite) TestReloadAfterShutdown() {
+	proxy, err := mtglib.NewProxy(*suite.opts)
+	suite.Require().NoError(err)
+
+	proxy.Shutdown()
+
+	suite.ErrorIs(proxy.Reload(*suite.opts), mtglib.ErrProxyClosed)
+}
+
+func (suite *ProxyTestSuite) TestLimitedFrontingIsBounded() {
+	fronting, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	defer fronting.Close() //nolint: errcheck
+
+	frontedConns := make(chan net.Conn, 2*mtglib.LimitedFrontingConcurrency)
+
+	go func() {
+		for {
+			conn, err := fronting.Accept()
+			if err != nil {
+				return
+			}
+
+			frontedConns <- conn
+		}
+	}()
+
+	opts := *suite.opts
+	opts.DomainFrontingIP = "127.0.0.1"
+	opts.DomainFrontingPort = uint(fronting.Addr().(*net.TCPAddr).Port) //nolint: forcetypeassert
+	opts.ClientLimits = mtglib.ClientLimits{
+		ConcurrentPerIP: 1,
+		DomainFronting:  true,
+	}
+
+	proxy, err := mtglib.NewProxy(opts)
+	suite.Require().NoError(err)
+
+	defer proxy.Shutdown()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	defer listener.Close() //nolint: errcheck
+
+	go proxy.Serve(listener) //nolint: errcheck
+
+	clients := make([]net.Conn, 0, mtglib.LimitedFrontingConcurrency+17)
+
+	for range cap(clients) {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		suite.Require().NoError(err)
+
+		defer conn.Close() //nolint: errcheck
+
+		clients = append(clients, conn)
+	}
+
+	suite.Eventually(func() bool {
+		return len(frontedConns) == mtglib.LimitedFrontingConcurrency
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// each of open connections takes a worker of a pool.
+	open := atomic.Int32{}
+	wg := sync.WaitGroup{}
+
+	for _, conn := range clients {
+		wg.Go(func() {
+			conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond)) //nolint: errcheck
+
+			_, err := conn.Read(make([]byte, 1))
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				open.Add(1)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	suite.EqualValues(1+mtglib.LimitedFrontingConcurrency, open.Load())
+	suite.Len(frontedConns, mtglib.LimitedFrontingConcurrency)
+
+	for range len(frontedConns) {
+		(<-frontedConns).Close() //nolint: errcheck
+	}
+}
+
 func (suite *ProxyTestSuite) TestHTTPSRequest() {
 	client := &http.Client{
 		Transport: &http.Transport{
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"time"
 )
 
 const secretFakeTLSFirstByte byte = 0xee
@@ -38,12 +39,24 @@ var secretEmptyKey [SecretKeyLength]byte
 // Secrets can be serialized into 2 forms: hex and base64. If you decode both
 // forms into bytes, you'll get the same byte array. Telegram clients nowadays
 // accept all forms.
+//
+// Secret may also have a validity window. This window is not a part of the
+// serialized form, clients know nothing about it. It is used only by a proxy
+// to reject secrets which are not active yet or already expired.
 type Secret struct {
 	// Key is a set of bytes used for traffic authentication.
 	Key [SecretKeyLength]byte
 
 	// Host is a domain fronting hostname.
 	Host string
+
+	// NotBefore is a moment when secret becomes active. Zero value means
+	// that secret is active since the beginning of times.
+	NotBefore time.Time
+
+	// NotAfter is a moment when secret expires. Zero value means that secret
+	// never expires.
+	NotAfter time.Time
 }
 
 // MarshalText is to support text.Marshaller interface.
@@ -99,7 +112,21 @@ func (s *Secret) Set(text string) error {
 
 // Valid checks if this secret is valid and can be used in proxy.
 func (s Secret) Valid() bool {
-	return s.Key != secretEmptyKey && s.Host != ""
+	if s.Key == secretEmptyKey || s.Host == "" {
+		return false
+	}
+
+	return s.NotBefore.IsZero() || s.NotAfter.IsZero() || s.NotAfter.After(s.NotBefore)
+}
+
+// ActiveAt checks if a given moment is within a validity window of the
+// secret.
+func (s Secret) ActiveAt(moment time.Time) bool {
+	if !s.NotBefore.IsZero() && moment.Before(s.NotBefore) {
+		return false
+	}
+
+	return s.NotAfter.IsZero() || moment.Before(s.NotAfter)
 }
 
 // String is to support fmt.Stringer interface.
@@ -143,3 +170,8 @@ func ParseSecret(secret string) (Secret, error) {
 
 	return s, s.UnmarshalText([]byte(secret))
 }
+
+type namedSecret struct {
+	name   string
+	secret Secret
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/9seconds/mtg/v2/mtglib"
 	"github.com/stretchr/testify/assert"
@@ -119,6 +120,33 @@ func (suite *SecretTestSuite) TestValid() {
 
 	s.Host = "11"
 	suite.True(s.Valid())
+
+	now := time.Now()
+
+	s.NotBefore = now
+	s.NotAfter = now.Add(-time.Hour)
+	suite.False(s.Valid())
+
+	s.NotAfter = now.Add(time.Hour)
+	suite.True(s.Valid())
+}
+
+func (suite *SecretTestSuite) TestActiveAt() {
+	now := time.Now()
+	s := mtglib.GenerateSecret("google.com")
+
+	suite.True(s.ActiveAt(now))
+
+	s.NotBefore = now.Add(time.Hour)
+	suite.False(s.ActiveAt(now))
+	suite.True(s.ActiveAt(now.Add(2 * time.Hour)))
+
+	s.NotAfter = now.Add(3 * time.Hour)
+	suite.True(s.ActiveAt(now.Add(2 * time.Hour)))
+	suite.False(s.ActiveAt(now.Add(3 * time.Hour)))
+
+	s.NotBefore = time.Time{}
+	suite.True(s.ActiveAt(now))
 }
 
 func TestSecret(t *testing.T) {
@@ -16,6 +16,10 @@ type streamContext struct {
 	clientConn   essentials.Conn
 	telegramConn essentials.Conn
 	streamID     string
+	secretName   string
+	secretKey    []byte
+	secretExpiry time.Time
+	settings     *proxySettings
 	dc           int
 	logger       Logger
 }
@@ -52,6 +56,23 @@ func (s *streamContext) ClientIP() net.IP {
 	return s.clientConn.RemoteAddr().(*net.TCPAddr).IP //nolint: forcetypeassert
 }
 
+// watchSecretExpiry terminates a relayed stream once its secret has
+// expired. A returned function stops watching.
+func (s *streamContext) watchSecretExpiry() func() {
+	if s.secretExpiry.IsZero() {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(time.Until(s.secretExpiry), func() {
+		s.logger.Info("secret has expired, closing a stream")
+		s.ctxCancel()
+	})
+
+	return func() {
+		timer.Stop()
+	}
+}
+
 func newStreamContext(ctx context.Context, logger Logger, clientConn essentials.Conn) *streamContext {
 	connIDBytes := make([]byte, ConnectionIDBytesLength)
 
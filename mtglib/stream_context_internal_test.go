@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/9seconds/mtg/v2/internal/testlib"
 	"github.com/stretchr/testify/suite"
@@ -89,6 +90,32 @@ func (suite *StreamContextTestSuite) TestClose() {
 	tgConnMock.AssertExpectations(suite.T())
 }
 
+func (suite *StreamContextTestSuite) TestWatchSecretExpiry() {
+	suite.ctx.secretExpiry = time.Now().Add(20 * time.Millisecond)
+
+	stop := suite.ctx.watchSecretExpiry()
+	defer stop()
+
+	suite.NoError(suite.ctx.Err())
+	suite.Eventually(func() bool {
+		return suite.ctx.Err() != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (suite *StreamContextTestSuite) TestWatchSecretExpiryStopped() {
+	suite.ctx.secretExpiry = time.Now().Add(20 * time.Millisecond)
+
+	suite.ctx.watchSecretExpiry()()
+
+	time.Sleep(50 * time.Millisecond)
+	suite.NoError(suite.ctx.Err())
+}
+
+func (suite *StreamContextTestSuite) TestWatchSecretExpiryNotSet() {
+	suite.ctx.watchSecretExpiry()()
+	suite.NoError(suite.ctx.Err())
+}
+
 func TestStreamContext(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, &StreamContextTestSuite{})
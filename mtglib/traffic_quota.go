@@ -0,0 +1,32 @@
+package mtglib
+
+import "time"
+
+// trafficQuotaCheckEach defines how often streams check if a quota of
+// their secret is exhausted.
+const trafficQuotaCheckEach = 10 * time.Second
+
+// watchTrafficQuota terminates a stream once a quota of its secret is
+// exhausted. A quota is consumed only on traffic, so a stream which went
+// idle after its quota had run out would live until idle timeout
+// otherwise.
+//
+// This is a blocking function, it returns when a stream is done.
+func watchTrafficQuota(ctx *streamContext, quota TrafficQuota, checkEach time.Duration) {
+	ticker := time.NewTicker(checkEach)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !quota.Allowed(ctx.secretName) {
+				ctx.logger.Info("traffic quota is exhausted, closing a stream")
+				ctx.ctxCancel()
+
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package mtglib
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/internal/testlib"
+	"github.com/stretchr/testify/suite"
+)
+
+type trafficQuotaFake struct {
+	exhausted atomic.Bool
+}
+
+func (t *trafficQuotaFake) Allowed(_ string) bool { return !t.exhausted.Load() }
+func (t *trafficQuotaFake) Shutdown()             {}
+
+func (t *trafficQuotaFake) Consume(_ string, _ uint) (time.Duration, error) {
+	return 0, nil
+}
+
+type WatchTrafficQuotaTestSuite struct {
+	suite.Suite
+
+	quota *trafficQuotaFake
+	ctx   *streamContext
+}
+
+func (suite *WatchTrafficQuotaTestSuite) SetupTest() {
+	connMock := &testlib.EssentialsConnMock{}
+	connMock.On("RemoteAddr").Return(&net.TCPAddr{
+		IP:   net.ParseIP("10.0.0.10"),
+		Port: 6676,
+	})
+
+	suite.quota = &trafficQuotaFake{}
+	suite.ctx = newStreamContext(context.Background(), NoopLogger{}, connMock)
+}
+
+func (suite *WatchTrafficQuotaTestSuite) TearDownTest() {
+	suite.ctx.ctxCancel()
+}
+
+func (suite *WatchTrafficQuotaTestSuite) TestExhausted() {
+	go watchTrafficQuota(suite.ctx, suite.quota, 10*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	suite.NoError(suite.ctx.Err())
+
+	suite.quota.exhausted.Store(true)
+
+	suite.Eventually(func() bool {
+		return suite.ctx.Err() != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (suite *WatchTrafficQuotaTestSuite) TestStreamIsDone() {
+	done := make(chan struct{})
+
+	go func() {
+		watchTrafficQuota(suite.ctx, suite.quota, time.Hour)
+		close(done)
+	}()
+
+	suite.ctx.ctxCancel()
+
+	suite.Eventually(func() bool {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWatchTrafficQuota(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &WatchTrafficQuotaTestSuite{})
+}
@@ -0,0 +1,36 @@
+// Quota package has implementations of [mtglib.TrafficQuota].
+//
+// A quota limits a traffic of streams which are authenticated with some
+// secret. It is possible to set a limit on a total traffic per day and per
+// month and a cap on throughput. Counters are stored in a small state file so
+// they survive restarts.
+package quota
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultSaveEach defines a default period of time for dumping counters into
+// a state file.
+const DefaultSaveEach = time.Minute
+
+// ErrQuotaExceeded is returned when a stream has exhausted a quota of its
+// secret.
+var ErrQuotaExceeded = errors.New("quota is exceeded")
+
+// Limits defines limits of a single secret. Zero value means that there is no
+// limit.
+type Limits struct {
+	// Daily is a maximum number of bytes that could be transferred within a
+	// day (UTC).
+	Daily uint64
+
+	// Monthly is a maximum number of bytes that could be transferred within
+	// a month (UTC).
+	Monthly uint64
+
+	// Throughput is a maximum number of bytes per second for all streams
+	// authenticated with a secret.
+	Throughput uint64
+}
@@ -0,0 +1,21 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+type noop struct{}
+
+func (n noop) Allowed(_ string) bool { return true }
+func (n noop) Shutdown()             {}
+
+func (n noop) Consume(_ string, _ uint) (time.Duration, error) {
+	return 0, nil
+}
+
+// NewNoop returns a dummy quota which has no limits at all.
+func NewNoop() mtglib.TrafficQuota {
+	return noop{}
+}
@@ -0,0 +1,29 @@
+package quota_test
+
+import (
+	"testing"
+
+	"github.com/9seconds/mtg/v2/quota"
+	"github.com/stretchr/testify/suite"
+)
+
+type NoopTestSuite struct {
+	suite.Suite
+}
+
+func (suite *NoopTestSuite) TestOp() {
+	q := quota.NewNoop()
+
+	suite.True(q.Allowed("secret"))
+
+	delay, err := q.Consume("secret", 1024*1024*1024)
+	suite.NoError(err)
+	suite.Zero(delay)
+
+	q.Shutdown()
+}
+
+func TestNoop(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &NoopTestSuite{})
+}
@@ -0,0 +1,275 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+const (
+	quotaDayLayout   = "2006-01-02"
+	quotaMonthLayout = "2006-01"
+
+	// a bucket can accumulate this amount of time of throughput.
+	quotaBurst = time.Second
+)
+
+type secretCounters struct {
+	Day     string `json:"day"`
+	Daily   uint64 `json:"daily"`
+	Month   string `json:"month"`
+	Monthly uint64 `json:"monthly"`
+}
+
+type secretQuota struct {
+	mutex    sync.Mutex
+	limits   Limits
+	counters secretCounters
+
+	// a moment when the bucket becomes empty. This is a GCRA-like
+	// implementation of a token bucket.
+	bucketAt time.Time
+}
+
+func (s *secretQuota) roll(now time.Time) {
+	day := now.Format(quotaDayLayout)
+	month := now.Format(quotaMonthLayout)
+
+	if s.counters.Day != day {
+		s.counters.Day = day
+		s.counters.Daily = 0
+	}
+
+	if s.counters.Month != month {
+		s.counters.Month = month
+		s.counters.Monthly = 0
+	}
+}
+
+func (s *secretQuota) exceeded() bool {
+	return (s.limits.Daily > 0 && s.counters.Daily >= s.limits.Daily) ||
+		(s.limits.Monthly > 0 && s.counters.Monthly >= s.limits.Monthly)
+}
+
+func (s *secretQuota) throttle(now time.Time, traffic uint) time.Duration {
+	if s.limits.Throughput == 0 {
+		return 0
+	}
+
+	if s.bucketAt.Before(now) {
+		s.bucketAt = now
+	}
+
+	s.bucketAt = s.bucketAt.Add(
+		time.Duration(float64(traffic) / float64(s.limits.Throughput) * float64(time.Second)))
+
+	return max(s.bucketAt.Sub(now)-quotaBurst, 0)
+}
+
+// Quota is [mtglib.TrafficQuota] which keeps counters in memory and
+// periodically dumps them into a state file.
+//
+// Day and month boundaries are calculated in UTC.
+type Quota struct {
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	logger    mtglib.Logger
+	stateFile string
+	saveMutex sync.Mutex
+
+	secrets map[string]*secretQuota
+}
+
+// Allowed checks if a secret has not exhausted its quota yet.
+func (q *Quota) Allowed(secretName string) bool {
+	secret, ok := q.secrets[secretName]
+	if !ok {
+		return true
+	}
+
+	secret.mutex.Lock()
+	defer secret.mutex.Unlock()
+
+	secret.roll(time.Now().UTC())
+
+	return !secret.exceeded()
+}
+
+// Consume registers a traffic of a secret. It returns a time to wait to keep
+// a throughput under the cap or [ErrQuotaExceeded] if quota is exhausted.
+func (q *Quota) Consume(secretName string, traffic uint) (time.Duration, error) {
+	secret, ok := q.secrets[secretName]
+	if !ok {
+		return 0, nil
+	}
+
+	now := time.Now().UTC()
+
+	secret.mutex.Lock()
+	defer secret.mutex.Unlock()
+
+	secret.roll(now)
+
+	if secret.exceeded() {
+		return 0, ErrQuotaExceeded
+	}
+
+	secret.counters.Daily += uint64(traffic)
+	secret.counters.Monthly += uint64(traffic)
+
+	return secret.throttle(now, traffic), nil
+}
+
+// Run starts a background process which dumps counters into a state file.
+//
+// This is a blocking method so you probably want to run it in a goroutine.
+func (q *Quota) Run(saveEach time.Duration) {
+	if saveEach == 0 {
+		saveEach = DefaultSaveEach
+	}
+
+	ticker := time.NewTicker(saveEach)
+
+	defer func() {
+		ticker.Stop()
+
+		select {
+		case <-ticker.C:
+		default:
+		}
+	}()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := q.save(); err != nil {
+				q.logger.WarningError("cannot save quota state", err)
+			}
+		}
+	}
+}
+
+// Shutdown stops a background process and dumps counters into a state
+// file.
+func (q *Quota) Shutdown() {
+	q.ctxCancel()
+
+	if err := q.save(); err != nil {
+		q.logger.WarningError("cannot save quota state", err)
+	}
+}
+
+func (q *Quota) load() error {
+	if q.stateFile == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(q.stateFile)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return fmt.Errorf("cannot read a state file: %w", err)
+	}
+
+	state := map[string]secretCounters{}
+
+	if err := json.Unmarshal(content, &state); err != nil {
+		return fmt.Errorf("cannot parse a state file: %w", err)
+	}
+
+	for name, counters := range state {
+		if secret, ok := q.secrets[name]; ok {
+			secret.counters = counters
+		}
+	}
+
+	return nil
+}
+
+func (q *Quota) save() error {
+	if q.stateFile == "" {
+		return nil
+	}
+
+	q.saveMutex.Lock()
+	defer q.saveMutex.Unlock()
+
+	state := make(map[string]secretCounters, len(q.secrets))
+
+	for name, secret := range q.secrets {
+		secret.mutex.Lock()
+		state[name] = secret.counters
+		secret.mutex.Unlock()
+	}
+
+	content, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("cannot serialize a state: %w", err)
+	}
+
+	// write + rename is atomic, so we won't end up with a half-written file
+	// on crash.
+	tmpFile, err := os.CreateTemp(filepath.Dir(q.stateFile), filepath.Base(q.stateFile)+".*")
+	if err != nil {
+		return fmt.Errorf("cannot create a temporary file: %w", err)
+	}
+
+	defer os.Remove(tmpFile.Name()) //nolint: errcheck
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close() //nolint: errcheck
+
+		return fmt.Errorf("cannot write a temporary file: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("cannot close a temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), q.stateFile); err != nil {
+		return fmt.Errorf("cannot replace a state file: %w", err)
+	}
+
+	return nil
+}
+
+// New creates a new quota for given secrets. Secrets which are not mentioned
+// in limits have no restrictions.
+//
+// If stateFile is empty, counters are kept only in memory.
+func New(logger mtglib.Logger, stateFile string, limits map[string]Limits) (*Quota, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	quota := &Quota{
+		ctx:       ctx,
+		ctxCancel: cancel,
+		logger:    logger,
+		stateFile: stateFile,
+		secrets:   make(map[string]*secretQuota, len(limits)),
+	}
+
+	for name, value := range limits {
+		quota.secrets[name] = &secretQuota{
+			limits: value,
+		}
+	}
+
+	if err := quota.load(); err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	return quota, nil
+}
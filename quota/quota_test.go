@@ -0,0 +1,115 @@
+package quota_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/logger"
+	"github.com/9seconds/mtg/v2/quota"
+	"github.com/stretchr/testify/suite"
+)
+
+type QuotaTestSuite struct {
+	suite.Suite
+
+	stateFile string
+}
+
+func (suite *QuotaTestSuite) SetupTest() {
+	suite.stateFile = filepath.Join(suite.T().TempDir(), "quota.json")
+}
+
+func (suite *QuotaTestSuite) Make(limits map[string]quota.Limits) *quota.Quota {
+	q, err := quota.New(logger.NewNoopLogger(), suite.stateFile, limits)
+	suite.NoError(err)
+
+	return q
+}
+
+func (suite *QuotaTestSuite) TestUnknownSecret() {
+	q := suite.Make(map[string]quota.Limits{
+		"limited": {Daily: 10},
+	})
+	defer q.Shutdown()
+
+	suite.True(q.Allowed("unknown"))
+
+	delay, err := q.Consume("unknown", 1000)
+	suite.NoError(err)
+	suite.Zero(delay)
+	suite.True(q.Allowed("unknown"))
+}
+
+func (suite *QuotaTestSuite) TestDaily() {
+	q := suite.Make(map[string]quota.Limits{
+		"limited": {Daily: 10},
+	})
+	defer q.Shutdown()
+
+	suite.True(q.Allowed("limited"))
+
+	_, err := q.Consume("limited", 5)
+	suite.NoError(err)
+	suite.True(q.Allowed("limited"))
+
+	_, err = q.Consume("limited", 5)
+	suite.NoError(err)
+	suite.False(q.Allowed("limited"))
+
+	_, err = q.Consume("limited", 1)
+	suite.ErrorIs(err, quota.ErrQuotaExceeded)
+}
+
+func (suite *QuotaTestSuite) TestMonthly() {
+	q := suite.Make(map[string]quota.Limits{
+		"limited": {Daily: 100, Monthly: 10},
+	})
+	defer q.Shutdown()
+
+	_, err := q.Consume("limited", 20)
+	suite.NoError(err)
+	suite.False(q.Allowed("limited"))
+}
+
+func (suite *QuotaTestSuite) TestThroughput() {
+	q := suite.Make(map[string]quota.Limits{
+		"limited": {Throughput: 100},
+	})
+	defer q.Shutdown()
+
+	delay, err := q.Consume("limited", 100)
+	suite.NoError(err)
+	suite.Zero(delay)
+
+	delay, err = q.Consume("limited", 100)
+	suite.NoError(err)
+	suite.InDelta(time.Second, delay, float64(100*time.Millisecond))
+	suite.True(q.Allowed("limited"))
+}
+
+func (suite *QuotaTestSuite) TestPersistence() {
+	limits := map[string]quota.Limits{
+		"limited": {Daily: 10},
+	}
+
+	q := suite.Make(limits)
+	_, err := q.Consume("limited", 10)
+	suite.NoError(err)
+	q.Shutdown()
+
+	q = suite.Make(limits)
+	defer q.Shutdown()
+
+	suite.False(q.Allowed("limited"))
+}
+
+func (suite *QuotaTestSuite) TestBrokenStateFile() {
+	_, err := quota.New(logger.NewNoopLogger(), suite.T().TempDir(), nil)
+	suite.Error(err)
+}
+
+func TestQuota(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &QuotaTestSuite{})
+}
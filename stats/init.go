@@ -80,6 +80,9 @@ const (
 	// client was blocked because her IP address was found in blocklists.
 	//
 	//     Type: counter
+	//     Tags:
+	//       ip_list | 'allowlist' or 'blocklist'
+	//       asn     | a number of autonomous system, if it is known
 	MetricIPBlocklisted = "ip_blocklisted"
 
 	// MetricReplayAttacks defines a metric for a count of events, when
@@ -89,6 +92,13 @@ const (
 	//     Type: counter
 	MetricReplayAttacks = "replay_attacks"
 
+	// MetricPreviousSecretHandshakes defines a metric for a count of
+	// handshakes made with a previous secret. Once it stops growing, it is
+	// safe to drop a previous secret.
+	//
+	//     Type: counter
+	MetricPreviousSecretHandshakes = "previous_secret_handshakes"
+
 	// MetricIPListSize defines a metric for the size of the the ip list.
 	//
 	//     Type: gauge
@@ -96,6 +106,119 @@ const (
 	//       ip_list | 'allowlist' or 'blocklist'
 	MetricIPListSize = "iplist_size"
 
+	// MetricTelegramProbeRTT defines a metric for a time (in seconds) of
+	// establishing TCP connection to Telegram server by background prober.
+	// It is a moving average. It is not reported until address is reached.
+	//
+	//     Type: gauge
+	//     Tags:
+	//       telegram_ip | IP address of the telegram server.
+	//       dc          | Index of the datacenter.
+	MetricTelegramProbeRTT = "telegram_probe_rtt"
+
+	// MetricTelegramProbeFailureRate defines a metric for a rate of failed
+	// probes of Telegram server, from 0 to 1. It is a moving average.
+	// Servers with rate 0.5 and more are tried last.
+	//
+	//     Type: gauge
+	//     Tags:
+	//       telegram_ip | IP address of the telegram server.
+	//       dc          | Index of the datacenter.
+	MetricTelegramProbeFailureRate = "telegram_probe_failure_rate"
+
+	// MetricWarmPoolHits defines a metric for a count of client streams
+	// which got a pre-dialed connection to Telegram.
+	//
+	//     Type: counter
+	//     Tags:
+	//       dc | Index of the datacenter.
+	MetricWarmPoolHits = "warm_pool_hits"
+
+	// MetricWarmPoolMisses defines a metric for a count of client streams
+	// which had to dial Telegram because pool of pre-dialed connections
+	// was empty.
+	//
+	//     Type: counter
+	//     Tags:
+	//       dc | Index of the datacenter.
+	MetricWarmPoolMisses = "warm_pool_misses"
+
+	// MetricHandshakeFailures defines a metric for a count of failed
+	// client handshakes.
+	//
+	//     Type: counter
+	//     Tags:
+	//       reason | A reason of the failure.
+	MetricHandshakeFailures = "handshake_failures"
+
+	// MetricClientClockSkew defines a metric for an absolute difference
+	// (in seconds) between a clock of the client and a clock of mtg. It
+	// is measured for each client hello signed by a known secret,
+	// including those which are rejected because of too large skew.
+	// Statsd gets this value as a timing.
+	//
+	//     Type: histogram
+	//     Tags:
+	//       result | 'accepted' or 'rejected'
+	MetricClientClockSkew = "client_clock_skew"
+
+	// MetricFakeTLSHandshakeDuration defines a metric for a time (in
+	// seconds) since a client has connected until mtg has sent server
+	// hello. Statsd gets this value as a timing.
+	//
+	//     Type: histogram
+	MetricFakeTLSHandshakeDuration = "faketls_handshake_duration"
+
+	// MetricObfuscatedHandshakeDuration defines a metric for a time (in
+	// seconds) of obfuscated handshake which follows FakeTLS one. Statsd
+	// gets this value as a timing.
+	//
+	//     Type: histogram
+	MetricObfuscatedHandshakeDuration = "obfuscated_handshake_duration"
+
+	// MetricTelegramDialDuration defines a metric for a time (in seconds)
+	// of establishing TCP connection to Telegram for a client stream.
+	// Connections taken from a warm pool are not counted. Statsd gets
+	// this value as a timing.
+	//
+	//     Type: histogram
+	//     Tags:
+	//       telegram_ip | IP address of the telegram server.
+	//       dc          | Index of the datacenter.
+	MetricTelegramDialDuration = "telegram_dial_duration"
+
+	// MetricSessionDuration defines a metric for a time (in seconds) of
+	// client stream, from accepting a connection until it is closed.
+	// Statsd gets this value as a timing.
+	//
+	//     Type: histogram
+	MetricSessionDuration = "session_duration"
+
+	// MetricEventsOverflow defines a metric for a count of events which
+	// were not delivered to observers as is because they were too slow.
+	//
+	//     Type: counter
+	//     Tags:
+	//       action | 'dropped' or 'coalesced'
+	MetricEventsOverflow = "events_overflow"
+
+	// MetricRateLimited defines a metric for a count of connections of
+	// clients which have exceeded their limits.
+	//
+	//     Type: counter
+	//     Tags:
+	//       reason | ip_concurrency, subnet_concurrency, ip_rate,
+	//                subnet_rate
+	MetricRateLimited = "rate_limited"
+
+	// MetricIPBans defines a metric for a count of client IPs which were
+	// banned for failed handshakes.
+	//
+	//     Type: counter
+	//     Tags:
+	//       reason | a reason of the failed handshake
+	MetricIPBans = "ip_bans"
+
 	// TagIPFamily defines a name of the 'ip_family' tag and all values.
 	TagIPFamily = "ip_family"
 
@@ -130,4 +253,63 @@ const (
 
 	// TagIPListBlock defines a value of 'ip_list' of blocklist.
 	TagIPListBlock = "blocklist"
+
+	// TagIPListBan defines a value of 'ip_list' of banned IPs.
+	TagIPListBan = "banlist"
+
+	// TagASN defines a name of the 'asn' tag. A value is a number of
+	// autonomous system of a client. It is empty or omitted if it is
+	// unknown.
+	TagASN = "asn"
+
+	// TagASNOther defines a value of 'asn' tag for autonomous systems
+	// which are not tracked.
+	TagASNOther = "other"
+
+	// TagReason defines a name of the 'reason' tag. Values are
+	// [mtglib.HandshakeFailureReason] or [mtglib.RateLimitReason].
+	TagReason = "reason"
+
+	// TagResult defines a name of the 'result' tag and all values.
+	TagResult = "result"
+
+	// TagResultAccepted defines a value of 'result' of accepted
+	// handshake.
+	TagResultAccepted = "accepted"
+
+	// TagResultRejected defines a value of 'result' of rejected
+	// handshake.
+	TagResultRejected = "rejected"
+
+	// TagAction defines a name of the 'action' tag and all values.
+	TagAction = "action"
+
+	// TagActionDropped defines a value of 'action' of dropped events.
+	TagActionDropped = "dropped"
+
+	// TagActionCoalesced defines a value of 'action' of traffic events
+	// merged into other ones.
+	TagActionCoalesced = "coalesced"
 )
+
+// ClockSkewBuckets defines buckets (in seconds) of
+// [MetricClientClockSkew] histogram. Default time skewness is 3
+// seconds, so buckets are dense there and then go up to a day to catch
+// clients with a wrong timezone.
+var ClockSkewBuckets = []float64{ //nolint: gochecknoglobals
+	0.5, 1, 2, 3, 5, 10, 30, 60, 120, 300, 600, 1800, 3600, 7200, 86400,
+}
+
+// LatencyBuckets defines buckets (in seconds) of handshake and dial
+// histograms.
+var LatencyBuckets = []float64{ //nolint: gochecknoglobals
+	0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// SessionDurationBuckets defines buckets (in seconds) of
+// [MetricSessionDuration] histogram. Probes and failed handshakes
+// live for a second or less, while real clients keep connections for
+// minutes or hours.
+var SessionDurationBuckets = []float64{ //nolint: gochecknoglobals
+	0.1, 1, 5, 15, 60, 300, 900, 1800, 3600, 3 * 3600, 12 * 3600, 86400,
+}
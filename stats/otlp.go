@@ -0,0 +1,614 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/9seconds/mtg/v2/events"
+	"github.com/9seconds/mtg/v2/mtglib"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// DefaultOTLPPushInterval defines how often metrics are pushed to
+	// OTLP collector.
+	DefaultOTLPPushInterval = 15 * time.Second
+
+	// OTLPServiceName defines a name of the service which is reported to
+	// OTLP collector.
+	OTLPServiceName = "mtg"
+
+	otlpSpanStream         = "stream"
+	otlpSpanHandshake      = "handshake"
+	otlpSpanDCDial         = "dc_dial"
+	otlpSpanRelay          = "relay"
+	otlpSpanDomainFronting = "domain_fronting"
+
+	otlpAttrStreamID          = "stream_id"
+	otlpAttrSecret            = "secret"
+	otlpAttrDomainFronting    = "domain_fronting"
+	otlpAttrTrafficToClient   = "traffic_" + TagDirectionToClient
+	otlpAttrTrafficFromClient = "traffic_" + TagDirectionFromClient
+	otlpAttrDialDuration      = "dial_duration"
+	otlpAttrClockSkew         = "clock_skew"
+	otlpAttrWarmPoolHit       = "warm_pool_hit"
+
+	otlpEventReplayAttack   = "replay_attack"
+	otlpEventPreviousSecret = "previous_secret"
+)
+
+// otlpStream is a state of the stream trace. There is a root span of
+// the stream and a span of the current stage of it: handshake, dial,
+// relay or domain fronting. Stages follow each other.
+type otlpStream struct {
+	info *streamInfo
+
+	ctx   context.Context //nolint: containedctx
+	span  trace.Span
+	stage trace.Span
+
+	trafficToClient   int64
+	trafficFromClient int64
+}
+
+func (o *otlpStream) startStage(tracer trace.Tracer, name string, startedAt time.Time) {
+	_, o.stage = tracer.Start(o.ctx, name, trace.WithTimestamp(startedAt))
+}
+
+func (o *otlpStream) endStage(endedAt time.Time) {
+	if o.stage != nil {
+		o.stage.End(trace.WithTimestamp(endedAt))
+		o.stage = nil
+	}
+}
+
+func (o *otlpStream) attrs(keys ...string) metric.AddOption {
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+
+	for _, key := range keys {
+		attrs = append(attrs, attribute.String(key, o.info.tags[key]))
+	}
+
+	return metric.WithAttributes(attrs...)
+}
+
+func (o *otlpStream) finish(finishedAt time.Time) {
+	o.endStage(finishedAt)
+	o.span.SetAttributes(
+		attribute.Int64(otlpAttrTrafficToClient, o.trafficToClient),
+		attribute.Int64(otlpAttrTrafficFromClient, o.trafficFromClient))
+	o.span.End(trace.WithTimestamp(finishedAt))
+}
+
+type otlpProcessor struct {
+	streams map[string]*otlpStream
+	factory *OTLPFactory
+}
+
+func (o otlpProcessor) EventStart(evt mtglib.EventStart) {
+	stream := &otlpStream{
+		info: acquireStreamInfo(),
+	}
+
+	if evt.RemoteIP.To4() != nil {
+		stream.info.tags[TagIPFamily] = TagIPFamilyIPv4
+	} else {
+		stream.info.tags[TagIPFamily] = TagIPFamilyIPv6
+	}
+
+	stream.ctx, stream.span = o.factory.tracer.Start(
+		withOTLPStreamID(context.Background(), evt.StreamID()),
+		otlpSpanStream,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithTimestamp(evt.Timestamp()),
+		trace.WithAttributes(
+			attribute.String(otlpAttrStreamID, evt.StreamID()),
+			attribute.String(TagIPFamily, stream.info.tags[TagIPFamily])))
+	stream.startStage(o.factory.tracer, otlpSpanHandshake, evt.Timestamp())
+
+	o.streams[evt.StreamID()] = stream
+
+	o.factory.metricClientConnections.Add(context.Background(), 1, stream.attrs(TagIPFamily))
+}
+
+func (o otlpProcessor) EventConnectedToDC(evt mtglib.EventConnectedToDC) {
+	ctx := context.Background()
+
+	if evt.DialDuration > 0 {
+		o.factory.metricTelegramDialDuration.Record(ctx,
+			evt.DialDuration.Seconds(),
+			metric.WithAttributes(
+				attribute.String(TagTelegramIP, evt.RemoteIP.String()),
+				attribute.String(TagDC, strconv.Itoa(evt.DC))))
+	}
+
+	stream, ok := o.streams[evt.StreamID()]
+	if !ok {
+		return
+	}
+
+	stream.info.tags[TagTelegramIP] = evt.RemoteIP.String()
+	stream.info.tags[TagDC] = strconv.Itoa(evt.DC)
+
+	if stream.stage != nil {
+		stream.stage.SetAttributes(
+			attribute.String(TagTelegramIP, stream.info.tags[TagTelegramIP]),
+			attribute.Int(TagDC, evt.DC),
+			attribute.Float64(otlpAttrDialDuration, evt.DialDuration.Seconds()))
+	}
+
+	stream.span.SetAttributes(
+		attribute.String(otlpAttrSecret, evt.SecretName()),
+		attribute.String(TagTelegramIP, stream.info.tags[TagTelegramIP]),
+		attribute.Int(TagDC, evt.DC))
+	stream.endStage(evt.Timestamp())
+	stream.startStage(o.factory.tracer, otlpSpanRelay, evt.Timestamp())
+
+	o.factory.metricTelegramConnections.Add(ctx, 1, stream.attrs(TagTelegramIP, TagDC))
+}
+
+func (o otlpProcessor) EventDomainFronting(evt mtglib.EventDomainFronting) {
+	stream, ok := o.streams[evt.StreamID()]
+	if !ok {
+		return
+	}
+
+	stream.info.isDomainFronted = true
+
+	stream.span.SetAttributes(attribute.Bool(otlpAttrDomainFronting, true))
+	stream.endStage(evt.Timestamp())
+	stream.startStage(o.factory.tracer, otlpSpanDomainFronting, evt.Timestamp())
+
+	ctx := context.Background()
+
+	o.factory.metricDomainFronting.Add(ctx, 1)
+	o.factory.metricDomainFrontingConnections.Add(ctx, 1, stream.attrs(TagIPFamily))
+}
+
+func (o otlpProcessor) EventTraffic(evt mtglib.EventTraffic) {
+	stream, ok := o.streams[evt.StreamID()]
+	if !ok {
+		return
+	}
+
+	direction := getDirection(evt.IsRead)
+
+	if evt.IsRead {
+		stream.trafficToClient += int64(evt.Traffic)
+	} else {
+		stream.trafficFromClient += int64(evt.Traffic)
+	}
+
+	if stream.info.isDomainFronted {
+		o.factory.metricDomainFrontingTraffic.Add(context.Background(),
+			int64(evt.Traffic),
+			metric.WithAttributes(attribute.String(TagDirection, direction)))
+	} else {
+		o.factory.metricTelegramTraffic.Add(context.Background(),
+			int64(evt.Traffic),
+			metric.WithAttributes(
+				attribute.String(TagTelegramIP, stream.info.tags[TagTelegramIP]),
+				attribute.String(TagDC, stream.info.tags[TagDC]),
+				attribute.String(TagDirection, direction)))
+	}
+}
+
+func (o otlpProcessor) EventFinish(evt mtglib.EventFinish) {
+	ctx := context.Background()
+
+	if evt.Duration > 0 {
+		o.factory.metricSessionDuration.Record(ctx, evt.Duration.Seconds())
+	}
+
+	stream, ok := o.streams[evt.StreamID()]
+	if !ok {
+		return
+	}
+
+	defer func() {
+		delete(o.streams, evt.StreamID())
+		releaseStreamInfo(stream.info)
+	}()
+
+	o.factory.metricClientConnections.Add(ctx, -1, stream.attrs(TagIPFamily))
+
+	if stream.info.isDomainFronted {
+		o.factory.metricDomainFrontingConnections.Add(ctx, -1, stream.attrs(TagIPFamily))
+	} else if _, ok := stream.info.tags[TagTelegramIP]; ok {
+		o.factory.metricTelegramConnections.Add(ctx, -1, stream.attrs(TagTelegramIP, TagDC))
+	}
+
+	stream.finish(evt.Timestamp())
+}
+
+func (o otlpProcessor) EventConcurrencyLimited(_ mtglib.EventConcurrencyLimited) {
+	o.factory.metricConcurrencyLimited.Add(context.Background(), 1)
+}
+
+func (o otlpProcessor) EventIPBlocklisted(evt mtglib.EventIPBlocklisted) {
+	tag := getIPList(evt.IsBlockList, evt.IsBanned)
+
+	attributes := []attribute.KeyValue{attribute.String(TagIPList, tag)}
+	if asn := o.factory.asns.get(evt.ASN); asn != "" {
+		attributes = append(attributes, attribute.String(TagASN, asn))
+	}
+
+	o.factory.metricIPBlocklisted.Add(context.Background(),
+		1,
+		metric.WithAttributes(attributes...))
+}
+
+func (o otlpProcessor) EventReplayAttack(evt mtglib.EventReplayAttack) {
+	o.factory.metricReplayAttacks.Add(context.Background(), 1)
+
+	if stream, ok := o.streams[evt.StreamID()]; ok {
+		stream.span.AddEvent(otlpEventReplayAttack, trace.WithTimestamp(evt.Timestamp()))
+	}
+}
+
+func (o otlpProcessor) EventPreviousSecretUsed(evt mtglib.EventPreviousSecretUsed) {
+	o.factory.metricPreviousSecretHandshakes.Add(context.Background(), 1)
+
+	if stream, ok := o.streams[evt.StreamID()]; ok {
+		stream.span.AddEvent(otlpEventPreviousSecret, trace.WithTimestamp(evt.Timestamp()))
+	}
+}
+
+func (o otlpProcessor) EventIPListSize(evt mtglib.EventIPListSize) {
+	tag := getIPList(evt.IsBlockList, evt.IsBanList)
+
+	o.factory.metricIPListSize.Record(context.Background(),
+		int64(evt.Size),
+		metric.WithAttributes(attribute.String(TagIPList, tag)))
+}
+
+func (o otlpProcessor) EventTelegramProbe(evt mtglib.EventTelegramProbe) {
+	if evt.Forgotten {
+		return
+	}
+
+	attrs := metric.WithAttributes(
+		attribute.String(TagTelegramIP, evt.RemoteIP.String()),
+		attribute.String(TagDC, strconv.Itoa(evt.DC)))
+
+	o.factory.metricTelegramProbeFailureRate.Record(context.Background(), evt.FailureRate, attrs)
+
+	if evt.RTT > 0 {
+		o.factory.metricTelegramProbeRTT.Record(context.Background(), evt.RTT.Seconds(), attrs)
+	}
+}
+
+func (o otlpProcessor) EventWarmPool(evt mtglib.EventWarmPool) {
+	instrument := o.factory.metricWarmPoolMisses
+	if evt.Hit {
+		instrument = o.factory.metricWarmPoolHits
+	}
+
+	instrument.Add(context.Background(),
+		1,
+		metric.WithAttributes(attribute.String(TagDC, strconv.Itoa(evt.DC))))
+
+	if stream, ok := o.streams[evt.StreamID()]; ok && stream.stage != nil {
+		stream.stage.SetAttributes(attribute.Bool(otlpAttrWarmPoolHit, evt.Hit))
+	}
+}
+
+func (o otlpProcessor) EventHandshakeFailed(evt mtglib.EventHandshakeFailed) {
+	o.factory.metricHandshakeFailures.Add(context.Background(),
+		1,
+		metric.WithAttributes(attribute.String(TagReason, string(evt.Reason))))
+
+	stream, ok := o.streams[evt.StreamID()]
+	if !ok || stream.stage == nil {
+		return
+	}
+
+	stream.stage.SetAttributes(attribute.String(TagReason, string(evt.Reason)))
+	stream.stage.SetStatus(codes.Error, string(evt.Reason))
+	stream.endStage(evt.Timestamp())
+}
+
+func (o otlpProcessor) EventClockSkew(evt mtglib.EventClockSkew) {
+	o.factory.metricClientClockSkew.Record(context.Background(),
+		evt.Skew.Abs().Seconds(),
+		metric.WithAttributes(attribute.String(TagResult, getClockSkewResult(evt.Accepted))))
+
+	if stream, ok := o.streams[evt.StreamID()]; ok && stream.stage != nil {
+		stream.stage.SetAttributes(attribute.Float64(otlpAttrClockSkew, evt.Skew.Seconds()))
+	}
+}
+
+func (o otlpProcessor) EventHandshakeSucceeded(evt mtglib.EventHandshakeSucceeded) {
+	ctx := context.Background()
+
+	o.factory.metricFakeTLSHandshakeDuration.Record(ctx, evt.FakeTLSDuration.Seconds())
+	o.factory.metricObfuscatedHandshakeDuration.Record(ctx, evt.ObfuscatedDuration.Seconds())
+
+	stream, ok := o.streams[evt.StreamID()]
+	if !ok {
+		return
+	}
+
+	stream.span.SetAttributes(attribute.String(otlpAttrSecret, evt.SecretName()))
+	stream.endStage(evt.Timestamp())
+	stream.startStage(o.factory.tracer, otlpSpanDCDial, evt.Timestamp())
+}
+
+func (o otlpProcessor) EventOverflow(evt mtglib.EventOverflow) {
+	ctx := context.Background()
+
+	o.factory.metricEventsOverflow.Add(ctx,
+		int64(evt.Dropped),
+		metric.WithAttributes(attribute.String(TagAction, TagActionDropped)))
+	o.factory.metricEventsOverflow.Add(ctx,
+		int64(evt.Coalesced),
+		metric.WithAttributes(attribute.String(TagAction, TagActionCoalesced)))
+}
+
+func (o otlpProcessor) EventRateLimited(evt mtglib.EventRateLimited) {
+	o.factory.metricRateLimited.Add(context.Background(),
+		1,
+		metric.WithAttributes(attribute.String(TagReason, string(evt.Reason))))
+}
+
+func (o otlpProcessor) EventIPBanned(evt mtglib.EventIPBanned) {
+	o.factory.metricIPBans.Add(context.Background(),
+		1,
+		metric.WithAttributes(attribute.String(TagReason, string(evt.Reason))))
+}
+
+func (o otlpProcessor) Shutdown() {
+	now := time.Now()
+
+	for k, v := range o.streams {
+		v.finish(now)
+		releaseStreamInfo(v.info)
+		delete(o.streams, k)
+	}
+}
+
+// OTLPFactory is a factory of [events.Observer] which push metrics and
+// traces to OpenTelemetry collector by OTLP/HTTP.
+//
+// Metrics are the same as for Prometheus and statsd. Each stream is
+// a trace with a root span and spans of its stages: handshake, dial to
+// Telegram DC and relay (or domain fronting). Trace id is made of bytes
+// of stream id.
+//
+// Like statsd, this factory won't use [mtglib.Network].
+type OTLPFactory struct {
+	meterProvider  *sdkmetric.MeterProvider
+	tracerProvider *sdktrace.TracerProvider
+	tracer         trace.Tracer
+	asns           asnTags
+
+	metricClientConnections         metric.Int64UpDownCounter
+	metricTelegramConnections       metric.Int64UpDownCounter
+	metricDomainFrontingConnections metric.Int64UpDownCounter
+
+	metricIPListSize               metric.Int64Gauge
+	metricTelegramProbeRTT         metric.Float64Gauge
+	metricTelegramProbeFailureRate metric.Float64Gauge
+
+	metricTelegramTraffic          metric.Int64Counter
+	metricDomainFrontingTraffic    metric.Int64Counter
+	metricIPBlocklisted            metric.Int64Counter
+	metricWarmPoolHits             metric.Int64Counter
+	metricWarmPoolMisses           metric.Int64Counter
+	metricHandshakeFailures        metric.Int64Counter
+	metricEventsOverflow           metric.Int64Counter
+	metricRateLimited              metric.Int64Counter
+	metricIPBans                   metric.Int64Counter
+	metricDomainFronting           metric.Int64Counter
+	metricConcurrencyLimited       metric.Int64Counter
+	metricReplayAttacks            metric.Int64Counter
+	metricPreviousSecretHandshakes metric.Int64Counter
+
+	metricClientClockSkew             metric.Float64Histogram
+	metricTelegramDialDuration        metric.Float64Histogram
+	metricFakeTLSHandshakeDuration    metric.Float64Histogram
+	metricObfuscatedHandshakeDuration metric.Float64Histogram
+	metricSessionDuration             metric.Float64Histogram
+}
+
+// Make builds a new observer.
+func (o *OTLPFactory) Make() events.Observer {
+	return otlpProcessor{
+		streams: make(map[string]*otlpStream),
+		factory: o,
+	}
+}
+
+// Close pushes collected data and stops a factory.
+func (o *OTLPFactory) Close() error {
+	ctx := context.Background()
+
+	return errors.Join(o.tracerProvider.Shutdown(ctx), o.meterProvider.Shutdown(ctx))
+}
+
+// NewOTLP builds an [events.ObserverFactory] that pushes metrics and
+// traces to OTLP/HTTP endpoint, host:port of the collector. basePath is
+// a path collector is mounted at; metrics and traces are sent to
+// basePath/v1/metrics and basePath/v1/traces.
+//
+// If insecure is true, plain HTTP is used instead of HTTPS. asns are
+// autonomous systems which have their own values of 'asn' attribute,
+// others are reported as [TagASNOther].
+func NewOTLP(endpoint, basePath, metricPrefix string,
+	insecure bool, pushInterval time.Duration,
+	asns []uint,
+) (*OTLPFactory, error) {
+	ctx := context.Background()
+	metricOpts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(endpoint),
+		otlpmetrichttp.WithURLPath(path.Join("/", basePath, "v1/metrics")),
+	}
+	traceOpts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpoint),
+		otlptracehttp.WithURLPath(path.Join("/", basePath, "v1/traces")),
+	}
+
+	if insecure {
+		metricOpts = append(metricOpts, otlpmetrichttp.WithInsecure())
+		traceOpts = append(traceOpts, otlptracehttp.WithInsecure())
+	}
+
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build metric exporter: %w", err)
+	}
+
+	traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build trace exporter: %w", err)
+	}
+
+	if pushInterval == 0 {
+		pushInterval = DefaultOTLPPushInterval
+	}
+
+	res := resource.NewSchemaless(attribute.String("service.name", OTLPServiceName))
+	factory := &OTLPFactory{
+		meterProvider: sdkmetric.NewMeterProvider(
+			sdkmetric.WithResource(res),
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter,
+				sdkmetric.WithInterval(pushInterval)))),
+		tracerProvider: sdktrace.NewTracerProvider(
+			sdktrace.WithResource(res),
+			sdktrace.WithIDGenerator(otlpIDGenerator{}),
+			sdktrace.WithBatcher(traceExporter)),
+		asns: newASNTags(asns),
+	}
+	factory.tracer = factory.tracerProvider.Tracer(OTLPServiceName)
+
+	if err := factory.makeInstruments(metricPrefix); err != nil {
+		factory.Close() //nolint: errcheck
+
+		return nil, err
+	}
+
+	return factory, nil
+}
+
+func (o *OTLPFactory) makeInstruments(metricPrefix string) error { //nolint: funlen
+	meter := o.meterProvider.Meter(OTLPServiceName)
+	errs := []error{}
+	name := func(metricName string) string {
+		if metricPrefix == "" {
+			return metricName
+		}
+
+		return metricPrefix + "." + metricName
+	}
+	collect := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	upDownCounter := func(metricName, description string) metric.Int64UpDownCounter {
+		instrument, err := meter.Int64UpDownCounter(name(metricName), metric.WithDescription(description))
+		collect(err)
+
+		return instrument
+	}
+	counter := func(metricName, description string) metric.Int64Counter {
+		instrument, err := meter.Int64Counter(name(metricName), metric.WithDescription(description))
+		collect(err)
+
+		return instrument
+	}
+	gauge := func(metricName, description string) metric.Float64Gauge {
+		instrument, err := meter.Float64Gauge(name(metricName), metric.WithDescription(description))
+		collect(err)
+
+		return instrument
+	}
+	histogram := func(metricName, description string, buckets []float64) metric.Float64Histogram {
+		instrument, err := meter.Float64Histogram(name(metricName),
+			metric.WithDescription(description),
+			metric.WithUnit("s"),
+			metric.WithExplicitBucketBoundaries(buckets...))
+		collect(err)
+
+		return instrument
+	}
+
+	o.metricClientConnections = upDownCounter(MetricClientConnections,
+		"A number of actively processing client connections.")
+	o.metricTelegramConnections = upDownCounter(MetricTelegramConnections,
+		"A number of connections to Telegram servers.")
+	o.metricDomainFrontingConnections = upDownCounter(MetricDomainFrontingConnections,
+		"A number of connections which talk to front domain.")
+
+	ipListSize, err := meter.Int64Gauge(name(MetricIPListSize),
+		metric.WithDescription("A size of the ip list (blocklist or allowlist) or a number of active bans"))
+	collect(err)
+
+	o.metricIPListSize = ipListSize
+	o.metricTelegramProbeRTT = gauge(MetricTelegramProbeRTT,
+		"A time of connecting to Telegram server by prober, in seconds.")
+	o.metricTelegramProbeFailureRate = gauge(MetricTelegramProbeFailureRate,
+		"A rate of failed probes of Telegram server.")
+
+	o.metricTelegramTraffic = counter(MetricTelegramTraffic,
+		"Traffic which is generated talking with Telegram servers.")
+	o.metricDomainFrontingTraffic = counter(MetricDomainFrontingTraffic,
+		"Traffic which is generated talking with front domain.")
+	o.metricIPBlocklisted = counter(MetricIPBlocklisted,
+		"A number of rejected sessions due to ip blocklisting.")
+	o.metricWarmPoolHits = counter(MetricWarmPoolHits,
+		"A number of sessions which got a pre-dialed connection to Telegram.")
+	o.metricWarmPoolMisses = counter(MetricWarmPoolMisses,
+		"A number of sessions which had to dial Telegram because the pool was empty.")
+	o.metricHandshakeFailures = counter(MetricHandshakeFailures,
+		"A number of failed client handshakes.")
+	o.metricEventsOverflow = counter(MetricEventsOverflow,
+		"A number of events which were dropped or coalesced because observers were slow.")
+	o.metricRateLimited = counter(MetricRateLimited,
+		"A number of connections of clients which have exceeded their limits.")
+	o.metricIPBans = counter(MetricIPBans,
+		"A number of client IPs which were banned for failed handshakes.")
+	o.metricDomainFronting = counter(MetricDomainFronting,
+		"A number of routings to front domain.")
+	o.metricConcurrencyLimited = counter(MetricConcurrencyLimited,
+		"A number of sessions that were rejected by concurrency limiter.")
+	o.metricReplayAttacks = counter(MetricReplayAttacks,
+		"A number of detected replay attacks.")
+	o.metricPreviousSecretHandshakes = counter(MetricPreviousSecretHandshakes,
+		"A number of handshakes made with a previous secret.")
+
+	o.metricClientClockSkew = histogram(MetricClientClockSkew,
+		"An absolute difference between client clock and mtg clock.",
+		ClockSkewBuckets)
+	o.metricTelegramDialDuration = histogram(MetricTelegramDialDuration,
+		"A time of connecting to Telegram server for a client session.",
+		LatencyBuckets)
+	o.metricFakeTLSHandshakeDuration = histogram(MetricFakeTLSHandshakeDuration,
+		"A time of FakeTLS handshake.",
+		LatencyBuckets)
+	o.metricObfuscatedHandshakeDuration = histogram(MetricObfuscatedHandshakeDuration,
+		"A time of obfuscated handshake.",
+		LatencyBuckets)
+	o.metricSessionDuration = histogram(MetricSessionDuration,
+		"A duration of client sessions.",
+		SessionDurationBuckets)
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("cannot create instruments: %w", err)
+	}
+
+	return nil
+}
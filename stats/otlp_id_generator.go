@@ -0,0 +1,45 @@
+package stats
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"math/rand/v2"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type otlpStreamIDKey struct{}
+
+// otlpIDGenerator makes trace id from a stream id, so it is possible to
+// find a trace of the stream by a stream id from logs or admin api.
+// Stream ids are random, so they are as good as random trace ids.
+type otlpIDGenerator struct{}
+
+func (o otlpIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	traceID := trace.TraceID{}
+
+	streamID, _ := ctx.Value(otlpStreamIDKey{}).(string)
+	decoded, err := base64.RawURLEncoding.DecodeString(streamID)
+
+	if err == nil && len(decoded) == len(traceID) {
+		copy(traceID[:], decoded)
+	} else {
+		binary.BigEndian.PutUint64(traceID[:8], rand.Uint64()) //nolint: gosec
+		binary.BigEndian.PutUint64(traceID[8:], rand.Uint64()) //nolint: gosec
+	}
+
+	return traceID, o.NewSpanID(ctx, traceID)
+}
+
+func (o otlpIDGenerator) NewSpanID(_ context.Context, _ trace.TraceID) trace.SpanID {
+	spanID := trace.SpanID{}
+
+	binary.BigEndian.PutUint64(spanID[:], rand.Uint64()) //nolint: gosec
+
+	return spanID
+}
+
+func withOTLPStreamID(ctx context.Context, streamID string) context.Context {
+	return context.WithValue(ctx, otlpStreamIDKey{}, streamID)
+}
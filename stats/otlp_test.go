@@ -0,0 +1,271 @@
+package stats_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/events"
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/9seconds/mtg/v2/stats"
+	"github.com/stretchr/testify/suite"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver is an in-process OTLP/HTTP collector which keeps
+// everything it has received.
+type otlpReceiver struct {
+	mutex   sync.Mutex
+	metrics []*metricpb.Metric
+	spans   []*tracepb.Span
+}
+
+func (o *otlpReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	switch req.URL.Path {
+	case "/v1/metrics":
+		msg := &colmetricpb.ExportMetricsServiceRequest{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		for _, resource := range msg.GetResourceMetrics() {
+			for _, scope := range resource.GetScopeMetrics() {
+				o.metrics = append(o.metrics, scope.GetMetrics()...)
+			}
+		}
+	case "/v1/traces":
+		msg := &coltracepb.ExportTraceServiceRequest{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		for _, resource := range msg.GetResourceSpans() {
+			for _, scope := range resource.GetScopeSpans() {
+				o.spans = append(o.spans, scope.GetSpans()...)
+			}
+		}
+	default:
+		http.NotFound(w, req)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+}
+
+func (o *otlpReceiver) Metric(name string) *metricpb.Metric {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	var rv *metricpb.Metric
+
+	for _, v := range o.metrics {
+		if v.GetName() == name {
+			rv = v
+		}
+	}
+
+	return rv
+}
+
+func (o *otlpReceiver) Spans() map[string]*tracepb.Span {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	rv := map[string]*tracepb.Span{}
+
+	for _, v := range o.spans {
+		rv[v.GetName()] = v
+	}
+
+	return rv
+}
+
+type OTLPTestSuite struct {
+	suite.Suite
+
+	receiver *otlpReceiver
+	server   *httptest.Server
+	factory  *stats.OTLPFactory
+	otlp     events.Observer
+}
+
+func (suite *OTLPTestSuite) SetupTest() {
+	suite.receiver = &otlpReceiver{}
+	suite.server = httptest.NewServer(suite.receiver)
+
+	factory, err := stats.NewOTLP(
+		strings.TrimPrefix(suite.server.URL, "http://"),
+		"",
+		"mtg",
+		true,
+		time.Hour,
+		nil)
+	suite.Require().NoError(err)
+
+	suite.factory = factory
+	suite.otlp = factory.Make()
+}
+
+func (suite *OTLPTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// Flush closes a factory, so everything is pushed to the receiver.
+func (suite *OTLPTestSuite) Flush() {
+	suite.otlp.Shutdown()
+	suite.NoError(suite.factory.Close())
+}
+
+func (suite *OTLPTestSuite) TestTelegramPath() {
+	streamID := base64.RawURLEncoding.EncodeToString([]byte("0123456789abcdef"))
+
+	suite.otlp.EventStart(mtglib.NewEventStart(streamID, net.ParseIP("10.0.0.10")))
+	suite.otlp.EventClockSkew(mtglib.NewEventClockSkew(streamID, time.Second, true))
+	suite.otlp.EventHandshakeSucceeded(
+		mtglib.NewEventHandshakeSucceeded(streamID, time.Second, 500*time.Millisecond))
+	suite.otlp.EventWarmPool(mtglib.NewEventWarmPool(streamID, 2, false))
+
+	connectedEvt := mtglib.NewEventConnectedToDC(streamID, net.ParseIP("10.0.0.1"), 2)
+	connectedEvt.DialDuration = 250 * time.Millisecond
+	suite.otlp.EventConnectedToDC(connectedEvt)
+	suite.otlp.EventTraffic(mtglib.NewEventTraffic(streamID, 200, true))
+	suite.otlp.EventTraffic(mtglib.NewEventTraffic(streamID, 100, false))
+
+	finishEvt := mtglib.NewEventFinish(streamID)
+	finishEvt.Duration = time.Minute
+	suite.otlp.EventFinish(finishEvt)
+
+	suite.Flush()
+
+	connections := suite.receiver.Metric("mtg.client_connections")
+	suite.Require().NotNil(connections)
+	suite.EqualValues(0, connections.GetSum().GetDataPoints()[0].GetAsInt())
+
+	traffic := suite.receiver.Metric("mtg.telegram_traffic")
+	suite.Require().NotNil(traffic)
+	suite.Len(traffic.GetSum().GetDataPoints(), 2)
+
+	dial := suite.receiver.Metric("mtg.telegram_dial_duration")
+	suite.Require().NotNil(dial)
+	suite.InDelta(0.25, dial.GetHistogram().GetDataPoints()[0].GetSum(), 0.001)
+
+	session := suite.receiver.Metric("mtg.session_duration")
+	suite.Require().NotNil(session)
+	suite.InDelta(60, session.GetHistogram().GetDataPoints()[0].GetSum(), 0.001)
+
+	suite.NotNil(suite.receiver.Metric("mtg.faketls_handshake_duration"))
+	suite.NotNil(suite.receiver.Metric("mtg.obfuscated_handshake_duration"))
+	suite.NotNil(suite.receiver.Metric("mtg.client_clock_skew"))
+	suite.NotNil(suite.receiver.Metric("mtg.warm_pool_misses"))
+
+	spans := suite.receiver.Spans()
+	suite.Len(spans, 4)
+
+	root := spans["stream"]
+	suite.Require().NotNil(root)
+	suite.Equal(hex.EncodeToString([]byte("0123456789abcdef")), hex.EncodeToString(root.GetTraceId()))
+	suite.Empty(root.GetParentSpanId())
+
+	for _, name := range []string{"handshake", "dc_dial", "relay"} {
+		span := spans[name]
+
+		suite.Require().NotNil(span, name)
+		suite.Equal(root.GetTraceId(), span.GetTraceId())
+		suite.Equal(root.GetSpanId(), span.GetParentSpanId())
+	}
+
+	suite.LessOrEqual(spans["handshake"].GetEndTimeUnixNano(), spans["dc_dial"].GetStartTimeUnixNano())
+	suite.LessOrEqual(spans["dc_dial"].GetEndTimeUnixNano(), spans["relay"].GetStartTimeUnixNano())
+}
+
+func (suite *OTLPTestSuite) TestFailedHandshake() {
+	suite.otlp.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	suite.otlp.EventHandshakeFailed(
+		mtglib.NewEventHandshakeFailed("connID", net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureBadHMAC))
+	suite.otlp.EventDomainFronting(mtglib.NewEventDomainFronting("connID"))
+	suite.otlp.EventTraffic(mtglib.NewEventTraffic("connID", 100, true))
+	suite.otlp.EventFinish(mtglib.NewEventFinish("connID"))
+
+	suite.Flush()
+
+	failures := suite.receiver.Metric("mtg.handshake_failures")
+	suite.Require().NotNil(failures)
+	suite.EqualValues(1, failures.GetSum().GetDataPoints()[0].GetAsInt())
+
+	suite.NotNil(suite.receiver.Metric("mtg.domain_fronting_traffic"))
+
+	spans := suite.receiver.Spans()
+	suite.Len(spans, 3)
+	suite.Equal(tracepb.Status_STATUS_CODE_ERROR, spans["handshake"].GetStatus().GetCode())
+	suite.Equal("bad_hmac", spans["handshake"].GetStatus().GetMessage())
+	suite.NotNil(spans["domain_fronting"])
+}
+
+func (suite *OTLPTestSuite) TestUnfinishedStream() {
+	suite.otlp.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+
+	suite.Flush()
+
+	spans := suite.receiver.Spans()
+	suite.Len(spans, 2)
+	suite.NotNil(spans["stream"])
+	suite.NotNil(spans["handshake"])
+}
+
+func (suite *OTLPTestSuite) TestBasePath() {
+	receiver := &otlpReceiver{}
+	mux := http.NewServeMux()
+	mux.Handle("/otlp/", http.StripPrefix("/otlp", receiver))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	factory, err := stats.NewOTLP(
+		strings.TrimPrefix(server.URL, "http://"),
+		"/otlp",
+		"mtg",
+		true,
+		time.Hour,
+		nil)
+	suite.Require().NoError(err)
+
+	observer := factory.Make()
+	observer.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	observer.EventFinish(mtglib.NewEventFinish("connID"))
+	observer.Shutdown()
+	suite.NoError(factory.Close())
+
+	suite.NotNil(receiver.Metric("mtg.client_connections"))
+	suite.NotEmpty(receiver.Spans())
+}
+
+func TestOTLP(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &OTLPTestSuite{})
+}
@@ -34,6 +34,12 @@ func (p prometheusProcessor) EventStart(evt mtglib.EventStart) {
 }
 
 func (p prometheusProcessor) EventConnectedToDC(evt mtglib.EventConnectedToDC) {
+	if evt.DialDuration > 0 {
+		p.factory.metricTelegramDialDuration.
+			WithLabelValues(evt.RemoteIP.String(), strconv.Itoa(evt.DC)).
+			Observe(evt.DialDuration.Seconds())
+	}
+
 	info, ok := p.streams[evt.StreamID()]
 	if !ok {
 		return
@@ -81,6 +87,10 @@ func (p prometheusProcessor) EventTraffic(evt mtglib.EventTraffic) {
 }
 
 func (p prometheusProcessor) EventFinish(evt mtglib.EventFinish) {
+	if evt.Duration > 0 {
+		p.factory.metricSessionDuration.Observe(evt.Duration.Seconds())
+	}
+
 	info, ok := p.streams[evt.StreamID()]
 	if !ok {
 		return
@@ -111,27 +121,84 @@ func (p prometheusProcessor) EventConcurrencyLimited(_ mtglib.EventConcurrencyLi
 }
 
 func (p prometheusProcessor) EventIPBlocklisted(evt mtglib.EventIPBlocklisted) {
-	tag := TagIPListBlock
-	if !evt.IsBlockList {
-		tag = TagIPListAllow
-	}
+	tag := getIPList(evt.IsBlockList, evt.IsBanned)
 
-	p.factory.metricIPBlocklisted.WithLabelValues(tag).Inc()
+	p.factory.metricIPBlocklisted.WithLabelValues(tag, p.factory.asns.get(evt.ASN)).Inc()
 }
 
 func (p prometheusProcessor) EventReplayAttack(_ mtglib.EventReplayAttack) {
 	p.factory.metricReplayAttacks.Inc()
 }
 
+func (p prometheusProcessor) EventPreviousSecretUsed(_ mtglib.EventPreviousSecretUsed) {
+	p.factory.metricPreviousSecretHandshakes.Inc()
+}
+
 func (p prometheusProcessor) EventIPListSize(evt mtglib.EventIPListSize) {
-	tag := TagIPListBlock
-	if !evt.IsBlockList {
-		tag = TagIPListAllow
-	}
+	tag := getIPList(evt.IsBlockList, evt.IsBanList)
 
 	p.factory.metricIPListSize.WithLabelValues(tag).Set(float64(evt.Size))
 }
 
+func (p prometheusProcessor) EventTelegramProbe(evt mtglib.EventTelegramProbe) {
+	telegramIP := evt.RemoteIP.String()
+	dc := strconv.Itoa(evt.DC)
+
+	if evt.Forgotten {
+		p.factory.metricTelegramProbeFailureRate.DeleteLabelValues(telegramIP, dc)
+		p.factory.metricTelegramProbeRTT.DeleteLabelValues(telegramIP, dc)
+
+		return
+	}
+
+	p.factory.metricTelegramProbeFailureRate.
+		WithLabelValues(telegramIP, dc).
+		Set(evt.FailureRate)
+
+	if evt.RTT > 0 {
+		p.factory.metricTelegramProbeRTT.
+			WithLabelValues(telegramIP, dc).
+			Set(evt.RTT.Seconds())
+	}
+}
+
+func (p prometheusProcessor) EventWarmPool(evt mtglib.EventWarmPool) {
+	metric := p.factory.metricWarmPoolMisses
+	if evt.Hit {
+		metric = p.factory.metricWarmPoolHits
+	}
+
+	metric.WithLabelValues(strconv.Itoa(evt.DC)).Inc()
+}
+
+func (p prometheusProcessor) EventHandshakeFailed(evt mtglib.EventHandshakeFailed) {
+	p.factory.metricHandshakeFailures.WithLabelValues(string(evt.Reason)).Inc()
+}
+
+func (p prometheusProcessor) EventClockSkew(evt mtglib.EventClockSkew) {
+	p.factory.metricClientClockSkew.
+		WithLabelValues(getClockSkewResult(evt.Accepted)).
+		Observe(evt.Skew.Abs().Seconds())
+}
+
+func (p prometheusProcessor) EventHandshakeSucceeded(evt mtglib.EventHandshakeSucceeded) {
+	p.factory.metricFakeTLSHandshakeDuration.Observe(evt.FakeTLSDuration.Seconds())
+	p.factory.metricObfuscatedHandshakeDuration.Observe(evt.ObfuscatedDuration.Seconds())
+}
+
+func (p prometheusProcessor) EventOverflow(evt mtglib.EventOverflow) {
+	p.factory.metricEventsOverflow.WithLabelValues(TagActionDropped).Add(float64(evt.Dropped))
+	p.factory.metricEventsOverflow.WithLabelValues(TagActionCoalesced).Add(float64(evt.Coalesced))
+}
+
+func (p prometheusProcessor) EventRateLimited(evt mtglib.EventRateLimited) {
+	p.factory.metricRateLimited.WithLabelValues(string(evt.Reason)).Inc()
+}
+
+func (p prometheusProcessor) EventIPBanned(evt mtglib.EventIPBanned) {
+	p.factory.metricIPBans.WithLabelValues(string(evt.Reason)).Inc()
+}
+
 func (p prometheusProcessor) Shutdown() {
 	for k, v := range p.streams {
 		releaseStreamInfo(v)
@@ -146,19 +213,36 @@ func (p prometheusProcessor) Shutdown() {
 // server with a single endpoint - a Prometheus-compatible scrape output.
 type PrometheusFactory struct {
 	httpServer *http.Server
+	asns       asnTags
 
 	metricClientConnections         *prometheus.GaugeVec
 	metricTelegramConnections       *prometheus.GaugeVec
 	metricDomainFrontingConnections *prometheus.GaugeVec
 	metricIPListSize                *prometheus.GaugeVec
+	metricTelegramProbeRTT          *prometheus.GaugeVec
+	metricTelegramProbeFailureRate  *prometheus.GaugeVec
 
 	metricTelegramTraffic       *prometheus.CounterVec
 	metricDomainFrontingTraffic *prometheus.CounterVec
 	metricIPBlocklisted         *prometheus.CounterVec
-
-	metricDomainFronting     prometheus.Counter
-	metricConcurrencyLimited prometheus.Counter
-	metricReplayAttacks      prometheus.Counter
+	metricWarmPoolHits          *prometheus.CounterVec
+	metricWarmPoolMisses        *prometheus.CounterVec
+	metricHandshakeFailures     *prometheus.CounterVec
+	metricEventsOverflow        *prometheus.CounterVec
+	metricRateLimited           *prometheus.CounterVec
+	metricIPBans                *prometheus.CounterVec
+
+	metricClientClockSkew      *prometheus.HistogramVec
+	metricTelegramDialDuration *prometheus.HistogramVec
+
+	metricFakeTLSHandshakeDuration    prometheus.Histogram
+	metricObfuscatedHandshakeDuration prometheus.Histogram
+	metricSessionDuration             prometheus.Histogram
+
+	metricDomainFronting           prometheus.Counter
+	metricConcurrencyLimited       prometheus.Counter
+	metricReplayAttacks            prometheus.Counter
+	metricPreviousSecretHandshakes prometheus.Counter
 }
 
 // Make builds a new observer.
@@ -181,8 +265,10 @@ func (p *PrometheusFactory) Close() error {
 }
 
 // NewPrometheus builds an events.ObserverFactory which can serve HTTP
-// endpoint with Prometheus scrape data.
-func NewPrometheus(metricPrefix, httpPath string) *PrometheusFactory { //nolint: funlen
+// endpoint with Prometheus scrape data. asns are autonomous systems
+// which have their own values of 'asn' label, others are reported as
+// [TagASNOther].
+func NewPrometheus(metricPrefix, httpPath string, asns []uint) *PrometheusFactory { //nolint: funlen
 	registry := prometheus.NewPedanticRegistry()
 	httpHandler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{
 		EnableOpenMetrics: true,
@@ -195,6 +281,7 @@ func NewPrometheus(metricPrefix, httpPath string) *PrometheusFactory { //nolint:
 		httpServer: &http.Server{
 			Handler: mux,
 		},
+		asns: newASNTags(asns),
 
 		metricClientConnections: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: metricPrefix,
@@ -214,8 +301,18 @@ func NewPrometheus(metricPrefix, httpPath string) *PrometheusFactory { //nolint:
 		metricIPListSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: metricPrefix,
 			Name:      MetricIPListSize,
-			Help:      "A size of the ip list (blocklist or allowlist)",
+			Help:      "A size of the ip list (blocklist or allowlist) or a number of active bans",
 		}, []string{TagIPList}),
+		metricTelegramProbeRTT: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricPrefix,
+			Name:      MetricTelegramProbeRTT,
+			Help:      "A time of connecting to Telegram server by prober, in seconds.",
+		}, []string{TagTelegramIP, TagDC}),
+		metricTelegramProbeFailureRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricPrefix,
+			Name:      MetricTelegramProbeFailureRate,
+			Help:      "A rate of failed probes of Telegram server.",
+		}, []string{TagTelegramIP, TagDC}),
 
 		metricTelegramTraffic: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: metricPrefix,
@@ -231,7 +328,69 @@ func NewPrometheus(metricPrefix, httpPath string) *PrometheusFactory { //nolint:
 			Namespace: metricPrefix,
 			Name:      MetricIPBlocklisted,
 			Help:      "A number of rejected sessions due to ip blocklisting.",
-		}, []string{TagIPList}),
+		}, []string{TagIPList, TagASN}),
+		metricWarmPoolHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricPrefix,
+			Name:      MetricWarmPoolHits,
+			Help:      "A number of sessions which got a pre-dialed connection to Telegram.",
+		}, []string{TagDC}),
+		metricWarmPoolMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricPrefix,
+			Name:      MetricWarmPoolMisses,
+			Help:      "A number of sessions which had to dial Telegram because the pool was empty.",
+		}, []string{TagDC}),
+		metricHandshakeFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricPrefix,
+			Name:      MetricHandshakeFailures,
+			Help:      "A number of failed client handshakes.",
+		}, []string{TagReason}),
+		metricEventsOverflow: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricPrefix,
+			Name:      MetricEventsOverflow,
+			Help:      "A number of events which were dropped or coalesced because observers were slow.",
+		}, []string{TagAction}),
+		metricRateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricPrefix,
+			Name:      MetricRateLimited,
+			Help:      "A number of connections of clients which have exceeded their limits.",
+		}, []string{TagReason}),
+		metricIPBans: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricPrefix,
+			Name:      MetricIPBans,
+			Help:      "A number of client IPs which were banned for failed handshakes.",
+		}, []string{TagReason}),
+
+		metricClientClockSkew: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricPrefix,
+			Name:      MetricClientClockSkew,
+			Help:      "An absolute difference between client clock and mtg clock, in seconds.",
+			Buckets:   ClockSkewBuckets,
+		}, []string{TagResult}),
+		metricTelegramDialDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricPrefix,
+			Name:      MetricTelegramDialDuration,
+			Help:      "A time of connecting to Telegram server for a client session, in seconds.",
+			Buckets:   LatencyBuckets,
+		}, []string{TagTelegramIP, TagDC}),
+
+		metricFakeTLSHandshakeDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricPrefix,
+			Name:      MetricFakeTLSHandshakeDuration,
+			Help:      "A time of FakeTLS handshake, in seconds.",
+			Buckets:   LatencyBuckets,
+		}),
+		metricObfuscatedHandshakeDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricPrefix,
+			Name:      MetricObfuscatedHandshakeDuration,
+			Help:      "A time of obfuscated handshake, in seconds.",
+			Buckets:   LatencyBuckets,
+		}),
+		metricSessionDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricPrefix,
+			Name:      MetricSessionDuration,
+			Help:      "A duration of client sessions, in seconds.",
+			Buckets:   SessionDurationBuckets,
+		}),
 
 		metricDomainFronting: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: metricPrefix,
@@ -248,20 +407,41 @@ func NewPrometheus(metricPrefix, httpPath string) *PrometheusFactory { //nolint:
 			Name:      MetricReplayAttacks,
 			Help:      "A number of detected replay attacks.",
 		}),
+		metricPreviousSecretHandshakes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricPrefix,
+			Name:      MetricPreviousSecretHandshakes,
+			Help:      "A number of handshakes made with a previous secret.",
+		}),
 	}
 
 	registry.MustRegister(factory.metricClientConnections)
 	registry.MustRegister(factory.metricTelegramConnections)
 	registry.MustRegister(factory.metricDomainFrontingConnections)
 	registry.MustRegister(factory.metricIPListSize)
+	registry.MustRegister(factory.metricTelegramProbeRTT)
+	registry.MustRegister(factory.metricTelegramProbeFailureRate)
 
 	registry.MustRegister(factory.metricTelegramTraffic)
 	registry.MustRegister(factory.metricDomainFrontingTraffic)
 	registry.MustRegister(factory.metricIPBlocklisted)
+	registry.MustRegister(factory.metricWarmPoolHits)
+	registry.MustRegister(factory.metricWarmPoolMisses)
+	registry.MustRegister(factory.metricHandshakeFailures)
+	registry.MustRegister(factory.metricEventsOverflow)
+	registry.MustRegister(factory.metricRateLimited)
+	registry.MustRegister(factory.metricIPBans)
+
+	registry.MustRegister(factory.metricClientClockSkew)
+	registry.MustRegister(factory.metricTelegramDialDuration)
+
+	registry.MustRegister(factory.metricFakeTLSHandshakeDuration)
+	registry.MustRegister(factory.metricObfuscatedHandshakeDuration)
+	registry.MustRegister(factory.metricSessionDuration)
 
 	registry.MustRegister(factory.metricDomainFronting)
 	registry.MustRegister(factory.metricConcurrencyLimited)
 	registry.MustRegister(factory.metricReplayAttacks)
+	registry.MustRegister(factory.metricPreviousSecretHandshakes)
 
 	return factory
 }
@@ -42,7 +42,7 @@ func (suite *PrometheusTestSuite) Get() (string, error) {
 
 func (suite *PrometheusTestSuite) SetupTest() {
 	suite.httpListener, _ = net.Listen("tcp", "127.0.0.1:0")
-	suite.factory = stats.NewPrometheus("mtg", "/")
+	suite.factory = stats.NewPrometheus("mtg", "/", []uint{14061})
 	suite.prometheus = suite.factory.Make()
 
 	go suite.factory.Serve(suite.httpListener) //nolint: errcheck
@@ -156,7 +156,7 @@ func (suite *PrometheusTestSuite) TestEventIPBlocklisted() {
 
 	data, err := suite.Get()
 	suite.NoError(err)
-	suite.Contains(data, `mtg_ip_blocklisted{ip_list="blocklist"} 1`)
+	suite.Contains(data, `mtg_ip_blocklisted{asn="",ip_list="blocklist"} 1`)
 }
 
 func (suite *PrometheusTestSuite) TestEventIPAllowlisted() {
@@ -167,7 +167,47 @@ func (suite *PrometheusTestSuite) TestEventIPAllowlisted() {
 
 	data, err := suite.Get()
 	suite.NoError(err)
-	suite.Contains(data, `mtg_ip_blocklisted{ip_list="allowlist"} 1`)
+	suite.Contains(data, `mtg_ip_blocklisted{asn="",ip_list="allowlist"} 1`)
+}
+
+func (suite *PrometheusTestSuite) TestEventIPBanlisted() {
+	suite.prometheus.EventIPBlocklisted(
+		mtglib.NewEventIPBanlisted(net.ParseIP("2001:db8::68")))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_ip_blocklisted{asn="",ip_list="banlist"} 1`)
+}
+
+func (suite *PrometheusTestSuite) TestEventIPBlocklistedASN() {
+	evt := mtglib.NewEventIPBlocklisted(net.ParseIP("2001:db8::68"))
+	evt.ASN = 14061
+
+	suite.prometheus.EventIPBlocklisted(evt)
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_ip_blocklisted{asn="14061",ip_list="blocklist"} 1`)
+}
+
+func (suite *PrometheusTestSuite) TestEventIPBlocklistedOtherASN() {
+	for _, asn := range []uint{16509, 24940} {
+		evt := mtglib.NewEventIPBlocklisted(net.ParseIP("2001:db8::68"))
+		evt.ASN = asn
+
+		suite.prometheus.EventIPBlocklisted(evt)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_ip_blocklisted{asn="other",ip_list="blocklist"} 2`)
+	suite.NotContains(data, `16509`)
 }
 
 func (suite *PrometheusTestSuite) TestEventReplayAttack() {
@@ -180,9 +220,20 @@ func (suite *PrometheusTestSuite) TestEventReplayAttack() {
 	suite.Contains(data, `mtg_replay_attacks 1`)
 }
 
+func (suite *PrometheusTestSuite) TestEventPreviousSecretUsed() {
+	suite.prometheus.EventPreviousSecretUsed(mtglib.NewEventPreviousSecretUsed("connID"))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_previous_secret_handshakes 1`)
+}
+
 func (suite *PrometheusTestSuite) TestEventIPListSize() {
 	suite.prometheus.EventIPListSize(mtglib.NewEventIPListSize(10, false))
 	suite.prometheus.EventIPListSize(mtglib.NewEventIPListSize(3, true))
+	suite.prometheus.EventIPListSize(mtglib.NewEventIPBanlistSize(2))
 
 	time.Sleep(100 * time.Millisecond)
 
@@ -190,6 +241,154 @@ func (suite *PrometheusTestSuite) TestEventIPListSize() {
 	suite.NoError(err)
 	suite.Contains(data, `mtg_iplist_size{ip_list="allowlist"} 10`)
 	suite.Contains(data, `mtg_iplist_size{ip_list="blocklist"} 3`)
+	suite.Contains(data, `mtg_iplist_size{ip_list="banlist"} 2`)
+}
+
+func (suite *PrometheusTestSuite) TestEventTelegramProbe() {
+	suite.prometheus.EventTelegramProbe(mtglib.NewEventTelegramProbe(
+		net.ParseIP("10.0.0.10"), 2, 250*time.Millisecond, 0))
+	suite.prometheus.EventTelegramProbe(mtglib.NewEventTelegramProbe(
+		net.ParseIP("10.0.0.11"), 4, 0, 1))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_telegram_probe_rtt{dc="2",telegram_ip="10.0.0.10"} 0.25`)
+	suite.Contains(data, `mtg_telegram_probe_failure_rate{dc="2",telegram_ip="10.0.0.10"} 0`)
+	suite.Contains(data, `mtg_telegram_probe_failure_rate{dc="4",telegram_ip="10.0.0.11"} 1`)
+	suite.NotContains(data, `mtg_telegram_probe_rtt{dc="4"`)
+
+	forgotten := mtglib.NewEventTelegramProbe(net.ParseIP("10.0.0.10"), 2, 0, 0)
+	forgotten.Forgotten = true
+	suite.prometheus.EventTelegramProbe(forgotten)
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err = suite.Get()
+	suite.NoError(err)
+	suite.NotContains(data, `telegram_ip="10.0.0.10"`)
+	suite.Contains(data, `mtg_telegram_probe_failure_rate{dc="4",telegram_ip="10.0.0.11"} 1`)
+}
+
+func (suite *PrometheusTestSuite) TestEventWarmPool() {
+	suite.prometheus.EventWarmPool(mtglib.NewEventWarmPool("connID", 2, true))
+	suite.prometheus.EventWarmPool(mtglib.NewEventWarmPool("connID", 2, false))
+	suite.prometheus.EventWarmPool(mtglib.NewEventWarmPool("connID", 4, false))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_warm_pool_hits{dc="2"} 1`)
+	suite.Contains(data, `mtg_warm_pool_misses{dc="2"} 1`)
+	suite.Contains(data, `mtg_warm_pool_misses{dc="4"} 1`)
+}
+
+func (suite *PrometheusTestSuite) TestEventHandshakeFailed() {
+	suite.prometheus.EventHandshakeFailed(
+		mtglib.NewEventHandshakeFailed("connID", net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureBadHMAC))
+	suite.prometheus.EventHandshakeFailed(
+		mtglib.NewEventHandshakeFailed("connID2", net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureBadHMAC))
+	suite.prometheus.EventHandshakeFailed(
+		mtglib.NewEventHandshakeFailed("connID3", net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureTimeSkew))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_handshake_failures{reason="bad_hmac"} 2`)
+	suite.Contains(data, `mtg_handshake_failures{reason="time_skew"} 1`)
+}
+
+func (suite *PrometheusTestSuite) TestEventClockSkew() {
+	suite.prometheus.EventClockSkew(mtglib.NewEventClockSkew("connID", 2*time.Second, true))
+	suite.prometheus.EventClockSkew(mtglib.NewEventClockSkew("connID2", -time.Second, true))
+	suite.prometheus.EventClockSkew(mtglib.NewEventClockSkew("connID3", -time.Hour, false))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_client_clock_skew_count{result="accepted"} 2`)
+	suite.Contains(data, `mtg_client_clock_skew_sum{result="accepted"} 3`)
+	suite.Contains(data, `mtg_client_clock_skew_count{result="rejected"} 1`)
+	suite.Contains(data, `mtg_client_clock_skew_sum{result="rejected"} 3600`)
+}
+
+func (suite *PrometheusTestSuite) TestDurations() {
+	suite.prometheus.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	suite.prometheus.EventHandshakeSucceeded(
+		mtglib.NewEventHandshakeSucceeded("connID", 2*time.Second, 500*time.Millisecond))
+
+	connectedEvt := mtglib.NewEventConnectedToDC("connID", net.ParseIP("10.0.0.1"), 2)
+	connectedEvt.DialDuration = 250 * time.Millisecond
+	suite.prometheus.EventConnectedToDC(connectedEvt)
+
+	finishEvt := mtglib.NewEventFinish("connID")
+	finishEvt.Duration = time.Minute
+	suite.prometheus.EventFinish(finishEvt)
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_faketls_handshake_duration_sum 2`)
+	suite.Contains(data, `mtg_obfuscated_handshake_duration_sum 0.5`)
+	suite.Contains(data, `mtg_telegram_dial_duration_sum{dc="2",telegram_ip="10.0.0.1"} 0.25`)
+	suite.Contains(data, `mtg_session_duration_sum 60`)
+}
+
+func (suite *PrometheusTestSuite) TestDurationsUnknown() {
+	suite.prometheus.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	suite.prometheus.EventConnectedToDC(mtglib.NewEventConnectedToDC("connID", net.ParseIP("10.0.0.1"), 2))
+	suite.prometheus.EventFinish(mtglib.NewEventFinish("connID"))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.NotContains(data, `mtg_telegram_dial_duration_count`)
+	suite.Contains(data, `mtg_session_duration_count 0`)
+}
+
+func (suite *PrometheusTestSuite) TestEventOverflow() {
+	suite.prometheus.EventOverflow(mtglib.NewEventOverflow(2, 10))
+	suite.prometheus.EventOverflow(mtglib.NewEventOverflow(1, 0))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_events_overflow{action="dropped"} 3`)
+	suite.Contains(data, `mtg_events_overflow{action="coalesced"} 10`)
+}
+
+func (suite *PrometheusTestSuite) TestEventRateLimited() {
+	suite.prometheus.EventRateLimited(mtglib.NewEventRateLimited(net.ParseIP("10.0.0.10"), mtglib.RateLimitIPRate))
+	suite.prometheus.EventRateLimited(mtglib.NewEventRateLimited(net.ParseIP("10.0.0.11"), mtglib.RateLimitIPRate))
+	suite.prometheus.EventRateLimited(
+		mtglib.NewEventRateLimited(net.ParseIP("10.0.0.12"), mtglib.RateLimitSubnetConcurrency))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_rate_limited{reason="ip_rate"} 2`)
+	suite.Contains(data, `mtg_rate_limited{reason="subnet_concurrency"} 1`)
+}
+
+func (suite *PrometheusTestSuite) TestEventIPBanned() {
+	suite.prometheus.EventIPBanned(
+		mtglib.NewEventIPBanned(net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureNotTLS, time.Hour))
+	suite.prometheus.EventIPBanned(
+		mtglib.NewEventIPBanned(net.ParseIP("10.0.0.11"), mtglib.HandshakeFailureNotTLS, time.Hour))
+
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := suite.Get()
+	suite.NoError(err)
+	suite.Contains(data, `mtg_ip_bans{reason="not_tls"} 2`)
 }
 
 func TestPrometheus(t *testing.T) {
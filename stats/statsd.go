@@ -14,6 +14,7 @@ import (
 type statsdProcessor struct {
 	streams map[string]*streamInfo
 	client  *statsd.Client
+	asns    asnTags
 }
 
 func (s statsdProcessor) EventStart(evt mtglib.EventStart) {
@@ -33,6 +34,13 @@ func (s statsdProcessor) EventStart(evt mtglib.EventStart) {
 }
 
 func (s statsdProcessor) EventConnectedToDC(evt mtglib.EventConnectedToDC) {
+	if evt.DialDuration > 0 {
+		s.client.PrecisionTiming(MetricTelegramDialDuration,
+			evt.DialDuration,
+			statsd.StringTag(TagTelegramIP, evt.RemoteIP.String()),
+			statsd.IntTag(TagDC, evt.DC))
+	}
+
 	info, ok := s.streams[evt.StreamID()]
 	if !ok {
 		return
@@ -83,6 +91,10 @@ func (s statsdProcessor) EventTraffic(evt mtglib.EventTraffic) {
 }
 
 func (s statsdProcessor) EventFinish(evt mtglib.EventFinish) {
+	if evt.Duration > 0 {
+		s.client.PrecisionTiming(MetricSessionDuration, evt.Duration)
+	}
+
 	info, ok := s.streams[evt.StreamID()]
 	if !ok {
 		return
@@ -114,27 +126,90 @@ func (s statsdProcessor) EventConcurrencyLimited(_ mtglib.EventConcurrencyLimite
 }
 
 func (s statsdProcessor) EventIPBlocklisted(evt mtglib.EventIPBlocklisted) {
-	tag := TagIPListBlock
-	if !evt.IsBlockList {
-		tag = TagIPListAllow
+	tag := getIPList(evt.IsBlockList, evt.IsBanned)
+
+	tags := []statsd.Tag{statsd.StringTag(TagIPList, tag)}
+	if asn := s.asns.get(evt.ASN); asn != "" {
+		tags = append(tags, statsd.StringTag(TagASN, asn))
 	}
 
-	s.client.Incr(MetricIPBlocklisted, 1, statsd.StringTag(TagIPList, tag))
+	s.client.Incr(MetricIPBlocklisted, 1, tags...)
 }
 
 func (s statsdProcessor) EventReplayAttack(_ mtglib.EventReplayAttack) {
 	s.client.Incr(MetricReplayAttacks, 1)
 }
 
+func (s statsdProcessor) EventPreviousSecretUsed(_ mtglib.EventPreviousSecretUsed) {
+	s.client.Incr(MetricPreviousSecretHandshakes, 1)
+}
+
 func (s statsdProcessor) EventIPListSize(evt mtglib.EventIPListSize) {
-	tag := TagIPListBlock
-	if !evt.IsBlockList {
-		tag = TagIPListAllow
-	}
+	tag := getIPList(evt.IsBlockList, evt.IsBanList)
 
 	s.client.Gauge(MetricIPListSize, int64(evt.Size), statsd.StringTag(TagIPList, tag))
 }
 
+func (s statsdProcessor) EventTelegramProbe(evt mtglib.EventTelegramProbe) {
+	// statsd gauges cannot be removed, they just are not updated anymore.
+	if evt.Forgotten {
+		return
+	}
+
+	tags := []statsd.Tag{
+		statsd.StringTag(TagTelegramIP, evt.RemoteIP.String()),
+		statsd.IntTag(TagDC, evt.DC),
+	}
+
+	s.client.FGauge(MetricTelegramProbeFailureRate, evt.FailureRate, tags...)
+
+	if evt.RTT > 0 {
+		s.client.FGauge(MetricTelegramProbeRTT, evt.RTT.Seconds(), tags...)
+	}
+}
+
+func (s statsdProcessor) EventWarmPool(evt mtglib.EventWarmPool) {
+	metric := MetricWarmPoolMisses
+	if evt.Hit {
+		metric = MetricWarmPoolHits
+	}
+
+	s.client.Incr(metric, 1, statsd.IntTag(TagDC, evt.DC))
+}
+
+func (s statsdProcessor) EventHandshakeFailed(evt mtglib.EventHandshakeFailed) {
+	s.client.Incr(MetricHandshakeFailures, 1, statsd.StringTag(TagReason, string(evt.Reason)))
+}
+
+func (s statsdProcessor) EventClockSkew(evt mtglib.EventClockSkew) {
+	s.client.PrecisionTiming(MetricClientClockSkew,
+		evt.Skew.Abs(),
+		statsd.StringTag(TagResult, getClockSkewResult(evt.Accepted)))
+}
+
+func (s statsdProcessor) EventHandshakeSucceeded(evt mtglib.EventHandshakeSucceeded) {
+	s.client.PrecisionTiming(MetricFakeTLSHandshakeDuration, evt.FakeTLSDuration)
+	s.client.PrecisionTiming(MetricObfuscatedHandshakeDuration, evt.ObfuscatedDuration)
+}
+
+func (s statsdProcessor) EventOverflow(evt mtglib.EventOverflow) {
+	if evt.Dropped > 0 {
+		s.client.Incr(MetricEventsOverflow, int64(evt.Dropped), statsd.StringTag(TagAction, TagActionDropped))
+	}
+
+	if evt.Coalesced > 0 {
+		s.client.Incr(MetricEventsOverflow, int64(evt.Coalesced), statsd.StringTag(TagAction, TagActionCoalesced))
+	}
+}
+
+func (s statsdProcessor) EventRateLimited(evt mtglib.EventRateLimited) {
+	s.client.Incr(MetricRateLimited, 1, statsd.StringTag(TagReason, string(evt.Reason)))
+}
+
+func (s statsdProcessor) EventIPBanned(evt mtglib.EventIPBanned) {
+	s.client.Incr(MetricIPBans, 1, statsd.StringTag(TagReason, string(evt.Reason)))
+}
+
 func (s statsdProcessor) Shutdown() {
 	events := make([]mtglib.EventFinish, 0, len(s.streams))
 
@@ -156,6 +231,7 @@ func (s statsdProcessor) Shutdown() {
 // further by features of the chosen server.
 type StatsdFactory struct {
 	client *statsd.Client
+	asns   asnTags
 }
 
 // Close stops sending requests to statsd.
@@ -168,14 +244,18 @@ func (s StatsdFactory) Make() events.Observer {
 	return statsdProcessor{
 		client:  s.client,
 		streams: make(map[string]*streamInfo),
+		asns:    s.asns,
 	}
 }
 
 // NewStatsd builds an [events.ObserverFactory] that sends events to statsd.
 //
-// Valid tagFormats are 'datadog', 'influxdb' and 'graphite'.
+// Valid tagFormats are 'datadog', 'influxdb' and 'graphite'. asns are
+// autonomous systems which have their own values of 'asn' tag, others
+// are reported as [TagASNOther].
 func NewStatsd(address string, log logger.StdLikeLogger,
 	metricPrefix, tagFormat string,
+	asns []uint,
 ) (StatsdFactory, error) {
 	options := []statsd.Option{
 		statsd.MetricPrefix(metricPrefix),
@@ -195,5 +275,6 @@ func NewStatsd(address string, log logger.StdLikeLogger,
 
 	return StatsdFactory{
 		client: statsd.NewClient(address, options...),
+		asns:   newASNTags(asns),
 	}, nil
 }
@@ -89,7 +89,7 @@ func (suite *StatsdTestSuite) SetupTest() {
 	suite.statsdServer = statsdNewFakeServer()
 
 	factory, err := stats.NewStatsd(suite.statsdServer.Addr(),
-		logger.NewNoopLogger(), "mtg.", "datadog")
+		logger.NewNoopLogger(), "mtg.", "datadog", []uint{14061})
 	if err != nil {
 		panic(err)
 	}
@@ -197,6 +197,26 @@ func (suite *StatsdTestSuite) TestEventIPAllowlisted() {
 	suite.Equal("mtg.ip_blocklisted:1|c|#ip_list:allowlist", suite.statsdServer.String())
 }
 
+func (suite *StatsdTestSuite) TestEventIPBlocklistedASN() {
+	evt := mtglib.NewEventIPBlocklisted(net.ParseIP("10.0.0.10"))
+	evt.ASN = 14061
+
+	suite.statsd.EventIPBlocklisted(evt)
+
+	time.Sleep(statsdSleepTime)
+	suite.Equal("mtg.ip_blocklisted:1|c|#ip_list:blocklist,asn:14061", suite.statsdServer.String())
+}
+
+func (suite *StatsdTestSuite) TestEventIPBlocklistedOtherASN() {
+	evt := mtglib.NewEventIPBlocklisted(net.ParseIP("10.0.0.10"))
+	evt.ASN = 16509
+
+	suite.statsd.EventIPBlocklisted(evt)
+
+	time.Sleep(statsdSleepTime)
+	suite.Equal("mtg.ip_blocklisted:1|c|#ip_list:blocklist,asn:other", suite.statsdServer.String())
+}
+
 func (suite *StatsdTestSuite) TestEventReplayAttack() {
 	suite.statsd.EventReplayAttack(mtglib.NewEventReplayAttack("connID"))
 
@@ -204,6 +224,13 @@ func (suite *StatsdTestSuite) TestEventReplayAttack() {
 	suite.Equal("mtg.replay_attacks:1|c", suite.statsdServer.String())
 }
 
+func (suite *StatsdTestSuite) TestEventPreviousSecretUsed() {
+	suite.statsd.EventPreviousSecretUsed(mtglib.NewEventPreviousSecretUsed("connID"))
+
+	time.Sleep(statsdSleepTime)
+	suite.Equal("mtg.previous_secret_handshakes:1|c", suite.statsdServer.String())
+}
+
 func (suite *StatsdTestSuite) TestEventIPListSizeAllowlist() {
 	suite.statsd.EventIPListSize(mtglib.NewEventIPListSize(10, false))
 
@@ -220,6 +247,82 @@ func (suite *StatsdTestSuite) TestEventIPListSizeBlocklist() {
 	suite.Contains(suite.statsdServer.String(), "blocklist")
 }
 
+func (suite *StatsdTestSuite) TestEventTelegramProbe() {
+	suite.statsd.EventTelegramProbe(mtglib.NewEventTelegramProbe(
+		net.ParseIP("10.0.0.10"), 2, 250*time.Millisecond, 0.5))
+
+	time.Sleep(statsdSleepTime)
+	suite.Contains(suite.statsdServer.String(), "mtg.telegram_probe_rtt:0.25|g")
+	suite.Contains(suite.statsdServer.String(), "mtg.telegram_probe_failure_rate:0.5|g")
+	suite.Contains(suite.statsdServer.String(), "10.0.0.10")
+}
+
+func (suite *StatsdTestSuite) TestEventWarmPool() {
+	suite.statsd.EventWarmPool(mtglib.NewEventWarmPool("connID", 2, true))
+	suite.statsd.EventWarmPool(mtglib.NewEventWarmPool("connID", 2, false))
+
+	time.Sleep(statsdSleepTime)
+	suite.Contains(suite.statsdServer.String(), "mtg.warm_pool_hits:1|c")
+	suite.Contains(suite.statsdServer.String(), "mtg.warm_pool_misses:1|c")
+}
+
+func (suite *StatsdTestSuite) TestEventHandshakeFailed() {
+	suite.statsd.EventHandshakeFailed(
+		mtglib.NewEventHandshakeFailed("connID", net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureNotTLS))
+
+	time.Sleep(statsdSleepTime)
+	suite.Contains(suite.statsdServer.String(), "mtg.handshake_failures:1|c|#reason:not_tls")
+}
+
+func (suite *StatsdTestSuite) TestEventClockSkew() {
+	suite.statsd.EventClockSkew(mtglib.NewEventClockSkew("connID", -2*time.Second, false))
+
+	time.Sleep(statsdSleepTime)
+	suite.Contains(suite.statsdServer.String(), "mtg.client_clock_skew:2000|ms|#result:rejected")
+}
+
+func (suite *StatsdTestSuite) TestDurations() {
+	suite.statsd.EventStart(mtglib.NewEventStart("connID", net.ParseIP("10.0.0.10")))
+	suite.statsd.EventHandshakeSucceeded(
+		mtglib.NewEventHandshakeSucceeded("connID", 2*time.Second, 500*time.Millisecond))
+
+	connectedEvt := mtglib.NewEventConnectedToDC("connID", net.ParseIP("10.0.0.1"), 2)
+	connectedEvt.DialDuration = 250 * time.Millisecond
+	suite.statsd.EventConnectedToDC(connectedEvt)
+
+	finishEvt := mtglib.NewEventFinish("connID")
+	finishEvt.Duration = time.Minute
+	suite.statsd.EventFinish(finishEvt)
+
+	time.Sleep(statsdSleepTime)
+	suite.Contains(suite.statsdServer.String(), "mtg.faketls_handshake_duration:2000|ms")
+	suite.Contains(suite.statsdServer.String(), "mtg.obfuscated_handshake_duration:500|ms")
+	suite.Contains(suite.statsdServer.String(), "mtg.telegram_dial_duration:250|ms|#telegram_ip:10.0.0.1,dc:2")
+	suite.Contains(suite.statsdServer.String(), "mtg.session_duration:60000|ms")
+}
+
+func (suite *StatsdTestSuite) TestEventOverflow() {
+	suite.statsd.EventOverflow(mtglib.NewEventOverflow(0, 10))
+
+	time.Sleep(statsdSleepTime)
+	suite.Contains(suite.statsdServer.String(), "mtg.events_overflow:10|c|#action:coalesced")
+	suite.NotContains(suite.statsdServer.String(), "action:dropped")
+}
+
+func (suite *StatsdTestSuite) TestEventRateLimited() {
+	suite.statsd.EventRateLimited(mtglib.NewEventRateLimited(net.ParseIP("10.0.0.10"), mtglib.RateLimitIPRate))
+
+	time.Sleep(statsdSleepTime)
+	suite.Contains(suite.statsdServer.String(), "mtg.rate_limited:1|c|#reason:ip_rate")
+}
+
+func (suite *StatsdTestSuite) TestEventIPBanned() {
+	suite.statsd.EventIPBanned(mtglib.NewEventIPBanned(net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureReplay, time.Hour))
+
+	time.Sleep(statsdSleepTime)
+	suite.Contains(suite.statsdServer.String(), "mtg.ip_bans:1|c|#reason:replay")
+}
+
 func TestStatsd(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, &StatsdTestSuite{})
@@ -1,6 +1,10 @@
 package stats
 
-import statsd "github.com/smira/go-statsd"
+import (
+	"strconv"
+
+	statsd "github.com/smira/go-statsd"
+)
 
 type streamInfo struct {
 	isDomainFronted bool
@@ -26,3 +30,52 @@ func getDirection(isRead bool) string {
 
 	return TagDirectionFromClient
 }
+
+func getClockSkewResult(accepted bool) string {
+	if accepted {
+		return TagResultAccepted
+	}
+
+	return TagResultRejected
+}
+
+// getIPList returns a value of 'ip_list' tag.
+func getIPList(isBlockList, isBanList bool) string {
+	switch {
+	case isBanList:
+		return TagIPListBan
+	case isBlockList:
+		return TagIPListBlock
+	}
+
+	return TagIPListAllow
+}
+
+// asnTags maps autonomous systems to values of 'asn' tag. Client IPs
+// come from arbitrary networks, so only autonomous systems which are
+// given explicitly get their own values. Otherwise, a number of series
+// is unbounded.
+type asnTags map[uint]string
+
+func newASNTags(asns []uint) asnTags {
+	rv := make(asnTags, len(asns))
+
+	for _, v := range asns {
+		rv[v] = strconv.FormatUint(uint64(v), 10)
+	}
+
+	return rv
+}
+
+// get returns an empty string if autonomous system is unknown.
+func (a asnTags) get(asn uint) string {
+	if asn == 0 {
+		return ""
+	}
+
+	if value, ok := a[asn]; ok {
+		return value
+	}
+
+	return TagASNOther
+}
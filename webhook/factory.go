@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/9seconds/mtg/v2/events"
+)
+
+// Thresholds defines when alerts are fired. Zero value disables an
+// alert.
+type Thresholds struct {
+	// ReplayAttacks is a number of detected replay attacks within a
+	// window.
+	ReplayAttacks uint
+
+	// IPBlocklisted is a number of connections from blocklisted IPs
+	// within a window.
+	IPBlocklisted uint
+
+	// ConcurrencyLimited is a number of connections rejected because of
+	// concurrency limit within a window.
+	ConcurrencyLimited uint
+
+	// HandshakeFailures is a number of failed client handshakes within
+	// a window. Active probing of the proxy usually looks like a burst
+	// of these failures.
+	HandshakeFailures uint
+
+	// BlocklistEmpty fires an alert if blocklist has no addresses after
+	// update. Usually it means that lists cannot be downloaded.
+	BlocklistEmpty bool
+}
+
+// Opts defines a set of options for [NewFactory]. Only URLs are
+// mandatory.
+type Opts struct {
+	// URLs is a list of endpoints. Each batch of alerts is sent to all
+	// of them.
+	//
+	// This is a mandatory setting.
+	URLs []string
+
+	// Thresholds defines when alerts are fired.
+	Thresholds Thresholds
+
+	// Window is a size of window where events are counted.
+	//
+	// This is an optional setting, [DefaultWindow] is used by default.
+	Window time.Duration
+
+	// Cooldown is a time when the same alert is not repeated.
+	//
+	// This is an optional setting, [DefaultCooldown] is used by
+	// default.
+	Cooldown time.Duration
+
+	// BatchEach is an interval of sending collected alerts.
+	//
+	// This is an optional setting, [DefaultBatchEach] is used by
+	// default.
+	BatchEach time.Duration
+
+	// HTTPClient is used to send requests.
+	//
+	// This is an optional setting, a client with [DefaultTimeout] is
+	// used by default.
+	HTTPClient *http.Client
+
+	// Logger is used to report failed requests.
+	//
+	// This is an optional setting, errors are ignored by default.
+	Logger Logger
+}
+
+func (o Opts) getWindow() time.Duration {
+	if o.Window == 0 {
+		return DefaultWindow
+	}
+
+	return o.Window
+}
+
+func (o Opts) getCooldown() time.Duration {
+	if o.Cooldown == 0 {
+		return DefaultCooldown
+	}
+
+	return o.Cooldown
+}
+
+func (o Opts) getBatchEach() time.Duration {
+	if o.BatchEach == 0 {
+		return DefaultBatchEach
+	}
+
+	return o.BatchEach
+}
+
+func (o Opts) getHTTPClient() *http.Client {
+	if o.HTTPClient == nil {
+		return &http.Client{
+			Timeout: DefaultTimeout,
+		}
+	}
+
+	return o.HTTPClient
+}
+
+func (o Opts) getLogger() Logger {
+	if o.Logger == nil {
+		return noopLogger{}
+	}
+
+	return o.Logger
+}
+
+type noopLogger struct{}
+
+func (n noopLogger) WarningError(_ string, _ error) {}
+
+// Factory is a factory of [events.Observer] which send alerts to
+// webhooks.
+type Factory struct {
+	tracker *tracker
+	sender  *sender
+}
+
+// Make builds a new observer.
+func (f Factory) Make() events.Observer {
+	return observer{
+		tracker: f.tracker,
+	}
+}
+
+// Close sends alerts which are not sent yet and stops a factory.
+func (f Factory) Close() error {
+	f.sender.Close()
+
+	return nil
+}
+
+// NewFactory builds an [events.ObserverFactory] that sends alerts to
+// webhooks.
+func NewFactory(opts Opts) (Factory, error) {
+	if len(opts.URLs) == 0 {
+		return Factory{}, ErrNoURLs
+	}
+
+	tracker := newTracker(opts.Thresholds, opts.getWindow(), opts.getCooldown())
+	sender := newSender(tracker, opts.URLs, opts.getHTTPClient(), opts.getLogger())
+
+	go sender.run(opts.getBatchEach())
+
+	return Factory{
+		tracker: tracker,
+		sender:  sender,
+	}, nil
+}
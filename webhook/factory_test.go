@@ -0,0 +1,179 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/9seconds/mtg/v2/mtglib"
+	"github.com/9seconds/mtg/v2/webhook"
+	"github.com/stretchr/testify/suite"
+)
+
+type webhookPayload struct {
+	Text   string          `json:"text"`
+	Alerts []webhook.Alert `json:"alerts"`
+}
+
+type FactoryTestSuite struct {
+	suite.Suite
+
+	mutex    sync.Mutex
+	payloads []webhookPayload
+	server   *httptest.Server
+}
+
+func (suite *FactoryTestSuite) SetupTest() {
+	suite.payloads = nil
+	suite.server = httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		value := webhookPayload{}
+
+		suite.Equal(http.MethodPost, r.Method)
+		suite.Equal("application/json", r.Header.Get("Content-Type"))
+		suite.NoError(json.NewDecoder(r.Body).Decode(&value))
+
+		suite.mutex.Lock()
+		suite.payloads = append(suite.payloads, value)
+		suite.mutex.Unlock()
+	}))
+}
+
+func (suite *FactoryTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *FactoryTestSuite) Make(thresholds webhook.Thresholds) webhook.Factory {
+	factory, err := webhook.NewFactory(webhook.Opts{
+		URLs:       []string{suite.server.URL},
+		Thresholds: thresholds,
+		BatchEach:  time.Hour,
+	})
+	suite.Require().NoError(err)
+
+	return factory
+}
+
+func (suite *FactoryTestSuite) Payloads() []webhookPayload {
+	suite.mutex.Lock()
+	defer suite.mutex.Unlock()
+
+	return suite.payloads
+}
+
+func (suite *FactoryTestSuite) TestNoURLs() {
+	_, err := webhook.NewFactory(webhook.Opts{})
+	suite.ErrorIs(err, webhook.ErrNoURLs)
+}
+
+func (suite *FactoryTestSuite) TestNothingFired() {
+	factory := suite.Make(webhook.Thresholds{
+		ReplayAttacks: 3,
+	})
+	observer := factory.Make()
+
+	observer.EventReplayAttack(mtglib.NewEventReplayAttack("connID"))
+	observer.EventReplayAttack(mtglib.NewEventReplayAttack("connID"))
+	observer.EventConcurrencyLimited(mtglib.NewEventConcurrencyLimited())
+	observer.EventIPListSize(mtglib.NewEventIPListSize(0, true))
+
+	suite.NoError(factory.Close())
+	suite.Empty(suite.Payloads())
+}
+
+func (suite *FactoryTestSuite) TestBatch() {
+	factory := suite.Make(webhook.Thresholds{
+		ReplayAttacks:  2,
+		IPBlocklisted:  1,
+		BlocklistEmpty: true,
+	})
+	observer1 := factory.Make()
+	observer2 := factory.Make()
+
+	observer1.EventReplayAttack(mtglib.NewEventReplayAttack("connID1"))
+	observer2.EventReplayAttack(mtglib.NewEventReplayAttack("connID2"))
+	observer1.EventReplayAttack(mtglib.NewEventReplayAttack("connID3"))
+	observer2.EventIPBlocklisted(mtglib.NewEventIPAllowlisted(net.ParseIP("10.0.0.10")))
+	observer2.EventIPListSize(mtglib.NewEventIPListSize(10, true))
+	observer1.EventIPListSize(mtglib.NewEventIPListSize(0, true))
+
+	suite.NoError(factory.Close())
+
+	payloads := suite.Payloads()
+	suite.Require().Len(payloads, 1)
+	suite.Require().Len(payloads[0].Alerts, 2)
+	suite.Contains(payloads[0].Text, "3 replay attacks")
+
+	alert := payloads[0].Alerts[0]
+	suite.Equal(webhook.AlertReplayAttacks, alert.Name)
+	suite.EqualValues(3, alert.Count)
+	suite.EqualValues(2, alert.Threshold)
+	suite.EqualValues(60, alert.Window)
+	suite.NotEmpty(alert.Since)
+
+	alert = payloads[0].Alerts[1]
+	suite.Equal(webhook.AlertBlocklistEmpty, alert.Name)
+	suite.EqualValues(1, alert.Count)
+}
+
+func (suite *FactoryTestSuite) TestCooldown() {
+	factory, err := webhook.NewFactory(webhook.Opts{
+		URLs: []string{suite.server.URL},
+		Thresholds: webhook.Thresholds{
+			HandshakeFailures: 1,
+		},
+		BatchEach: 50 * time.Millisecond,
+	})
+	suite.Require().NoError(err)
+
+	observer := factory.Make()
+	evt := mtglib.NewEventHandshakeFailed("connID", net.ParseIP("10.0.0.10"), mtglib.HandshakeFailureNotTLS)
+
+	observer.EventHandshakeFailed(evt)
+	suite.Eventually(func() bool {
+		return len(suite.Payloads()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	observer.EventHandshakeFailed(evt)
+	suite.NoError(factory.Close())
+	suite.Len(suite.Payloads(), 1)
+}
+
+func (suite *FactoryTestSuite) TestBadResponse() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	logger := &loggerMock{}
+	factory, err := webhook.NewFactory(webhook.Opts{
+		URLs: []string{server.URL, suite.server.URL},
+		Thresholds: webhook.Thresholds{
+			ConcurrencyLimited: 1,
+		},
+		Logger: logger,
+	})
+	suite.Require().NoError(err)
+
+	factory.Make().EventConcurrencyLimited(mtglib.NewEventConcurrencyLimited())
+	suite.NoError(factory.Close())
+
+	suite.ErrorIs(logger.err, webhook.ErrBadResponse)
+	suite.Len(suite.Payloads(), 1)
+}
+
+type loggerMock struct {
+	err error
+}
+
+func (l *loggerMock) WarningError(_ string, err error) {
+	l.err = err
+}
+
+func TestFactory(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, &FactoryTestSuite{})
+}
@@ -0,0 +1,74 @@
+// Webhook package has an implementation of [events.Observer] which
+// sends alerts to HTTP endpoints when security-relevant events cross
+// thresholds: replay attacks, clients from blocklisted IPs, concurrency
+// limiting, failed handshakes or an empty blocklist after update.
+//
+// Events are counted in fixed windows. Once a counter reaches a
+// threshold, an alert is fired. The same alert is not repeated until
+// cooldown is over. Alerts are batched: all alerts fired within a
+// batch interval are sent by a single POST request with JSON payload
+// to each endpoint:
+//
+//	{
+//	  "text": "mtg: 12 replay attacks in 1m0s (threshold is 10)",
+//	  "alerts": [
+//	    {
+//	      "name": "replay_attacks",
+//	      "count": 12,
+//	      "threshold": 10,
+//	      "window": 60,
+//	      "since": "2026-01-01T10:00:00Z",
+//	      "message": "12 replay attacks in 1m0s (threshold is 10)"
+//	    }
+//	  ]
+//	}
+//
+// A text field makes payload compatible with incoming webhooks of Slack
+// and Mattermost.
+package webhook
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultWindow defines a default size of window where events are
+	// counted.
+	DefaultWindow = time.Minute
+
+	// DefaultCooldown defines a default time when the same alert is not
+	// repeated.
+	DefaultCooldown = 10 * time.Minute
+
+	// DefaultBatchEach defines a default interval of sending collected
+	// alerts.
+	DefaultBatchEach = 10 * time.Second
+
+	// DefaultTimeout defines a default timeout of HTTP request to the
+	// endpoint.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Names of alerts.
+const (
+	AlertReplayAttacks      = "replay_attacks"
+	AlertIPBlocklisted      = "ip_blocklisted"
+	AlertConcurrencyLimited = "concurrency_limited"
+	AlertHandshakeFailures  = "handshake_failures"
+	AlertBlocklistEmpty     = "blocklist_empty"
+)
+
+var (
+	// ErrNoURLs is returned if no endpoints are given.
+	ErrNoURLs = errors.New("at least one url has to be set")
+
+	// ErrBadResponse is returned if endpoint has responded with an
+	// error status.
+	ErrBadResponse = errors.New("bad response")
+)
+
+// Logger defines a logger which is used to report failed requests.
+type Logger interface {
+	WarningError(msg string, err error)
+}
@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"github.com/9seconds/mtg/v2/mtglib"
+)
+
+type observer struct {
+	tracker *tracker
+}
+
+func (o observer) EventReplayAttack(_ mtglib.EventReplayAttack) {
+	o.tracker.inc(AlertReplayAttacks)
+}
+
+func (o observer) EventIPBlocklisted(evt mtglib.EventIPBlocklisted) {
+	if evt.IsBlockList {
+		o.tracker.inc(AlertIPBlocklisted)
+	}
+}
+
+func (o observer) EventConcurrencyLimited(_ mtglib.EventConcurrencyLimited) {
+	o.tracker.inc(AlertConcurrencyLimited)
+}
+
+func (o observer) EventHandshakeFailed(_ mtglib.EventHandshakeFailed) {
+	o.tracker.inc(AlertHandshakeFailures)
+}
+
+func (o observer) EventIPListSize(evt mtglib.EventIPListSize) {
+	if evt.IsBlockList && evt.Size == 0 {
+		o.tracker.fire(AlertBlocklistEmpty, "blocklist is empty after update")
+	}
+}
+
+func (o observer) EventStart(_ mtglib.EventStart)                           {}
+func (o observer) EventConnectedToDC(_ mtglib.EventConnectedToDC)           {}
+func (o observer) EventTraffic(_ mtglib.EventTraffic)                       {}
+func (o observer) EventFinish(_ mtglib.EventFinish)                         {}
+func (o observer) EventDomainFronting(_ mtglib.EventDomainFronting)         {}
+func (o observer) EventPreviousSecretUsed(_ mtglib.EventPreviousSecretUsed) {}
+func (o observer) EventTelegramProbe(_ mtglib.EventTelegramProbe)           {}
+func (o observer) EventWarmPool(_ mtglib.EventWarmPool)                     {}
+func (o observer) EventClockSkew(_ mtglib.EventClockSkew)                   {}
+func (o observer) EventHandshakeSucceeded(_ mtglib.EventHandshakeSucceeded) {}
+func (o observer) EventOverflow(_ mtglib.EventOverflow)                     {}
+func (o observer) EventRateLimited(_ mtglib.EventRateLimited)               {}
+func (o observer) EventIPBanned(_ mtglib.EventIPBanned)                     {}
+func (o observer) Shutdown()                                                {}
@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type payload struct {
+	Text   string  `json:"text"`
+	Alerts []Alert `json:"alerts"`
+}
+
+// sender periodically takes fired alerts and posts them to endpoints.
+type sender struct {
+	tracker    *tracker
+	urls       []string
+	httpClient *http.Client
+	logger     Logger
+
+	ctx       context.Context //nolint: containedctx
+	ctxCancel context.CancelFunc
+	done      chan struct{}
+}
+
+func (s *sender) run(batchEach time.Duration) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(batchEach)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			// alerts fired right before shutdown are still interesting
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+			s.flush(ctx) //nolint: contextcheck
+
+			cancel()
+
+			return
+		case <-ticker.C:
+			s.flush(s.ctx)
+		}
+	}
+}
+
+func (s *sender) flush(ctx context.Context) {
+	alerts := s.tracker.take()
+	if len(alerts) == 0 {
+		return
+	}
+
+	messages := make([]string, len(alerts))
+	for i := range alerts {
+		messages[i] = alerts[i].Message
+	}
+
+	// payload has only primitive types so it is always encoded
+	body, _ := json.Marshal(payload{ //nolint: errchkjson
+		Text:   "mtg: " + strings.Join(messages, "; "),
+		Alerts: alerts,
+	})
+
+	for _, url := range s.urls {
+		if err := s.post(ctx, url, body); err != nil {
+			s.logger.WarningError("cannot send alerts", err)
+		}
+	}
+}
+
+func (s *sender) post(ctx context.Context, url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot build a request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot send a request to %s: %w", req.URL.Host, err)
+	}
+
+	defer resp.Body.Close() //nolint: errcheck
+
+	io.Copy(io.Discard, resp.Body) //nolint: errcheck
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: %s returned %d", ErrBadResponse, req.URL.Host, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *sender) Close() {
+	s.ctxCancel()
+	<-s.done
+}
+
+func newSender(tracker *tracker, urls []string, httpClient *http.Client, logger Logger) *sender {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &sender{
+		tracker:    tracker,
+		urls:       urls,
+		httpClient: httpClient,
+		logger:     logger,
+		ctx:        ctx,
+		ctxCancel:  cancel,
+		done:       make(chan struct{}),
+	}
+}
@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Alert is a single alert sent to endpoints.
+type Alert struct {
+	Name      string `json:"name"`
+	Count     uint   `json:"count"`
+	Threshold uint   `json:"threshold,omitempty"`
+	Window    uint   `json:"window,omitempty"`
+	Since     string `json:"since"`
+	Message   string `json:"message"`
+}
+
+var descriptions = map[string]string{ //nolint: gochecknoglobals
+	AlertReplayAttacks:      "replay attacks",
+	AlertIPBlocklisted:      "connections from blocklisted IPs",
+	AlertConcurrencyLimited: "connections rejected by concurrency limit",
+	AlertHandshakeFailures:  "failed handshakes",
+}
+
+type counter struct {
+	threshold   uint
+	count       uint
+	windowStart time.Time
+	firedAt     time.Time
+}
+
+// tracker counts events of all observers and fires alerts. Alerts are
+// kept until they are taken by a sender. If an alert is not taken yet,
+// its count is updated.
+type tracker struct {
+	mutex    sync.Mutex
+	window   time.Duration
+	cooldown time.Duration
+	counters map[string]*counter
+	fired    map[string]*Alert
+	order    []string
+}
+
+func (t *tracker) inc(name string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	value, ok := t.counters[name]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+
+	if now.Sub(value.windowStart) >= t.window {
+		value.windowStart = now
+		value.count = 0
+	}
+
+	value.count++
+
+	if alert, ok := t.fired[name]; ok {
+		if alert.Since == formatTime(value.windowStart) {
+			alert.Count = value.count
+			alert.Message = t.message(name, value.count, value.threshold)
+		}
+
+		return
+	}
+
+	if value.count < value.threshold || t.inCooldown(value.firedAt, now) {
+		return
+	}
+
+	value.firedAt = now
+
+	t.add(&Alert{
+		Name:      name,
+		Count:     value.count,
+		Threshold: value.threshold,
+		Window:    uint(t.window.Seconds()),
+		Since:     formatTime(value.windowStart),
+		Message:   t.message(name, value.count, value.threshold),
+	})
+}
+
+// fire fires an alert which does not depend on a counter.
+func (t *tracker) fire(name, message string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	value, ok := t.counters[name]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+
+	if _, ok := t.fired[name]; ok || t.inCooldown(value.firedAt, now) {
+		return
+	}
+
+	value.firedAt = now
+
+	t.add(&Alert{
+		Name:    name,
+		Count:   1,
+		Since:   formatTime(now),
+		Message: message,
+	})
+}
+
+func (t *tracker) take() []Alert {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.order) == 0 {
+		return nil
+	}
+
+	rv := make([]Alert, 0, len(t.order))
+
+	for _, name := range t.order {
+		rv = append(rv, *t.fired[name])
+	}
+
+	clear(t.fired)
+	t.order = t.order[:0]
+
+	return rv
+}
+
+func (t *tracker) add(alert *Alert) {
+	t.fired[alert.Name] = alert
+	t.order = append(t.order, alert.Name)
+}
+
+func (t *tracker) inCooldown(firedAt, now time.Time) bool {
+	return !firedAt.IsZero() && now.Sub(firedAt) < t.cooldown
+}
+
+func (t *tracker) message(name string, count, threshold uint) string {
+	return fmt.Sprintf("%d %s in %v (threshold is %d)",
+		count, descriptions[name], t.window, threshold)
+}
+
+func formatTime(value time.Time) string {
+	return value.UTC().Format(time.RFC3339)
+}
+
+func newTracker(thresholds Thresholds, window, cooldown time.Duration) *tracker {
+	rv := &tracker{
+		window:   window,
+		cooldown: cooldown,
+		counters: map[string]*counter{},
+		fired:    map[string]*Alert{},
+	}
+
+	for name, threshold := range map[string]uint{
+		AlertReplayAttacks:      thresholds.ReplayAttacks,
+		AlertIPBlocklisted:      thresholds.IPBlocklisted,
+		AlertConcurrencyLimited: thresholds.ConcurrencyLimited,
+		AlertHandshakeFailures:  thresholds.HandshakeFailures,
+	} {
+		if threshold > 0 {
+			rv.counters[name] = &counter{
+				threshold: threshold,
+			}
+		}
+	}
+
+	if thresholds.BlocklistEmpty {
+		rv.counters[AlertBlocklistEmpty] = &counter{}
+	}
+
+	return rv
+}